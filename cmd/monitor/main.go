@@ -1,7 +1,12 @@
-package main
-
-import "game-monitor/pkg/ui"
-
-func main() {
-	ui.Run()
-}
+package main
+
+import (
+	"game-monitor/pkg/ui"
+
+	// Embedded zone database so the display time zone option works on Windows
+	_ "time/tzdata"
+)
+
+func main() {
+	ui.Run()
+}
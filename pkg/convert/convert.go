@@ -0,0 +1,188 @@
+// Package convert turns raw game logs into kill/death feeds after the fact,
+// the way the live monitor would have reported them.
+package convert
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"game-monitor/pkg/processor"
+)
+
+var (
+	nicknameRegex = regexp.MustCompile(`nickname="([^"]+)"`)
+	playerRegex   = regexp.MustCompile(`Player\[([^\]]+)\]`)
+	logDateRegex  = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+)
+
+// LogDateFromName returns the YYYY-MM-DD date found in a log's file name,
+// or today's date when the name has none.
+func LogDateFromName(fileName string) string {
+	if date := logDateRegex.FindString(fileName); date != "" {
+		return date
+	}
+	return time.Now().Format("2006-01-02")
+}
+
+// DetectLogPlayer finds the player a log belongs to. The first line naming
+// them wins, trying nickname="…", then Player[…], then the legacy
+// "Player name:" form. Failing that the file name prefix is used unless it's
+// a generic word, and "Unknown" after that. Spaces become underscores.
+func DetectLogPlayer(r io.Reader, fileName string) string {
+	playerName := "Unknown"
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := nicknameRegex.FindStringSubmatch(line); len(matches) > 1 {
+			playerName = matches[1]
+			break
+		}
+		if matches := playerRegex.FindStringSubmatch(line); len(matches) > 1 {
+			playerName = matches[1]
+			break
+		}
+		if strings.Contains(line, "Player name:") {
+			playerName = strings.TrimSpace(strings.SplitN(line, ":", 2)[1])
+			break
+		}
+	}
+	// Only use filename extraction as a last resort if no player name found in log content
+	if playerName == "Unknown" {
+		if idx := strings.IndexAny(fileName, " _"); idx > 0 {
+			possibleName := fileName[:idx]
+			if possibleName != "Game" && possibleName != "Log" && possibleName != "StarCitizen" {
+				playerName = possibleName
+			}
+		}
+	}
+	playerName = strings.ReplaceAll(playerName, " ", "_")
+	if playerName == "" {
+		playerName = "Unknown"
+	}
+	return playerName
+}
+
+// IsConvertibleLog reports whether a file name looks like a raw game log:
+// .log or .txt, optionally gzipped.
+func IsConvertibleLog(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+	return strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".txt")
+}
+
+// OpenLogReader rewinds file and returns a reader over its log text, along
+// with a CountingReader counting the raw bytes read from the file. Gzipped
+// files are decompressed.
+func OpenLogReader(file *os.File, gzipped bool) (io.Reader, *CountingReader, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	counter := &CountingReader{r: file}
+	if !gzipped {
+		return counter, counter, nil
+	}
+	zr, err := gzip.NewReader(counter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return zr, counter, nil
+}
+
+// CountingReader counts the bytes read through it.
+type CountingReader struct {
+	r    io.Reader
+	read int64
+}
+
+func (c *CountingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.read += int64(n)
+	return n, err
+}
+
+// Count returns the number of bytes read so far.
+func (c *CountingReader) Count() int64 {
+	return c.read
+}
+
+// progressStep is how many bytes are read between progress reports.
+const progressStep = 1 << 20
+
+// Line is one feed line produced from a log.
+type Line struct {
+	Text string
+	// Time is when the line happened: its own timestamp, or else the last
+	// one read from the log before it. Zero for lines before the log's
+	// first timestamp.
+	Time time.Time
+}
+
+// ParseLog runs every line of a log through an offline processor with player
+// as the active player and returns the resulting feed lines. Saved stats and
+// the current session are left alone. onProgress, if set, receives the bytes
+// read so far about every megabyte. Parsing stops with ctx.Err() when ctx is
+// cancelled.
+func ParseLog(ctx context.Context, r io.Reader, player string, onProgress func(read int64)) ([]Line, error) {
+	var feed []Line
+	var lastLogTime time.Time // latest timestamp seen in the log so far
+	proc := processor.New(nil, nil)
+	proc.PlayerName = player
+	proc.Offline = true
+	proc.AppendOutput = func(line string, logTime ...time.Time) {
+		if line == "" || line == "PlayerName is empty, skipping stats update for line" {
+			return
+		}
+		// Drop 'Player appeared' lines for the player's own character
+		if strings.HasPrefix(line, "Player appeared:") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) > 1 {
+				appearedName := strings.TrimSpace(parts[1])
+				if strings.EqualFold(strings.ReplaceAll(appearedName, " ", "_"), strings.ReplaceAll(player, " ", "_")) {
+					return
+				}
+			}
+		}
+		at := lastLogTime
+		if len(logTime) > 0 && !logTime[0].IsZero() {
+			at = logTime[0]
+		}
+		feed = append(feed, Line{Text: line, Time: at})
+	}
+
+	counter := &CountingReader{r: r}
+	reader := bufio.NewReaderSize(counter, 64*1024)
+	var reported int64
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" || err == nil {
+			line = strings.TrimSuffix(line, "\n")
+			if t, ok := processor.ExtractLogTimestamp(line); ok {
+				lastLogTime = t
+			}
+			proc.ProcessLogLine(line)
+		}
+		if counter.read-reported >= progressStep {
+			reported = counter.read
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if onProgress != nil {
+				onProgress(counter.read)
+			}
+		}
+		if err == io.EOF {
+			// Events still inside the aggregation window at the end of the log
+			proc.FlushPending()
+			return feed, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
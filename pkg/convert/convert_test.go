@@ -0,0 +1,199 @@
+package convert
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"game-monitor/pkg/stats"
+)
+
+func TestLogDateFromName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Game Build(9876543) 2025-03-04 10-11-12.log", "2025-03-04"},
+		{"Me_2024-12-31.txt", "2024-12-31"},
+		{"Game.log", time.Now().Format("2006-01-02")},
+	}
+	for _, tt := range tests {
+		if got := LogDateFromName(tt.name); got != tt.want {
+			t.Errorf("LogDateFromName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetectLogPlayer(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      string
+		fileName string
+		want     string
+	}{
+		{"nickname", "<2025-01-01T10:00:00Z> noise\n<2025-01-01T10:00:01Z> Login nickname=\"Pilot_One\" playerGEID=1\n", "Game.log", "Pilot_One"},
+		{"player tag", "Requesting inventory for Player[Pilot.Two]\n", "Game.log", "Pilot.Two"},
+		{"first line wins", "Player[First]\nnickname=\"Second\"\n", "Game.log", "First"},
+		{"legacy form", "Player name: Old Timer\n", "Game.log", "Old_Timer"},
+		{"file name prefix", "nothing useful\n", "Someone_2025-01-01.log", "Someone"},
+		{"generic file name", "nothing useful\n", "Game_2025-01-01.log", "Unknown"},
+		{"no name at all", "", "Game.log", "Unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLogPlayer(strings.NewReader(tt.log), tt.fileName); got != tt.want {
+				t.Errorf("DetectLogPlayer = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConvertibleLog(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Game.log", true},
+		{"old.TXT", true},
+		{"Game Build 2025-01-01.log.gz", true},
+		{"Me_2025-01-01.jsonl", false},
+		{"archive.gz", false},
+		{"notes.md", false},
+	}
+	for _, tt := range tests {
+		if got := IsConvertibleLog(tt.name); got != tt.want {
+			t.Errorf("IsConvertibleLog(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	stats.SetDir(t.TempDir())
+	defer stats.SetDir("")
+	at := func(sec int) time.Time { return time.Date(2025, 1, 2, 10, 0, sec, 0, time.UTC) }
+	tests := []struct {
+		name string
+		log  string
+		want []Line
+	}{
+		{
+			name: "kill with weapon",
+			log:  "<2025-01-02T10:00:05.000Z> [Notice] <Actor Death> CActor::Kill: 'Victim_1' [123] in zone 'x' killed by 'Me' [456] using 'klwe_rifle_energy_01_1234' [Class x] with damage type 'Bullet'\n",
+			want: []Line{{"You killed: Victim_1 using Klaus & Werner Gallant Rifle", at(5)}},
+		},
+		{
+			name: "other players' kills are skipped",
+			log:  "<2025-01-02T10:00:05.000Z> CActor::Kill: 'Victim_1' [1] killed by 'Someone' [2] using 'gun'\n",
+			want: nil,
+		},
+		{
+			name: "death at the end of the log is flushed",
+			log:  "<2025-01-02T10:00:07.000Z> CActor::Kill: 'Me' [1] killed by 'Enemy_1' [2] using 'gun' with damage type 'Bullet'\n",
+			want: []Line{{"You were killed by: Enemy_1 using gun", at(7)}},
+		},
+		{
+			name: "last line without a newline",
+			log:  "<2025-01-02T10:00:05.000Z> noise\n<2025-01-02T10:00:06.000Z> CActor::Kill: 'Victim_2' [1] killed by 'Me' [2]",
+			want: []Line{{"You killed: Victim_2", at(6)}},
+		},
+		{
+			name: "untimestamped line takes the last log time",
+			log:  "<2025-01-02T10:00:03.000Z> noise\nCActor::Kill: 'Victim_3' [1] killed by 'Me' [2]\n",
+			want: []Line{{"You killed: Victim_3", at(3)}},
+		},
+		{
+			name: "untimestamped line before any timestamp has none",
+			log:  "CActor::Kill: 'Victim_3' [1] killed by 'Me' [2]\n",
+			want: []Line{{"You killed: Victim_3", time.Time{}}},
+		},
+		{
+			name: "own appearance is dropped",
+			log:  "<2025-01-02T10:00:05.000Z> Player appeared: Me\n",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLog(context.Background(), strings.NewReader(tt.log), "Me", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseLog = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Text != tt.want[i].Text || !got[i].Time.Equal(tt.want[i].Time) {
+					t.Errorf("line %d = %q at %v, want %q at %v", i, got[i].Text, got[i].Time, tt.want[i].Text, tt.want[i].Time)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLogCancelled(t *testing.T) {
+	stats.SetDir(t.TempDir())
+	defer stats.SetDir("")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	log := strings.Repeat("<2025-01-02T10:00:05.000Z> a line of noise to fill the log up\n", 2*progressStep/60)
+	if _, err := ParseLog(ctx, strings.NewReader(log), "Me", nil); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseGzippedLog(t *testing.T) {
+	stats.SetDir(t.TempDir())
+	defer stats.SetDir("")
+	const fixture = "testdata/Game Build 2025-01-02.log.gz"
+	file, err := os.Open(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(fixture), ".gz")
+	if got := LogDateFromName(base); got != "2025-01-02" {
+		t.Errorf("date = %q, want 2025-01-02", got)
+	}
+	reader, _, err := OpenLogReader(file, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := DetectLogPlayer(reader, base)
+	if player != "Me" {
+		t.Fatalf("player = %q, want Me", player)
+	}
+
+	// The reader is rewound for the second pass
+	reader, counter, err := OpenLogReader(file, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := ParseLog(context.Background(), reader, player, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"You killed: Victim_1 using ",
+		"You were killed by: Enemy_1 using ",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("lines = %v, want %d lines", lines, len(want))
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line.Text, want[i]) {
+			t.Errorf("line %d = %q, want it to start with %q", i, line.Text, want[i])
+		}
+	}
+	// Progress follows the compressed bytes read from disk
+	if counter.Count() != info.Size() {
+		t.Errorf("counted %d bytes, want the file's %d", counter.Count(), info.Size())
+	}
+}
@@ -0,0 +1,70 @@
+// Package fsutil holds small file-system helpers shared by the stats and UI packages.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SanitizeFilename makes name safe to use as a file name on Windows and
+// other platforms. Spaces, path separators, characters Windows rejects
+// (\ / : * ? " < > |) and control characters become underscores; trailing
+// dots and spaces, which Windows drops silently, are trimmed. Other Unicode
+// letters are kept so valid handles aren't mangled. A name Windows reserves
+// for a device, such as CON or COM1, gets an underscore after it. An empty
+// result becomes "Unknown".
+func SanitizeFilename(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || r == ' ' || strings.ContainsRune(`\/:*?"<>|`, r) {
+			b.WriteRune('_')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	clean := strings.TrimRight(b.String(), ". ")
+	if clean == "" {
+		return "Unknown"
+	}
+	// Windows treats the device name as reserved whatever extension follows it
+	stem, ext, _ := strings.Cut(clean, ".")
+	if reservedNames[strings.ToUpper(stem)] {
+		clean = stem + "_"
+		if ext != "" {
+			clean += "." + ext
+		}
+	}
+	return clean
+}
+
+// reservedNames are the device names Windows won't use as a file name.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// UniquePath returns dir/base+ext, or dir/base_2+ext, base_3 and so on when
+// that's taken, and creates it empty so that a concurrent caller can't pick
+// the same name. The caller is expected to overwrite it. If the file can't be
+// created for any other reason the name is returned as is, leaving the error
+// to the caller's own write.
+func UniquePath(dir, base, ext string) string {
+	for n := 1; ; n++ {
+		name := base + ext
+		if n > 1 {
+			name = fmt.Sprintf("%s_%d%s", base, n, ext)
+		}
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			f.Close()
+			return path
+		}
+		if !os.IsExist(err) {
+			return path
+		}
+	}
+}
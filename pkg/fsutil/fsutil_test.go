@@ -0,0 +1,100 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestUniquePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     string
+	}{
+		{"free name", nil, "Me_2025-01-02.jsonl"},
+		{"taken name", []string{"Me_2025-01-02.jsonl"}, "Me_2025-01-02_2.jsonl"},
+		{"several taken", []string{"Me_2025-01-02.jsonl", "Me_2025-01-02_2.jsonl"}, "Me_2025-01-02_3.jsonl"},
+		{"gap is filled", []string{"Me_2025-01-02.jsonl", "Me_2025-01-02_3.jsonl"}, "Me_2025-01-02_2.jsonl"},
+		{"other extension doesn't count", []string{"Me_2025-01-02.json"}, "Me_2025-01-02.jsonl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.existing {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("taken"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := UniquePath(dir, "Me_2025-01-02", ".jsonl")
+			if got != filepath.Join(dir, tt.want) {
+				t.Errorf("UniquePath = %q, want %q", filepath.Base(got), tt.want)
+			}
+			if _, err := os.Stat(got); err != nil {
+				t.Errorf("returned path wasn't created: %v", err)
+			}
+			for _, name := range tt.existing {
+				if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != "taken" {
+					t.Errorf("%s was overwritten", name)
+				}
+			}
+		})
+	}
+}
+
+func TestUniquePathConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	const callers = 20
+	paths := make([]string, callers)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			paths[i] = UniquePath(dir, "Me_2025-01-02", ".jsonl")
+		}()
+	}
+	wg.Wait()
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if seen[path] {
+			t.Errorf("%s handed out twice", filepath.Base(path))
+		}
+		seen[path] = true
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pilot_1", "Pilot_1"},
+		{"Pilot One", "Pilot_One"},
+		{"Pilot-1.Two", "Pilot-1.Two"},
+		{`a\b/c:d*e?f"g<h>i|j`, "a_b_c_d_e_f_g_h_i_j"},
+		{"../../etc/passwd", ".._.._etc_passwd"},
+		{"tab\there\x00nul\x7f", "tab_here_nul_"},
+		{"Pilot.", "Pilot"},
+		{"Pilot. . ", "Pilot._._"},
+		{"CON", "CON_"},
+		{"con", "con_"},
+		{"Com1", "Com1_"},
+		{"LPT9.txt", "LPT9_.txt"},
+		{"nul.", "nul_"},
+		{"CONSOLE", "CONSOLE"},
+		{"COM10", "COM10"},
+		{"Pilot_CON", "Pilot_CON"},
+		{"Ünïcødé_Pilot", "Ünïcødé_Pilot"},
+		{"パイロット", "パイロット"},
+		{"", "Unknown"},
+		{"...", "Unknown"},
+		{"   ", "___"},
+	}
+	for _, tt := range tests {
+		if got := SanitizeFilename(tt.name); got != tt.want {
+			t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package fsutil
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenFolder shows dir in the system file manager: explorer on Windows, open
+// on macOS and xdg-open elsewhere. It returns an error when the command isn't
+// installed or can't be started; it doesn't wait for the file manager.
+func OpenFolder(dir string) error {
+	name := "xdg-open"
+	switch runtime.GOOS {
+	case "windows":
+		name = "explorer"
+	case "darwin":
+		name = "open"
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s is not available: %w", name, err)
+	}
+	cmd := exec.Command(path, dir)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	// explorer exits with status 1 even when it worked, so the result is ignored
+	go cmd.Wait()
+	return nil
+}
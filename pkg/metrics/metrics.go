@@ -0,0 +1,105 @@
+// Package metrics exposes kill/death counters in the Prometheus text format.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server serves /metrics with per-player kill and death totals.
+type Server struct {
+	mu     sync.Mutex
+	kills  map[string]int
+	deaths map[string]int
+	srv    *http.Server
+}
+
+// New creates a metrics server that is not yet listening.
+func New() *Server {
+	return &Server{
+		kills:  make(map[string]int),
+		deaths: make(map[string]int),
+	}
+}
+
+// Update sets the counters for a player to their all-time totals. Because the
+// totals come from the persisted stats, counters carry over across restarts.
+func (s *Server) Update(player string, kills, deaths int) {
+	if player == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kills[player] = kills
+	s.deaths[player] = deaths
+}
+
+// DefaultHost keeps the endpoint on the local machine unless another address is configured.
+const DefaultHost = "127.0.0.1"
+
+// Start begins serving on the given host and port. Any previously running listener is stopped first.
+func (s *Server) Start(host string, port int) error {
+	s.Stop()
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	s.mu.Lock()
+	s.srv = srv
+	s.mu.Unlock()
+
+	go srv.Serve(ln)
+	return nil
+}
+
+// Stop shuts the listener down if it is running.
+func (s *Server) Stop() {
+	s.mu.Lock()
+	srv := s.srv
+	s.srv = nil
+	s.mu.Unlock()
+	if srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	srv.Shutdown(ctx)
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeCounter(w, "citizenmon_kills_total", "Total kills recorded for the player.", s.kills)
+	writeCounter(w, "citizenmon_deaths_total", "Total deaths recorded for the player.", s.deaths)
+}
+
+// writeCounter writes one counter family with a sample per player, sorted by name.
+func writeCounter(w http.ResponseWriter, name, help string, values map[string]int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	players := make([]string, 0, len(values))
+	for player := range values {
+		players = append(players, player)
+	}
+	sort.Strings(players)
+	for _, player := range players {
+		fmt.Fprintf(w, "%s{player=\"%s\"} %d\n", name, escapeLabel(player), values[player])
+	}
+}
+
+// escapeLabel escapes a label value per the Prometheus text format.
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
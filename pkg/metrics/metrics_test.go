@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// freePort asks the system for an unused local port.
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", DefaultHost+":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestStartServesOnLoopback(t *testing.T) {
+	s := New()
+	s.Update("Pilot_One", 3, 1)
+	port := freePort(t)
+	if err := s.Start(DefaultHost, port); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer s.Stop()
+
+	resp, err := http.Get(fmt.Sprintf("http://%s:%d/metrics", DefaultHost, port))
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`citizenmon_kills_total{player="Pilot_One"} 3`,
+		`citizenmon_deaths_total{player="Pilot_One"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+
+	// Only the loopback address should be bound
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		conn, err := net.Dial("tcp", net.JoinHostPort(ipNet.IP.String(), fmt.Sprint(port)))
+		if err == nil {
+			conn.Close()
+			t.Errorf("metrics endpoint reachable on %s", ipNet.IP)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Pilot_One", "Pilot_One"},
+		{`a"b`, `a\"b`},
+		{`a\b`, `a\\b`},
+		{"a\nb", `a\nb`},
+	}
+	for _, tt := range tests {
+		if got := escapeLabel(tt.in); got != tt.want {
+			t.Errorf("escapeLabel(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
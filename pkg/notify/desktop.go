@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle limits how often a desktop notification may be raised so that a
+// burst of events doesn't flood the OS notification center.
+type Throttle struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	last       time.Time
+	suppressed int
+}
+
+// NewThrottle creates a throttle allowing at most one notification per interval.
+func NewThrottle(interval time.Duration) *Throttle {
+	return &Throttle{interval: interval}
+}
+
+// Allow reports whether a notification may be sent at now. When it returns true
+// it also returns how many notifications were suppressed since the last one sent.
+func (t *Throttle) Allow(now time.Time) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.last.IsZero() && now.Sub(t.last) < t.interval {
+		t.suppressed++
+		return false, 0
+	}
+	skipped := t.suppressed
+	t.last = now
+	t.suppressed = 0
+	return true, skipped
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	_ "embed"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+//go:embed sounds/kill.wav
+var killWAV []byte
+
+//go:embed sounds/death.wav
+var deathWAV []byte
+
+// Sound identifies one of the bundled audio cues.
+type Sound int
+
+const (
+	SoundKill Sound = iota
+	SoundDeath
+)
+
+// SoundPlayer plays the bundled WAV cues using the platform's audio tools.
+// Playback is best-effort: if the asset can't be written or no player is
+// available it falls back to a system beep and never returns an error.
+type SoundPlayer struct {
+	mu    sync.Mutex
+	dir   string
+	files map[Sound]string
+}
+
+// NewSoundPlayer creates a player that unpacks its assets into a temp directory on first use.
+func NewSoundPlayer() *SoundPlayer {
+	return &SoundPlayer{
+		dir:   filepath.Join(os.TempDir(), "citizenmon-sounds"),
+		files: make(map[Sound]string),
+	}
+}
+
+// Play starts playback of the given cue in the background.
+func (p *SoundPlayer) Play(s Sound) {
+	go func() {
+		path := p.assetPath(s)
+		if path == "" || playFile(path) != nil {
+			beep()
+		}
+	}()
+}
+
+// assetPath returns the on-disk location of a cue, writing it out if it is missing.
+func (p *SoundPlayer) assetPath(s Sound) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if path, ok := p.files[s]; ok {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	var name string
+	var data []byte
+	switch s {
+	case SoundKill:
+		name, data = "kill.wav", killWAV
+	case SoundDeath:
+		name, data = "death.wav", deathWAV
+	default:
+		return ""
+	}
+	if len(data) == 0 {
+		return ""
+	}
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		return ""
+	}
+	path := filepath.Join(p.dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return ""
+	}
+	p.files[s] = path
+	return path
+}
+
+// playFile plays a WAV file with whatever player the OS provides and waits for it to finish.
+func playFile(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command",
+			"(New-Object Media.SoundPlayer '"+path+"').PlaySync()")
+	case "darwin":
+		cmd = exec.Command("afplay", path)
+	default:
+		if _, err := exec.LookPath("paplay"); err == nil {
+			cmd = exec.Command("paplay", path)
+		} else {
+			cmd = exec.Command("aplay", "-q", path)
+		}
+	}
+	hideWindow(cmd)
+	return cmd.Run()
+}
+
+// beep emits a short system beep as a fallback when no audio player works.
+func beep() {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", "[console]::beep(880,150)")
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", "beep")
+	default:
+		os.Stdout.WriteString("\a")
+		return
+	}
+	hideWindow(cmd)
+	_ = cmd.Run()
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package notify
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideWindow keeps the helper process from flashing a console window.
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+}
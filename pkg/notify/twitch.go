@@ -0,0 +1,317 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Twitch chat connection details and limits.
+const (
+	twitchAddr = "irc.chat.twitch.tv:6697"
+	// Twitch allows 20 messages per 30 seconds from a regular account.
+	twitchMessageInterval = 30 * time.Second / 20
+	twitchQueueSize       = 20
+	twitchMaxMessageLen   = 500
+	twitchMinBackoff      = 5 * time.Second
+	twitchMaxBackoff      = 5 * time.Minute
+)
+
+// TwitchConfig is the account and channel a TwitchBot posts with.
+type TwitchConfig struct {
+	Channel  string // channel name, with or without the leading #
+	Username string // account the token belongs to
+	Token    string // chat OAuth token, with or without the "oauth:" prefix
+}
+
+// normalized returns the config in the form sent to Twitch, or an error if
+// a field is missing.
+func (c TwitchConfig) normalized() (TwitchConfig, error) {
+	c.Channel = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(c.Channel), "#"))
+	c.Username = strings.ToLower(strings.TrimSpace(c.Username))
+	c.Token = strings.TrimSpace(c.Token)
+	switch {
+	case c.Channel == "":
+		return c, errors.New("twitch channel is empty")
+	case c.Username == "":
+		return c, errors.New("twitch username is empty")
+	case c.Token == "":
+		return c, errors.New("twitch OAuth token is empty")
+	}
+	if !strings.HasPrefix(c.Token, "oauth:") {
+		c.Token = "oauth:" + c.Token
+	}
+	return c, nil
+}
+
+// TwitchBot posts messages to a Twitch channel's chat over IRC. It connects
+// in the background, reconnects when the connection drops and spaces
+// messages out to stay under Twitch's rate limit. Posting never blocks and
+// connection problems are only reported through OnStatus, so a failing bot
+// can't hold up monitoring.
+type TwitchBot struct {
+	// OnStatus, when set, is called from the bot's goroutine whenever the
+	// connection state changes, with a short description for the UI.
+	OnStatus func(status string)
+
+	mu     sync.Mutex
+	queue  chan string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTwitchBot creates a bot that is not yet connected.
+func NewTwitchBot() *TwitchBot {
+	return &TwitchBot{}
+}
+
+// Start connects with cfg in the background. A running bot is stopped first.
+// Only an incomplete config is reported; connection errors go to OnStatus.
+func (b *TwitchBot) Start(cfg TwitchConfig) error {
+	cfg, err := cfg.normalized()
+	if err != nil {
+		return err
+	}
+	b.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := make(chan string, twitchQueueSize)
+	done := make(chan struct{})
+	b.mu.Lock()
+	b.queue, b.cancel, b.done = queue, cancel, done
+	b.mu.Unlock()
+	go func() {
+		defer close(done)
+		b.run(ctx, cfg, queue)
+	}()
+	return nil
+}
+
+// Stop disconnects and drops any messages still waiting to be sent.
+func (b *TwitchBot) Stop() {
+	b.mu.Lock()
+	cancel, done := b.cancel, b.done
+	b.queue, b.cancel, b.done = nil, nil, nil
+	b.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	b.status("Disconnected")
+}
+
+// Post queues a chat message. It's dropped when the bot isn't running or
+// too many messages are already waiting.
+func (b *TwitchBot) Post(message string) {
+	message = strings.Join(strings.Fields(message), " ")
+	if message == "" {
+		return
+	}
+	if len(message) > twitchMaxMessageLen {
+		message = message[:twitchMaxMessageLen]
+		// Don't leave half a character at the cut
+		for !utf8.ValidString(message) {
+			message = message[:len(message)-1]
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.queue == nil {
+		return
+	}
+	select {
+	case b.queue <- message:
+	default:
+	}
+}
+
+func (b *TwitchBot) status(status string) {
+	if b.OnStatus != nil {
+		b.OnStatus(status)
+	}
+}
+
+// run keeps a connection open until ctx is cancelled, waiting longer after
+// each failed attempt.
+func (b *TwitchBot) run(ctx context.Context, cfg TwitchConfig, queue <-chan string) {
+	backoff := twitchMinBackoff
+	for {
+		b.status("Connecting…")
+		joined, err := b.session(ctx, cfg, queue)
+		if ctx.Err() != nil {
+			return
+		}
+		if joined {
+			backoff = twitchMinBackoff
+		}
+		b.status(fmt.Sprintf("Disconnected (%v), retrying in %s", err, backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, twitchMaxBackoff)
+	}
+}
+
+// session runs one connection: it logs in, joins the channel and sends
+// queued messages until the connection fails or ctx is cancelled. It reports
+// whether the channel was joined.
+func (b *TwitchBot) session(ctx context.Context, cfg TwitchConfig, queue <-chan string) (bool, error) {
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: 15 * time.Second}}
+	conn, err := dialer.DialContext(ctx, "tcp", twitchAddr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	// Closing the connection ends the reader when the bot is stopped
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var writeMu sync.Mutex
+	send := func(line string) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		_, err := conn.Write([]byte(line + "\r\n"))
+		return err
+	}
+	if err := send("PASS " + cfg.Token); err != nil {
+		return false, err
+	}
+	if err := send("NICK " + cfg.Username); err != nil {
+		return false, err
+	}
+	if err := send("JOIN #" + cfg.Channel); err != nil {
+		return false, err
+	}
+
+	joinedCh := make(chan struct{})
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- readTwitch(conn, cfg, send, joinedCh)
+	}()
+
+	joined := false
+	for {
+		select {
+		case <-ctx.Done():
+			return joined, nil
+		case err := <-readErr:
+			return joined, err
+		case <-joinedCh:
+			joined = true
+			joinedCh = nil
+			b.status("Connected to #" + cfg.Channel)
+		case message := <-queueWhen(joined, queue):
+			if err := send("PRIVMSG #" + cfg.Channel + " :" + message); err != nil {
+				return joined, err
+			}
+			select {
+			case <-ctx.Done():
+				return joined, nil
+			case <-time.After(twitchMessageInterval):
+			}
+		}
+	}
+}
+
+// queueWhen returns queue once the channel is joined, and nil, which never
+// delivers, before that, so messages wait for the join.
+func queueWhen(joined bool, queue <-chan string) <-chan string {
+	if joined {
+		return queue
+	}
+	return nil
+}
+
+// readTwitch reads server lines until the connection fails, answering pings,
+// signalling joined once the channel join is confirmed and failing on a
+// rejected login. Only the command decides what a line is, so chat messages
+// that happen to contain these words are ignored.
+func readTwitch(conn net.Conn, cfg TwitchConfig, send func(string) error, joined chan<- struct{}) error {
+	scanner := bufio.NewScanner(conn)
+	signalled := false
+	for scanner.Scan() {
+		msg := parseIRC(scanner.Text())
+		switch {
+		case msg.command == "PING":
+			if err := send("PONG :" + msg.trailing()); err != nil {
+				return err
+			}
+		case msg.command == "NOTICE" && msg.param(0) == "*":
+			// Twitch only sends unaddressed notices for a failed login
+			return fmt.Errorf("login rejected: %s", msg.trailing())
+		case msg.command == "JOIN" && !signalled && msg.nick() == cfg.Username && msg.param(0) == "#"+cfg.Channel:
+			signalled = true
+			close(joined)
+		case msg.command == "RECONNECT":
+			return errors.New("server asked to reconnect")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("connection closed")
+}
+
+// ircMessage is a server line split into its parts:
+// [@tags] [:prefix] COMMAND [params...] [:trailing].
+type ircMessage struct {
+	prefix  string
+	command string
+	params  []string // the trailing parameter, if any, is the last
+}
+
+// parseIRC splits a raw IRC line. Tags are dropped.
+func parseIRC(line string) ircMessage {
+	var msg ircMessage
+	if strings.HasPrefix(line, "@") {
+		_, line, _ = strings.Cut(line, " ")
+	}
+	if strings.HasPrefix(line, ":") {
+		msg.prefix, line, _ = strings.Cut(line[1:], " ")
+	}
+	line, trailing, hasTrailing := strings.Cut(line, " :")
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		msg.command = strings.ToUpper(fields[0])
+	}
+	if len(fields) > 1 {
+		msg.params = fields[1:]
+	}
+	if hasTrailing {
+		msg.params = append(msg.params, trailing)
+	}
+	return msg
+}
+
+// param returns the i'th parameter, or "".
+func (m ircMessage) param(i int) string {
+	if i < len(m.params) {
+		return m.params[i]
+	}
+	return ""
+}
+
+// trailing returns the last parameter, or "".
+func (m ircMessage) trailing() string {
+	if len(m.params) == 0 {
+		return ""
+	}
+	return m.params[len(m.params)-1]
+}
+
+// nick returns the nickname in the prefix, e.g. "bot" in
+// "bot!bot@bot.tmi.twitch.tv".
+func (m ircMessage) nick() string {
+	nick, _, _ := strings.Cut(m.prefix, "!")
+	return nick
+}
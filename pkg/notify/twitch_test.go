@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIRC(t *testing.T) {
+	tests := []struct {
+		line string
+		want ircMessage
+	}{
+		{"PING :tmi.twitch.tv", ircMessage{command: "PING", params: []string{"tmi.twitch.tv"}}},
+		{":tmi.twitch.tv RECONNECT", ircMessage{prefix: "tmi.twitch.tv", command: "RECONNECT"}},
+		{":tmi.twitch.tv NOTICE * :Login authentication failed",
+			ircMessage{prefix: "tmi.twitch.tv", command: "NOTICE", params: []string{"*", "Login authentication failed"}}},
+		{":bot!bot@bot.tmi.twitch.tv JOIN #chan",
+			ircMessage{prefix: "bot!bot@bot.tmi.twitch.tv", command: "JOIN", params: []string{"#chan"}}},
+		{"@badge-info=;color= :viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #chan :RECONNECT NOTICE * :x",
+			ircMessage{prefix: "viewer!viewer@viewer.tmi.twitch.tv", command: "PRIVMSG", params: []string{"#chan", "RECONNECT NOTICE * :x"}}},
+	}
+	for _, tt := range tests {
+		if got := parseIRC(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseIRC(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestReadTwitchIgnoresChat(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	cfg := TwitchConfig{Channel: "chan", Username: "bot"}
+	var sent []string
+	send := func(line string) error {
+		sent = append(sent, line)
+		return nil
+	}
+	joined := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- readTwitch(client, cfg, send, joined) }()
+
+	w := bufio.NewWriter(server)
+	for _, line := range []string{
+		":viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #chan :RECONNECT",
+		":viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #chan :oops NOTICE * :gotcha",
+		":viewer!viewer@viewer.tmi.twitch.tv JOIN #chan",
+		":bot!bot@bot.tmi.twitch.tv JOIN #chan",
+		"PING :tmi.twitch.tv",
+		":tmi.twitch.tv RECONNECT",
+	} {
+		w.WriteString(line + "\r\n")
+	}
+	go w.Flush()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "reconnect") {
+			t.Fatalf("readTwitch returned %v, want the server's reconnect", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("readTwitch didn't return")
+	}
+	select {
+	case <-joined:
+	default:
+		t.Error("join of the bot's own account wasn't signalled")
+	}
+	if !reflect.DeepEqual(sent, []string{"PONG :tmi.twitch.tv"}) {
+		t.Errorf("sent %q, want one PONG", sent)
+	}
+}
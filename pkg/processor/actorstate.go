@@ -0,0 +1,40 @@
+package processor
+
+import "strings"
+
+// Causes of an EventActorState, the player's own state changes on the way
+// from going down to dying. They only narrate the feed; kills and deaths are
+// counted from the kill lines.
+const (
+	ActorStateIncap     = "incapacitated" // downed, ragdolled or stalled, and still revivable
+	ActorStateBledOut   = "bled out"
+	ActorStateHardDeath = "hard death"
+	ActorStateCorpse    = "corpse"
+)
+
+// actorStateCause names the state an actor state line moves the player to.
+// A line matching several states takes the most final one; lines naming none
+// of them, such as a bare control state change, count as turning to a corpse
+// as before.
+func actorStateCause(line string) string {
+	found := map[string]bool{}
+	for _, match := range actorStateRegex.FindAllString(line, -1) {
+		switch word := strings.ReplaceAll(strings.ToLower(match), " ", ""); word {
+		case "harddeath":
+			found[ActorStateHardDeath] = true
+		case "bledout", "bleedout":
+			found[ActorStateBledOut] = true
+		default:
+			found[ActorStateIncap] = true
+		}
+	}
+	for _, cause := range []string{ActorStateHardDeath, ActorStateBledOut} {
+		if found[cause] {
+			return cause
+		}
+	}
+	if found[ActorStateIncap] && !corpseRegex.MatchString(line) {
+		return ActorStateIncap
+	}
+	return ActorStateCorpse
+}
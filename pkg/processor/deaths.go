@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"strings"
+
+	"game-monitor/pkg/stats"
+)
+
+// Killer names for deaths the game attributes to the player themselves.
+const (
+	SuicideKiller     = stats.SuicideKiller
+	EnvironmentKiller = stats.EnvironmentKiller
+)
+
+// environmentalDamage lists damage types (lowercased) of self-attributed
+// deaths the player didn't choose, e.g. falling or running out of air.
+var environmentalDamage = map[string]bool{
+	"crash":         true,
+	"collision":     true,
+	"fall":          true,
+	"falldamage":    true,
+	"suffocation":   true,
+	"drowning":      true,
+	"decompression": true,
+	"hazard":        true,
+	"temperature":   true,
+	"hypothermia":   true,
+	"hyperthermia":  true,
+	"radiation":     true,
+	"starvation":    true,
+	"dehydration":   true,
+	"bleedout":      true,
+}
+
+// SelfDeathKiller classifies a death the log attributes to the player
+// themselves as EnvironmentKiller when the damage type is environmental and
+// as SuicideKiller otherwise (backspace, self-destruct, own explosives).
+func SelfDeathKiller(damageType string) string {
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(damageType), " ", ""))
+	if environmentalDamage[key] {
+		return EnvironmentKiller
+	}
+	return SuicideKiller
+}
+
+// EnvironmentalCause reports whether a killer, victim or damage type named
+// in the log is an environmental cause, like a collision, crash or fall,
+// rather than a player or NPC.
+func EnvironmentalCause(name string) bool {
+	return environmentalDamage[strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", ""))]
+}
+
+// IsSelfDeathKiller reports whether name is SuicideKiller or EnvironmentKiller.
+func IsSelfDeathKiller(name string) bool {
+	return strings.EqualFold(name, SuicideKiller) || strings.EqualFold(name, EnvironmentKiller)
+}
@@ -0,0 +1,134 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSelfDeathKiller(t *testing.T) {
+	tests := []struct {
+		damageType string
+		want       string
+	}{
+		{"Fall", EnvironmentKiller},
+		{"FallDamage", EnvironmentKiller},
+		{"Suffocation", EnvironmentKiller},
+		{"Fall Damage", EnvironmentKiller},
+		{"SelfDestruct", SuicideKiller},
+		{"Suicide", SuicideKiller},
+		{"Explosion", SuicideKiller},
+		{"", SuicideKiller},
+	}
+	for _, tt := range tests {
+		if got := SelfDeathKiller(tt.damageType); got != tt.want {
+			t.Errorf("SelfDeathKiller(%q) = %q, want %q", tt.damageType, got, tt.want)
+		}
+	}
+}
+
+func TestSelfAttributedDeaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantKiller string
+		wantDamage string
+	}{
+		{
+			name:       "fall",
+			line:       "<2025-01-02T10:00:00.000Z> CActor::Kill: 'Me' [1] in zone 'x' killed by 'Me' [1] using 'unknown' [Class unknown] with damage type 'Fall'",
+			wantKiller: EnvironmentKiller,
+			wantDamage: "Fall",
+		},
+		{
+			name:       "suffocation",
+			line:       "<2025-01-02T10:00:00.000Z> CActor::Kill: 'Me' [1] in zone 'x' killed by 'Me' [1] using 'unknown' [Class unknown] with damage type 'Suffocation'",
+			wantKiller: EnvironmentKiller,
+			wantDamage: "Suffocation",
+		},
+		{
+			name:       "self-destruct",
+			line:       "<2025-01-02T10:00:00.000Z> CActor::Kill: 'Me' [1] in zone 'x' killed by 'Me' [1] using 'unknown' [Class unknown] with damage type 'SelfDestruct'",
+			wantKiller: SuicideKiller,
+			wantDamage: SuicideKiller,
+		},
+		{
+			name:       "collision named as the killer",
+			line:       "<2025-01-02T10:00:00.000Z> CActor::Kill: 'Me' [1] in zone 'x' killed by 'Collision' [0] using 'unknown' [Class unknown]",
+			wantKiller: EnvironmentKiller,
+			wantDamage: "Collision",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProcessor(t)
+			p.PlayerName = "Me"
+			var death DeathEvent
+			p.OnDeath = func(e DeathEvent) { death = e }
+			p.ProcessLogLine(tt.line)
+			if got := p.SessionStats.Deaths[tt.wantKiller]; got != 1 {
+				t.Errorf("Deaths[%q] = %d, want 1 (deaths %v)", tt.wantKiller, got, p.SessionStats.Deaths)
+			}
+			if got := p.SessionStats.DamageTypes[tt.wantDamage]; got != 1 {
+				t.Errorf("DamageTypes[%q] = %d, want 1 (damage types %v)", tt.wantDamage, got, p.SessionStats.DamageTypes)
+			}
+			if death.Player != "Me" {
+				t.Errorf("OnDeath not called for the player: %+v", death)
+			}
+			if p.Streak != 0 {
+				t.Errorf("streak = %d, want 0", p.Streak)
+			}
+		})
+	}
+}
+
+func TestDeathLineWeapon(t *testing.T) {
+	builtinMappings(t)
+	const death = "<2025-01-02T10:00:05.000Z> [Notice] <Actor Death> CActor::Kill: 'Me' [1] in zone 'x' killed by '%s' [2] using '%s' [Class x] with damage type '%s'"
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name:  "energy weapon",
+			lines: []string{fmt.Sprintf(death, "Enemy_1", "klwe_rifle_energy_01_123", "Energy")},
+			want:  "You were killed by: Enemy_1 using Klaus & Werner Gallant Rifle",
+		},
+		{
+			name:  "ballistic weapon",
+			lines: []string{fmt.Sprintf(death, "Enemy_1", "behr_rifle_ballistic_01_456", "Bullet")},
+			want:  "You were killed by: Enemy_1 using Behring P4-AR Rifle",
+		},
+		{
+			name:  "unknown weapon falls back to the damage type",
+			lines: []string{fmt.Sprintf(death, "Enemy_1", "unknown", "Collision")},
+			want:  "You were killed by: Enemy_1 using Collision",
+		},
+		{
+			name:  "no weapon at all",
+			lines: []string{"<2025-01-02T10:00:05.000Z> CActor::Kill: 'Me' [1] in zone 'x' killed by 'Enemy_1' [2]"},
+			want:  "You died by Enemy_1",
+		},
+		{
+			name: "weapon kept in a crash summary",
+			lines: []string{
+				vehicleLine(4, "ANVL_Arrow_1", 1, 2, "Me", "Collision"),
+				fmt.Sprintf(death, "Enemy_1", "klwe_rifle_energy_01_123", "Crash"),
+			},
+			want: "Mission Event: Me crashed their Anvil Arrow and died (killed by Enemy_1 using Klaus & Werner Gallant Rifle)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, lines := newTestProcessor(t)
+			p.PlayerName = "Me"
+			for _, line := range tt.lines {
+				p.ProcessLogLine(line)
+			}
+			p.FlushPending()
+			if len(*lines) != 1 || (*lines)[0] != tt.want {
+				t.Errorf("feed = %q, want [%q]", *lines, tt.want)
+			}
+		})
+	}
+}
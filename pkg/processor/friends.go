@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"strings"
+	"sync"
+)
+
+// The game log doesn't mark friendly actors, so friends come from a list
+// the user maintains in Config. Matching is case-insensitive.
+var (
+	friendsMu sync.RWMutex
+	friends   = map[string]bool{}
+)
+
+// TeamKillPrefix starts the feed line for a kill of someone on the friends list.
+const TeamKillPrefix = "Team kill! "
+
+// ParseFriends splits a list of handles separated by commas or new lines.
+func ParseFriends(text string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SetFriends replaces the friends list.
+func SetFriends(names []string) {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	friendsMu.Lock()
+	friends = set
+	friendsMu.Unlock()
+}
+
+// IsFriend reports whether name is on the friends list.
+func IsFriend(name string) bool {
+	friendsMu.RLock()
+	defer friendsMu.RUnlock()
+	return friends[strings.ToLower(name)]
+}
@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// The ignore list drops kills, deaths and incaps involving matching names
+// before anything is counted or shown. It is checked ahead of the NPC and pet
+// detection, so an ignored NPC never reaches the feed at all, while NPCs that
+// aren't ignored are still counted and formatted as usual.
+//
+// Each entry is a case-insensitive substring, or a regular expression when
+// written between slashes, e.g. /^PU_Human_Enemy_.*_Dummy/.
+var (
+	ignoreMu    sync.RWMutex
+	ignoreSubs  []string
+	ignoreRegex []*regexp.Regexp
+)
+
+// killParticipantsRegex picks the victim and the killer out of a CActor::Kill line.
+var killParticipantsRegex = regexp.MustCompile(`CActor::Kill: '([^']+)'.*killed by '([^']+)'`)
+
+// incapTargetRegex picks the target out of an incap line.
+var incapTargetRegex = regexp.MustCompile(`nickname: ([A-Za-z0-9_]+)`)
+
+// SetIgnoreList replaces the ignore list with the entries in text, one per
+// line. Valid entries are applied even if others fail to compile; the
+// returned error describes the invalid ones.
+func SetIgnoreList(text string) error {
+	var subs []string
+	var res []*regexp.Regexp
+	var bad []string
+	for _, entry := range strings.Split(text, "\n") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+			re, err := regexp.Compile("(?i)" + entry[1:len(entry)-1])
+			if err != nil {
+				bad = append(bad, entry)
+				continue
+			}
+			res = append(res, re)
+			continue
+		}
+		subs = append(subs, strings.ToLower(entry))
+	}
+	ignoreMu.Lock()
+	ignoreSubs, ignoreRegex = subs, res
+	ignoreMu.Unlock()
+	if len(bad) > 0 {
+		return fmt.Errorf("invalid regular expression in ignore list: %s", strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// IsIgnored reports whether name matches the ignore list.
+func IsIgnored(name string) bool {
+	ignoreMu.RLock()
+	defer ignoreMu.RUnlock()
+	lower := strings.ToLower(name)
+	for _, sub := range ignoreSubs {
+		if strings.Contains(lower, sub) {
+			return true
+		}
+	}
+	for _, re := range ignoreRegex {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// KillParticipants returns the victim and killer of a raw CActor::Kill line.
+func KillParticipants(line string) (victim, killer string, ok bool) {
+	m := killParticipantsRegex.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// involvesIgnored reports whether a kill or incap line names someone on the
+// ignore list other than player.
+func involvesIgnored(line, player string) bool {
+	var names []string
+	if strings.Contains(line, "CActor::Kill:") {
+		if victim, killer, ok := KillParticipants(line); ok {
+			names = append(names, victim, killer)
+		}
+	} else if strings.Contains(line, "Logged an incap") {
+		if m := incapTargetRegex.FindStringSubmatch(line); m != nil {
+			names = append(names, m[1])
+		}
+	}
+	for _, name := range names {
+		if name != player && IsIgnored(name) {
+			return true
+		}
+	}
+	return false
+}
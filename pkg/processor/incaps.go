@@ -0,0 +1,51 @@
+package processor
+
+import "time"
+
+// DefaultIncapKillWindow is the IncapKillWindow of a new Processor.
+const DefaultIncapKillWindow = 30 * time.Second
+
+// recordIncap remembers when the player incapacitated target, so a kill that
+// finishes them off can be matched by finishIncap.
+func (p *Processor) recordIncap(target string, at time.Time) {
+	if p.IncapKillWindow <= 0 {
+		return
+	}
+	if p.lastIncaps == nil {
+		p.lastIncaps = make(map[string]time.Time)
+	}
+	// Incaps nobody followed up on can't match any more
+	for name, t := range p.lastIncaps {
+		if at.Sub(t) > p.IncapKillWindow {
+			delete(p.lastIncaps, name)
+		}
+	}
+	p.lastIncaps[target] = at
+}
+
+// finishIncap folds a recent incap of victim into the kill that finished them
+// off: the incap is taken back out of the counts so the engagement counts
+// once, as the kill. It reports whether there was such an incap. Call before
+// saving the stats.
+func (p *Processor) finishIncap(victim string, at time.Time) bool {
+	incappedAt, ok := p.lastIncaps[victim]
+	if !ok {
+		return false
+	}
+	delete(p.lastIncaps, victim)
+	if gap := at.Sub(incappedAt); gap < 0 || gap > p.IncapKillWindow {
+		return false
+	}
+	uncount(p.Stats.Incaps, victim)
+	uncount(p.SessionStats.Incaps, victim)
+	return true
+}
+
+// uncount takes one off name's count, dropping it at zero.
+func uncount(counts map[string]int, name string) {
+	if counts[name] <= 1 {
+		delete(counts, name)
+		return
+	}
+	counts[name]--
+}
@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIncapKillWindow(t *testing.T) {
+	const (
+		incap = "<2025-01-02T10:00:00.000Z> [Notice] Logged an incap. nickname: Target_1"
+		kill  = "CActor::Kill: '%s' [1] killed by 'Me' [2] using 'gun'"
+	)
+	tests := []struct {
+		name      string
+		window    time.Duration
+		killAt    string
+		victim    string
+		wantIncap int // Target_1's incaps left after the kill
+	}{
+		{"kill inside the window", 30 * time.Second, "10:00:20", "Target_1", 0},
+		{"kill at the window edge", 30 * time.Second, "10:00:30", "Target_1", 0},
+		{"kill outside the window", 30 * time.Second, "10:00:31", "Target_1", 1},
+		{"window off", 0, "10:00:05", "Target_1", 1},
+		{"different target", 30 * time.Second, "10:00:05", "Target_2", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProcessor(t)
+			p.PlayerName = "Me"
+			p.IncapKillWindow = tt.window
+			p.ProcessLogLine(incap)
+			if got := p.SessionStats.Incaps["Target_1"]; got != 1 {
+				t.Fatalf("incaps before the kill = %d, want 1", got)
+			}
+			p.ProcessLogLine("<2025-01-02T" + tt.killAt + ".000Z> " + fmt.Sprintf(kill, tt.victim))
+			if got := p.SessionStats.Incaps["Target_1"]; got != tt.wantIncap {
+				t.Errorf("session incaps = %d, want %d", got, tt.wantIncap)
+			}
+			if got := p.Stats.Incaps["Target_1"]; got != tt.wantIncap {
+				t.Errorf("all-time incaps = %d, want %d", got, tt.wantIncap)
+			}
+			if got := p.SessionStats.Kills[tt.victim]; got != 1 {
+				t.Errorf("kills of %s = %d, want 1", tt.victim, got)
+			}
+		})
+	}
+}
+
+func TestIncapMatchesOnce(t *testing.T) {
+	p, _ := newTestProcessor(t)
+	p.PlayerName = "Me"
+	p.IncapKillWindow = 30 * time.Second
+	p.Stats.Incaps["Target_1"] = 2 // an earlier incap, already settled
+	p.SessionStats.Incaps["Target_1"] = 2
+	p.ProcessLogLine("<2025-01-02T10:00:00.000Z> Logged an incap. nickname: Target_1")
+	p.ProcessLogLine("<2025-01-02T10:00:05.000Z> CActor::Kill: 'Target_1' [1] killed by 'Me' [2]")
+	p.ProcessLogLine("<2025-01-02T10:00:10.000Z> CActor::Kill: 'Target_1' [1] killed by 'Me' [2]")
+	if got := p.SessionStats.Incaps["Target_1"]; got != 2 {
+		t.Errorf("incaps = %d, want 2: only the latest incap is folded, and only once", got)
+	}
+}
@@ -4,15 +4,29 @@ import "time"
 
 // KillEvent represents a kill event in the log.
 type KillEvent struct {
-	Killer    string
-	Victim    string
-	Weapon    string
-	Timestamp time.Time
+	Killer     string
+	Victim     string
+	Weapon     string
+	DamageType string
+	Timestamp  time.Time
+	Streak     int  // kills since the player's last death, this one included
+	NewBest    bool // Streak beat the player's previous longest streak
+	Friendly   bool // the victim is on the friends list; counted in FriendlyKills, not Kills
 }
 
 // DeathEvent represents a death event in the log.
 type DeathEvent struct {
+	Player     string
+	Killer     string
+	Weapon     string
+	DamageType string
+	Timestamp  time.Time
+}
+
+// IncapEvent represents the player incapacitating someone.
+type IncapEvent struct {
 	Player    string
+	Target    string
 	Timestamp time.Time
 }
 
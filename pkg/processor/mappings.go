@@ -0,0 +1,190 @@
+package processor
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"game-monitor/pkg/stats"
+)
+
+// Built-in friendly names for weapons and vehicles. The app copies them to
+// the data directory on startup (see SeedMappingFiles), where players can
+// update them for a new patch; entries there are applied on top of these.
+var (
+	//go:embed weaponnames.json
+	defaultWeaponNamesJSON []byte
+	//go:embed vehiclenames.json
+	defaultVehicleNamesJSON []byte
+)
+
+// nameMappings holds the lookup tables, keyed by lower-case identifier.
+type nameMappings struct {
+	weapons       map[string]string // weapon class without the instance suffix
+	manufacturers map[string]string // vehicle identifier prefix, e.g. "anvl"
+	vehicles      map[string]string // full vehicle identifier without the instance suffix
+}
+
+// vehicleNamesFile is the layout of vehiclenames.json.
+type vehicleNamesFile struct {
+	Manufacturers map[string]string `json:"manufacturers"`
+	Vehicles      map[string]string `json:"vehicles"`
+}
+
+var activeMappings atomic.Pointer[nameMappings]
+
+// WeaponNamesPath returns the location of the editable weapon name table.
+func WeaponNamesPath() string {
+	return filepath.Join(stats.Dir(), "weaponnames.json")
+}
+
+// VehicleNamesPath returns the location of the editable vehicle name table.
+func VehicleNamesPath() string {
+	return filepath.Join(stats.Dir(), "vehiclenames.json")
+}
+
+// SeedMappingFiles writes the built-in weapon and vehicle tables to the app
+// data directory where they don't exist yet, so players have a file to edit.
+// Existing files are left alone.
+func SeedMappingFiles() error {
+	var errs []error
+	for _, seed := range []struct {
+		path string
+		data []byte
+	}{
+		{WeaponNamesPath(), defaultWeaponNamesJSON},
+		{VehicleNamesPath(), defaultVehicleNamesJSON},
+	} {
+		f, err := os.OpenFile(seed.path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create %s: %w", seed.path, err))
+			continue
+		}
+		_, err = f.Write(seed.data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(seed.path)
+			errs = append(errs, fmt.Errorf("failed to write %s: %w", seed.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReloadMappings reloads the built-in weapon and vehicle names and applies the
+// files in the app data directory on top, where they exist. Nothing is
+// written. A file that can't be read or is invalid is skipped, keeping the
+// built-in names for that table, and reported in the returned error.
+func ReloadMappings() error {
+	names := nameMappings{
+		weapons:       make(map[string]string),
+		manufacturers: make(map[string]string),
+		vehicles:      make(map[string]string),
+	}
+	if err := applyWeaponNames(&names, defaultWeaponNamesJSON, "built-in weapon names"); err != nil {
+		return err
+	}
+	if err := applyVehicleNames(&names, defaultVehicleNamesJSON, "built-in vehicle names"); err != nil {
+		return err
+	}
+
+	var errs []error
+	if data, err := readMappingFile(WeaponNamesPath(), defaultWeaponNamesJSON); err != nil {
+		errs = append(errs, err)
+	} else if err := applyWeaponNames(&names, data, WeaponNamesPath()); err != nil {
+		errs = append(errs, err)
+	}
+	if data, err := readMappingFile(VehicleNamesPath(), defaultVehicleNamesJSON); err != nil {
+		errs = append(errs, err)
+	} else if err := applyVehicleNames(&names, data, VehicleNamesPath()); err != nil {
+		errs = append(errs, err)
+	}
+	activeMappings.Store(&names)
+	return errors.Join(errs...)
+}
+
+// currentMappings returns the active tables, loading them on first use.
+func currentMappings() *nameMappings {
+	if names := activeMappings.Load(); names != nil {
+		return names
+	}
+	_ = ReloadMappings()
+	return activeMappings.Load()
+}
+
+// readMappingFile reads a mapping file, falling back to defaults when it
+// doesn't exist.
+func readMappingFile(path string, defaults []byte) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defaults, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// applyWeaponNames validates a weapon table and adds it to names. Nothing is
+// added when it's invalid.
+func applyWeaponNames(names *nameMappings, data []byte, source string) error {
+	var weapons map[string]string
+	if err := json.Unmarshal(data, &weapons); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	if err := checkMapping(weapons, source); err != nil {
+		return err
+	}
+	mergeMapping(names.weapons, weapons)
+	return nil
+}
+
+// applyVehicleNames validates a vehicle table and adds it to names. Nothing is
+// added when it's invalid.
+func applyVehicleNames(names *nameMappings, data []byte, source string) error {
+	var file vehicleNamesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	if err := checkMapping(file.Manufacturers, source+" manufacturers"); err != nil {
+		return err
+	}
+	if err := checkMapping(file.Vehicles, source+" vehicles"); err != nil {
+		return err
+	}
+	mergeMapping(names.manufacturers, file.Manufacturers)
+	mergeMapping(names.vehicles, file.Vehicles)
+	return nil
+}
+
+// checkMapping rejects entries with a blank identifier or name.
+func checkMapping(m map[string]string, source string) error {
+	for id, name := range m {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("%s: blank identifier for %q", source, name)
+		}
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%s: blank name for %q", source, id)
+		}
+	}
+	return nil
+}
+
+// mergeMapping copies src into dst with lower-cased identifiers.
+func mergeMapping(dst, src map[string]string) {
+	for id, name := range src {
+		dst[strings.ToLower(strings.TrimSpace(id))] = strings.TrimSpace(name)
+	}
+}
@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"os"
+	"testing"
+
+	"game-monitor/pkg/stats"
+)
+
+func TestReloadMappingsWritesNothing(t *testing.T) {
+	stats.SetDir(t.TempDir())
+	defer stats.SetDir("")
+	if err := ReloadMappings(); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{WeaponNamesPath(), VehicleNamesPath()} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was written by ReloadMappings", path)
+		}
+	}
+}
+
+func TestSeedMappingFilesKeepsEdits(t *testing.T) {
+	stats.SetDir(t.TempDir())
+	defer stats.SetDir("")
+	edited := []byte(`{"klwe_rifle_energy_01": "My Rifle"}`)
+	if err := os.WriteFile(WeaponNamesPath(), edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SeedMappingFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(WeaponNamesPath()); string(data) != string(edited) {
+		t.Errorf("weaponnames.json was overwritten: %s", data)
+	}
+	if data, _ := os.ReadFile(VehicleNamesPath()); string(data) != string(defaultVehicleNamesJSON) {
+		t.Error("vehiclenames.json wasn't seeded with the built-in names")
+	}
+	if err := ReloadMappings(); err != nil {
+		t.Fatal(err)
+	}
+	if got := FriendlyWeaponName("klwe_rifle_energy_01_123456"); got != "My Rifle" {
+		t.Errorf("FriendlyWeaponName = %q, want the edited name", got)
+	}
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// orgTagPattern matches an RSI organization SID: up to ten upper-case letters,
+// digits, dashes or underscores, with at least one letter so numeric entity
+// ids such as [200146296826] aren't mistaken for tags.
+const orgTagPattern = `[A-Z0-9_-]*[A-Z][A-Z0-9_-]*`
+
+var orgTagRegex = regexp.MustCompile(`^` + orgTagPattern + `$`)
+
+// OrgTag returns the organization tag written after a quoted actor name in a
+// raw log line, e.g. 'Name' [200146296826] [TAG], or "" when there is none.
+func OrgTag(line, name string) string {
+	if name == "" {
+		return ""
+	}
+	re := regexp.MustCompile(`'` + regexp.QuoteMeta(name) + `'(?: \[\d+\])? \[(` + orgTagPattern + `)\]`)
+	if m := re.FindStringSubmatch(line); len(m) == 2 && len(m[1]) <= 10 {
+		return m[1]
+	}
+	return ""
+}
+
+// withOrgTag appends " [TAG]" to name when the raw line carries an org tag for it.
+func withOrgTag(line, name string) string {
+	if tag := OrgTag(line, name); tag != "" {
+		return name + " [" + tag + "]"
+	}
+	return name
+}
+
+// StripOrgTag removes a trailing " [TAG]" from a name in a feed message.
+func StripOrgTag(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	open := strings.LastIndex(name, " [")
+	if open <= 0 || !strings.HasSuffix(name, "]") {
+		return name, ""
+	}
+	tag := name[open+2 : len(name)-1]
+	if len(tag) > 10 || !orgTagRegex.MatchString(tag) {
+		return name, ""
+	}
+	return name[:open], tag
+}
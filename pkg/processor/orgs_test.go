@@ -0,0 +1,71 @@
+package processor
+
+import "testing"
+
+func TestOrgTag(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"after the entity id", "CActor::Kill: 'Pilot_1' [200146296826] [TEST-ORG] in zone", "TEST-ORG"},
+		{"without an entity id", "CActor::Kill: 'Pilot_1' [ORG1] in zone", "ORG1"},
+		{"entity id only", "CActor::Kill: 'Pilot_1' [200146296826] in zone", ""},
+		{"lower case isn't a tag", "CActor::Kill: 'Pilot_1' [200146296826] [org] in zone", ""},
+		{"too long", "CActor::Kill: 'Pilot_1' [200146296826] [ABCDEFGHIJK] in zone", ""},
+		{"tag of another name", "CActor::Kill: 'Pilot_1' [1] killed by 'Pilot_2' [2] [OTHER]", ""},
+		{"name not in line", "CActor::Kill: 'Pilot_3' [1] [ORG]", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OrgTag(tt.line, "Pilot_1"); got != tt.want {
+				t.Errorf("OrgTag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripOrgTag(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantTag  string
+	}{
+		{"Pilot_1 [TEST]", "Pilot_1", "TEST"},
+		{" Pilot_1 [A-1_B] ", "Pilot_1", "A-1_B"},
+		{"Pilot_1", "Pilot_1", ""},
+		{"Pilot_1 [123]", "Pilot_1 [123]", ""},
+		{"Pilot_1 [lower]", "Pilot_1 [lower]", ""},
+		{"[TEST]", "[TEST]", ""},
+	}
+	for _, tt := range tests {
+		name, tag := StripOrgTag(tt.in)
+		if name != tt.wantName || tag != tt.wantTag {
+			t.Errorf("StripOrgTag(%q) = %q, %q; want %q, %q", tt.in, name, tag, tt.wantName, tt.wantTag)
+		}
+	}
+}
+
+func TestKillLineOrgTag(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"with org", "<2025-01-02T10:00:00.000Z> CActor::Kill: 'Pilot_1' [200146296826] [TEST] in zone 'x' killed by 'Me' [2] using 'gun'", "You killed: Pilot_1 [TEST] using gun"},
+		{"without org", "<2025-01-02T10:00:00.000Z> CActor::Kill: 'Pilot_1' [200146296826] in zone 'x' killed by 'Me' [2] using 'gun'", "You killed: Pilot_1 using gun"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, lines := newTestProcessor(t)
+			p.PlayerName = "Me"
+			p.ProcessLogLine(tt.line)
+			if len(*lines) != 1 || (*lines)[0] != tt.want {
+				t.Errorf("feed = %q, want [%q]", *lines, tt.want)
+			}
+			if p.SessionStats.Kills["Pilot_1"] != 1 {
+				t.Errorf("kill not counted under the bare name: %v", p.SessionStats.Kills)
+			}
+		})
+	}
+}
@@ -1,492 +1,1002 @@
-package processor
-
-import (
-	"fmt"
-	"regexp"
-	"strings"
-	"time"
-
-	"game-monitor/pkg/stats"
-
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/widget"
-)
-
-var (
-	corpseRegex  = regexp.MustCompile(`\bCorpse\b`)
-	vehicleRegex = regexp.MustCompile(
-		`CVehicle::OnAdvanceDestroyLevel: Vehicle '([^']+)' .*advanced from destroy level ([0-9]+) to ([0-9]+) caused by '([^']+)' .*with '([^']+)'`,
-	)
-)
-
-// cleanName removes numeric suffixes and replaces underscores with spaces.
-func cleanName(name string) string {
-	reNum := regexp.MustCompile(`_[0-9]+$`)
-	clean := reNum.ReplaceAllString(name, "")
-	return strings.ReplaceAll(clean, "_", " ")
-}
-
-// Processor holds state needed to parse and display log info.
-type Processor struct {
-	PlayerName      string
-	Stats           stats.Stats // All-time stats (persisted to file)
-	SessionStats    stats.Stats // Current session stats (reset on app restart)
-	OutputBox       *widget.Entry
-	PlayerLabel     *widget.Label
-	AppendOutput    func(line string, logTime ...time.Time) // logTime is optional, for UI to use
-	LastRawLogLine  string                                  // NEW: holds the last raw log line processed
-	EventAggregator *EventAggregator                        // NEW: aggregates related events into mission summaries
-}
-
-// New creates a Processor bound to the given output entry and label.
-func New(output *widget.Entry, label *widget.Label) *Processor {
-	p := &Processor{
-		Stats:           stats.New(),
-		SessionStats:    stats.New(), // Initialize current session stats
-		OutputBox:       output,
-		PlayerLabel:     label,
-		EventAggregator: NewEventAggregator(),
-	} // default AppendOutput updates the UI entry on main thread
-	p.AppendOutput = func(line string, logTime ...time.Time) {
-		ts := ""
-		if len(logTime) > 0 {
-			// Convert UTC timestamp to local timezone
-			localTime := logTime[0].Local()
-			ts = localTime.Format("2006-01-02 15:04:05") + " "
-		} else {
-			ts = time.Now().Format("2006-01-02 15:04:05") + " "
-		}
-		fyne.Do(func() {
-			if p.PlayerLabel != nil && p.PlayerName != "" {
-				p.PlayerLabel.SetText(p.PlayerName)
-			}
-			if p.OutputBox != nil {
-				p.OutputBox.SetText(p.OutputBox.Text + ts + line + "\n")
-			}
-		})
-	}
-	return p
-}
-
-// DetectPlayerName scans a line to set p.PlayerName once.
-func (p *Processor) DetectPlayerName(line string) {
-	if p.PlayerName != "" {
-		return
-	}
-
-	// Extract timestamp from the current line for consistent timestamping
-	logTime, hasTime := ExtractLogTimestamp(line)
-
-	// Look for nickname="PlayerName" pattern in network messages
-	if strings.Contains(line, "nickname=") {
-		// Extract nickname using regex for better accuracy
-		nicknameRegex := regexp.MustCompile(`nickname="([^"]+)"`)
-		if matches := nicknameRegex.FindStringSubmatch(line); len(matches) > 1 {
-			p.PlayerName = matches[1]
-			if hasTime {
-				p.AppendOutput("Detected player name: "+p.PlayerName, logTime)
-			} else {
-				p.AppendOutput("Detected player name: " + p.PlayerName)
-			}
-			p.Stats = stats.Load(p.PlayerName)
-			return
-		}
-	}
-
-	// Fallback: Look for Player[PlayerName] pattern in inventory/other messages
-	if strings.Contains(line, "Player[") {
-		playerRegex := regexp.MustCompile(`Player\[([^\]]+)\]`)
-		if matches := playerRegex.FindStringSubmatch(line); len(matches) > 1 {
-			p.PlayerName = matches[1]
-			if hasTime {
-				p.AppendOutput("Detected player name: "+p.PlayerName, logTime)
-			} else {
-				p.AppendOutput("Detected player name: " + p.PlayerName)
-			}
-			p.Stats = stats.Load(p.PlayerName)
-			return
-		}
-	}
-
-	// Legacy fallback for older log formats
-	if strings.Contains(line, "Character:") && strings.Contains(line, "name") {
-		parts := strings.Fields(line)
-		for i, tok := range parts {
-			if tok == "name" && i+1 < len(parts) {
-				p.PlayerName = strings.Trim(parts[i+1], "-:[]{}\\\",'")
-				if hasTime {
-					p.AppendOutput("Detected player name: "+p.PlayerName, logTime)
-				} else {
-					p.AppendOutput("Detected player name: " + p.PlayerName)
-				}
-				p.Stats = stats.Load(p.PlayerName)
-				return
-			}
-		}
-	}
-}
-
-// Helper to extract UTC timestamp from a log line and convert to local time
-func ExtractLogTimestamp(line string) (time.Time, bool) {
-	// Look for timestamp pattern <YYYY-MM-DDTHH:MM:SS.sssZ>
-	if idx1 := strings.Index(line, "<"); idx1 != -1 {
-		if idx2 := strings.Index(line[idx1:], ">"); idx2 != -1 {
-			timestamp := line[idx1+1 : idx1+idx2]
-			if t, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
-				return t, true
-			}
-		}
-	}
-
-	// Fallback: look in individual fields
-	fields := strings.Fields(line)
-	for _, f := range fields {
-		if len(f) >= 20 && (strings.HasSuffix(f, "Z") || strings.HasSuffix(f, "+00:00")) {
-			if t, err := time.Parse(time.RFC3339Nano, f); err == nil {
-				return t, true
-			}
-		}
-		if !strings.Contains(f, "-") && !strings.Contains(f, ":") {
-			break
-		}
-	}
-	return time.Time{}, false
-}
-
-// ProcessLogLine updates stats based on a single log line.
-func (p *Processor) ProcessLogLine(line string) {
-	p.LastRawLogLine = line // NEW: always set the last raw log line
-	logTime, hasTime := ExtractLogTimestamp(line)
-
-	if !hasTime {
-		logTime = time.Now()
-	}
-
-	// If player name not detected yet, just return without processing events
-	if p.PlayerName == "" {
-		return
-	}
-
-	// First, flush old events that are beyond the aggregation window
-	oldMessages := p.EventAggregator.FlushOldEvents(logTime, p)
-	for _, msg := range oldMessages {
-		p.AppendOutput(msg, logTime)
-	}
-
-	var eventDetected bool
-
-	// Vehicle destruction
-	if strings.Contains(line, "CVehicle::OnAdvanceDestroyLevel") {
-		if m := vehicleRegex.FindStringSubmatch(line); len(m) == 6 {
-			fullID := m[1]
-			toLevel := m[3]
-			causeRaw := m[4]
-			weaponRaw := m[5]
-
-			// Add to event aggregator
-			event := PendingEvent{
-				Type:        EventVehicleDestruction,
-				Timestamp:   logTime,
-				PlayerName:  p.PlayerName,
-				VehicleName: fullID,
-				Cause:       causeRaw,
-				Weapon:      weaponRaw,
-				RawLine:     line,
-				Details:     map[string]string{"destroyLevel": toLevel},
-			}
-			p.EventAggregator.AddEvent(event)
-			eventDetected = true
-		}
-	}
-	// Player deaths and kills
-	if strings.Contains(line, "CActor::Kill:") {		// suicide
-		suicidePattern := fmt.Sprintf(`CActor::Kill: '%s'.*killed by '%s'`, regexp.QuoteMeta(p.PlayerName), regexp.QuoteMeta(p.PlayerName))
-		suicideRe := regexp.MustCompile(suicidePattern)
-		if suicideRe.MatchString(line) {
-			p.Stats.Deaths["Suicide"]++
-			p.SessionStats.Deaths["Suicide"]++
-			stats.Save(p.PlayerName, p.Stats)
-			stats.UpdateCurrentSession(p.PlayerName, p.SessionStats)
-
-			// Add to event aggregator
-			event := PendingEvent{
-				Type:       EventPlayerDeath,
-				Timestamp:  logTime,
-				PlayerName: p.PlayerName,
-				Cause:      "suicide",
-				Weapon:     "suicide",
-				RawLine:    line,
-			}
-			p.EventAggregator.AddEvent(event)
-			eventDetected = true
-		} else {			// Check if this player died
-			rDeath := regexp.MustCompile(`CActor::Kill: '` + regexp.QuoteMeta(p.PlayerName) + `'.*killed by '([^']+)'(?:.*using '([^']+)')?(?:.*with damage type '([^']+)')?`)
-			if m := rDeath.FindStringSubmatch(line); len(m) > 1 {
-				killer := m[1]
-				weapon := ""
-				damageType := ""
-				if len(m) >= 3 && m[2] != "" {
-					weapon = m[2]
-				}
-				if len(m) >= 4 && m[3] != "" {
-					damageType = m[3]
-				}
-
-				p.Stats.Deaths[killer]++
-				p.SessionStats.Deaths[killer]++
-				stats.Save(p.PlayerName, p.Stats)
-				stats.UpdateCurrentSession(p.PlayerName, p.SessionStats)
-
-				// Add to event aggregator
-				event := PendingEvent{
-					Type:       EventPlayerDeath,
-					Timestamp:  logTime,
-					PlayerName: p.PlayerName,
-					Cause:      killer,
-					Weapon:     weapon,
-					RawLine:    line,
-					Details:    map[string]string{"damageType": damageType},
-				}
-				p.EventAggregator.AddEvent(event)
-				eventDetected = true
-			} else {				// kill by player with method
-				rMethod := regexp.MustCompile(`CActor::Kill: '([A-Za-z0-9_]+)'.*killed by '` + regexp.QuoteMeta(p.PlayerName) + `'.*using '([^']+)'`)
-				if m := rMethod.FindStringSubmatch(line); len(m) == 3 {
-					victim := m[1]
-					method := cleanName(m[2])
-					p.Stats.Kills[victim]++
-					p.SessionStats.Kills[victim]++
-					stats.Save(p.PlayerName, p.Stats)
-					stats.UpdateCurrentSession(p.PlayerName, p.SessionStats)
-					p.AppendOutput(fmt.Sprintf("You killed: %s using %s", victim, method), logTime)
-					return
-				}
-				// fallback kill by player
-				rKill := regexp.MustCompile(`CActor::Kill: '([A-Za-z0-9_]+)'.*killed by '` + regexp.QuoteMeta(p.PlayerName) + `'`)
-				if m := rKill.FindStringSubmatch(line); len(m) > 1 {
-					victim := m[1]
-					p.Stats.Kills[victim]++
-					p.SessionStats.Kills[victim]++
-					stats.Save(p.PlayerName, p.Stats)
-					stats.UpdateCurrentSession(p.PlayerName, p.SessionStats)
-					p.AppendOutput("You killed: "+victim, logTime)
-					return
-				}
-			}
-		}
-	}
-	// Actor state changes (corpse)
-	if corpseRegex.MatchString(line) || strings.Contains(line, "Entering control state") {
-		// Try to extract the player name from the line
-		if idx := strings.Index(line, "Player '"); idx != -1 {
-			endIdx := strings.Index(line[idx+8:], "'")
-			if endIdx != -1 {
-				extracted := line[idx+8 : idx+8+endIdx]
-				if extracted != "" && extracted == p.PlayerName {
-					// Add to event aggregator for player state changes
-					event := PendingEvent{
-						Type:       EventActorState,
-						Timestamp:  logTime,
-						PlayerName: p.PlayerName,
-						Cause:      "corpse",
-						RawLine:    line,
-					}
-					p.EventAggregator.AddEvent(event)
-					eventDetected = true
-				}
-			}
-		}
-	}
-	// Incapacitations (not aggregated, output immediately)
-	if strings.Contains(line, "Logged an incap") {
-		r := regexp.MustCompile(`nickname: ([A-Za-z0-9_]+)`)
-		if m := r.FindStringSubmatch(line); len(m) > 1 && m[1] != p.PlayerName {
-			target := m[1]
-			p.Stats.Incaps[target]++
-			p.SessionStats.Incaps[target]++
-			stats.Save(p.PlayerName, p.Stats)
-			stats.UpdateCurrentSession(p.PlayerName, p.SessionStats)
-			p.AppendOutput("You incapacitated: "+target, logTime)
-			return
-		}
-	}
-	// If we detected an event that should be aggregated, don't try to create a summary yet
-	// Let events accumulate in the aggregator
-	if eventDetected {
-		// Only try to create summaries when we flush old events or when forced
-		// This allows multiple related events to accumulate before processing
-	}
-}
-
-// EventType represents different types of events that can be aggregated
-type EventType int
-
-const (
-	EventVehicleDestruction EventType = iota
-	EventPlayerDeath
-	EventVehicleSpawn
-	EventActorState
-)
-
-// PendingEvent holds information about an event waiting to be aggregated
-type PendingEvent struct {
-	Type        EventType
-	Timestamp   time.Time
-	PlayerName  string
-	VehicleName string
-	Cause       string
-	Weapon      string
-	RawLine     string
-	Details     map[string]string
-}
-
-// EventAggregator manages combining related events into mission summaries
-type EventAggregator struct {
-	PendingEvents []PendingEvent
-	TimeWindow    time.Duration // Events within this window are considered related
-}
-
-// NewEventAggregator creates a new event aggregator with a 5-second time window
-func NewEventAggregator() *EventAggregator {
-	return &EventAggregator{
-		PendingEvents: make([]PendingEvent, 0),
-		TimeWindow:    5 * time.Second, // Events within 5 seconds are considered related
-	}
-}
-
-// AddEvent adds an event to the pending list
-func (ea *EventAggregator) AddEvent(event PendingEvent) {
-	ea.PendingEvents = append(ea.PendingEvents, event)
-}
-
-// FlushOldEvents processes and flushes events older than the time window
-func (ea *EventAggregator) FlushOldEvents(currentTime time.Time, processor *Processor) []string {
-	var messages []string
-	var remainingEvents []PendingEvent
-	var oldEvents []PendingEvent
-
-	for _, event := range ea.PendingEvents {
-		if currentTime.Sub(event.Timestamp) > ea.TimeWindow {
-			oldEvents = append(oldEvents, event)
-		} else {
-			remainingEvents = append(remainingEvents, event)
-		}
-	}
-	// Group old events by player and try to create mission summaries
-	playerEvents := make(map[string][]PendingEvent)
-	for _, event := range oldEvents {
-		playerEvents[event.PlayerName] = append(playerEvents[event.PlayerName], event)
-	}
-
-	// Create mission summaries for each player
-	for _, events := range playerEvents {
-		if summary := ea.createMissionSummary(events); summary != "" {
-			messages = append(messages, summary)
-		} else {
-			// If no summary could be created, output individual events
-			for _, event := range events {
-				messages = append(messages, ea.CreateIndividualEventMessage(event))
-			}
-		}
-	}
-
-	ea.PendingEvents = remainingEvents
-	return messages
-}
-
-// ProcessEventsForPlayer looks for related events for a specific player and creates summaries
-func (ea *EventAggregator) ProcessEventsForPlayer(playerName string, currentTime time.Time) string {
-	var relatedEvents []PendingEvent
-	var remainingEvents []PendingEvent
-
-	for _, event := range ea.PendingEvents {
-		if event.PlayerName == playerName && currentTime.Sub(event.Timestamp) <= ea.TimeWindow {
-			relatedEvents = append(relatedEvents, event)
-		} else {
-			remainingEvents = append(remainingEvents, event)
-		}
-	}
-
-	ea.PendingEvents = remainingEvents
-
-	if len(relatedEvents) > 0 {
-		return ea.createMissionSummary(relatedEvents)
-	}
-
-	return ""
-}
-
-// createMissionSummary analyzes related events and creates a coherent mission summary
-func (ea *EventAggregator) createMissionSummary(events []PendingEvent) string {
-	if len(events) == 0 {
-		return ""
-	}
-
-	// Sort events by timestamp
-	for i := 0; i < len(events)-1; i++ {
-		for j := i + 1; j < len(events); j++ {
-			if events[i].Timestamp.After(events[j].Timestamp) {
-				events[i], events[j] = events[j], events[i]
-			}
-		}
-	}
-
-	// Analyze the sequence of events
-	var vehicleDestroyed bool
-	var playerDied bool
-	var crashCause bool
-	var playerName string
-	var vehicleName string
-
-	for _, event := range events {
-		switch event.Type {
-		case EventVehicleDestruction:
-			vehicleDestroyed = true
-			vehicleName = event.VehicleName
-			if strings.ToLower(event.Cause) == "collision" || strings.ToLower(event.Weapon) == "collision" {
-				crashCause = true
-			}
-		case EventPlayerDeath:
-			playerDied = true
-			playerName = event.PlayerName
-			if strings.ToLower(event.Cause) == "crash" || strings.ToLower(event.Weapon) == "crash" {
-				crashCause = true
-			}
-		}
-	}
-
-	// Create mission summary based on detected patterns
-	if vehicleDestroyed && playerDied && crashCause && playerName != "" {
-		if vehicleName != "" {
-			return fmt.Sprintf("Mission Event: %s crashed their %s and died", playerName, cleanName(vehicleName))
-		} else {
-			return fmt.Sprintf("Mission Event: %s died in a crash", playerName)
-		}
-	}
-
-	// If we can't create a meaningful summary, return empty string to use individual events
-	return ""
-}
-
-// CreateIndividualEventMessage creates a message for a single event that couldn't be aggregated
-func (ea *EventAggregator) CreateIndividualEventMessage(event PendingEvent) string {
-	switch event.Type {
-	case EventVehicleDestruction:
-		if event.VehicleName != "" {
-			return fmt.Sprintf("Vehicle %s was destroyed by %s", cleanName(event.VehicleName), event.Cause)
-		}
-		return fmt.Sprintf("Vehicle was destroyed by %s", event.Cause)
-	case EventPlayerDeath:
-		if event.Weapon != "" && event.Weapon != "unknown" {
-			return fmt.Sprintf("You were killed by: %s using %s", event.Cause, event.Weapon)
-		}
-		return fmt.Sprintf("You died by %s", event.Cause)
-	case EventActorState:
-		if event.Cause == "corpse" {
-			return "You turned to a corpse"
-		}
-		return fmt.Sprintf("You %s", event.Cause)
-	default:
-		return event.RawLine
-	}
-}
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"game-monitor/pkg/stats"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	corpseRegex = regexp.MustCompile(`\bCorpse\b`)
+	// Actor state transitions between going down and dying, e.g. "Entering control state Incapacitated"
+	actorStateRegex = regexp.MustCompile(`(?i)\b(?:incapacitated|downed|ragdoll|stall(?:ed)?|bled ?out|bleed ?out|hard ?death)\b`)
+	damageTypeRegex = regexp.MustCompile(`with damage type '([^']+)'`)
+	// Spawn flow lines name each player whose character enters the server near us
+	appearanceRegex = regexp.MustCompile(`(?:<Spawn Flow>|CSCPlayerPUSpawningComponent).*?Player '([^']+)'`)
+	// Revives name the revived player, then the medic after "revived by"
+	reviveRegex = regexp.MustCompile(`<Actor Revived?>.*?'([^']+)' \[\d+\].*?\brevived by '([^']+)'`)
+	// Respawns at a bed or station come through the spawn flow and don't name anyone else
+	respawnRegex = regexp.MustCompile(`<Spawn Flow>.*?Player '([^']+)'.*?\b(?:respawned|spawned at)\b`)
+	vehicleRegex = regexp.MustCompile(
+		`CVehicle::OnAdvanceDestroyLevel: Vehicle '([^']+)' .*advanced from destroy level ([0-9]+) to ([0-9]+) caused by '([^']+)' .*with '([^']+)'`,
+	)
+)
+
+// cleanName removes numeric suffixes and replaces underscores with spaces.
+func cleanName(name string) string {
+	reNum := regexp.MustCompile(`_[0-9]+$`)
+	clean := reNum.ReplaceAllString(name, "")
+	return strings.ReplaceAll(clean, "_", " ")
+}
+
+// Processor holds state needed to parse and display log info.
+type Processor struct {
+	PlayerName      string
+	Stats           stats.Stats // All-time stats (persisted to file)
+	SessionStats    stats.Stats // Current session stats (reset on app restart)
+	OutputBox       *widget.Entry
+	PlayerLabel     *widget.Label
+	AppendOutput    func(line string, logTime ...time.Time) // logTime is optional, for UI to use; zero when the log line had none
+	LastRawLogLine  string                                  // NEW: holds the last raw log line processed
+	EventAggregator *EventAggregator                        // NEW: aggregates related events into mission summaries
+	OnKill          func(event KillEvent)                   // optional hook, called when the player kills someone
+	OnDeath         func(event DeathEvent)                  // optional hook, called when the player dies
+	OnIncap         func(event IncapEvent)                  // optional hook, called when the player incapacitates someone
+	Pinned          bool                                    // when true, PlayerName was chosen by the user and detection is skipped
+	Streak          int                                     // kills since the player's last death
+	// FollowPlayerChanges switches the active player when the log consistently
+	// names someone else, e.g. after changing characters
+	FollowPlayerChanges bool
+	OnPlayerChange      func(oldName, newName string) // optional hook, called after the active player switched
+	changeCandidate     string                        // name seen instead of PlayerName, see trackPlayerChange
+	changeSightings     int
+	redetect            bool // set by ResetPlayerDetection: the next name seen becomes the player at once
+	// DedupWindow drops a line identical to the previous one (apart from its
+	// timestamp) if it arrives within this long; 0 turns deduplication off
+	DedupWindow  time.Duration
+	lastLineAt   time.Time // timestamp of LastRawLogLine
+	lastLineWall time.Time // wall-clock time the last line was processed, for FlushIdle
+	// IncapKillWindow is how soon after the player incapacitates someone a kill
+	// of the same target replaces the incap in the counts; 0 counts both
+	IncapKillWindow time.Duration
+	lastIncaps      map[string]time.Time // recent incaps by target, see recordIncap
+	// Offline keeps counted stats in memory only, for throwaway processors
+	// such as log conversion that must not touch the saved stats or session
+	Offline bool
+	// SessionOnly publishes the session stats but never saves the all-time
+	// stats, e.g. for sample data that should show up without being kept
+	SessionOnly bool
+	// Shard and GameMode describe where the player currently is, e.g.
+	// "pub_use1b_7940467_100" and "PU"; Shard is empty outside the PU
+	Shard         string
+	GameMode      string
+	OnShardChange func(shard, mode string) // optional hook, called when Shard or GameMode changes
+}
+
+// saveStats persists the all-time stats and publishes the session stats,
+// unless the processor is Offline. A SessionOnly processor skips the save.
+func (p *Processor) saveStats() {
+	if p.Offline {
+		return
+	}
+	if !p.SessionOnly {
+		stats.Save(p.PlayerName, p.Stats)
+	}
+	stats.UpdateCurrentSession(p.PlayerName, p.SessionStats)
+}
+
+// New creates a Processor bound to the given output entry and label.
+func New(output *widget.Entry, label *widget.Label) *Processor {
+	p := &Processor{
+		Stats:           stats.New(),
+		SessionStats:    stats.New(), // Initialize current session stats
+		OutputBox:       output,
+		PlayerLabel:     label,
+		EventAggregator: NewEventAggregator(),
+		DedupWindow:     DefaultDedupWindow,
+		IncapKillWindow: DefaultIncapKillWindow,
+	} // default AppendOutput updates the UI entry on main thread
+	p.AppendOutput = func(line string, logTime ...time.Time) {
+		ts := ""
+		if len(logTime) > 0 && !logTime[0].IsZero() {
+			// Convert UTC timestamp to local timezone
+			ts = FormatTimestamp(logTime[0]) + " "
+		} else {
+			ts = FormatTimestamp(time.Now()) + " "
+		}
+		fyne.Do(func() {
+			if p.PlayerLabel != nil && p.PlayerName != "" {
+				p.PlayerLabel.SetText(p.PlayerName)
+			}
+			if p.OutputBox != nil {
+				p.OutputBox.SetText(p.OutputBox.Text + ts + line + "\n")
+			}
+		})
+	}
+	return p
+}
+
+// SetPlayer switches the active player and loads their all-time and current session stats.
+func (p *Processor) SetPlayer(name string) {
+	p.PlayerName = name
+	p.Stats = stats.Load(name)
+	p.SessionStats = stats.GetCurrentSession(name)
+	p.Streak = 0
+}
+
+// countStreakKill extends the kill streak, recording a new personal best in
+// the all-time stats. Call before saving them.
+func (p *Processor) countStreakKill() (streak int, newBest bool) {
+	p.Streak++
+	if p.Streak > p.Stats.BestStreak {
+		p.Stats.BestStreak = p.Streak
+		return p.Streak, true
+	}
+	return p.Streak, false
+}
+
+// FlushPending writes out every event still waiting in the aggregation
+// window, for when monitoring stops. The lines carry the latest event's time.
+func (p *Processor) FlushPending() {
+	if len(p.EventAggregator.PendingEvents) == 0 {
+		return
+	}
+	latest := p.EventAggregator.latestEvent()
+	for _, msg := range p.EventAggregator.FlushAll(p) {
+		p.AppendOutput(msg, latest)
+	}
+}
+
+// FlushIdle runs FlushPending once no log line has arrived for idle, so the
+// last events reach the feed when the log goes quiet, e.g. the game closed.
+func (p *Processor) FlushIdle(idle time.Duration) {
+	if !p.lastLineWall.IsZero() && time.Since(p.lastLineWall) >= idle {
+		p.FlushPending()
+	}
+}
+
+// countTeamKill records a kill of a friend. Team kills don't extend the streak.
+func (p *Processor) countTeamKill(victim string) {
+	p.Stats.FriendlyKills[victim]++
+	p.SessionStats.FriendlyKills[victim]++
+	p.saveStats()
+}
+
+// DetectPlayerName scans a line to set p.PlayerName once. With
+// FollowPlayerChanges set, a different name seen consistently afterwards
+// switches the active player, see trackPlayerChange. Only login lines count
+// towards a change, as other lines also name other players.
+func (p *Processor) DetectPlayerName(line string) {
+	if p.Pinned || (p.PlayerName != "" && !p.FollowPlayerChanges && !p.redetect) {
+		return
+	}
+	var name string
+	if p.PlayerName == "" {
+		name = extractPlayerName(line)
+	} else {
+		name = extractLoginName(line)
+	}
+	if name == "" {
+		return
+	}
+
+	// Extract timestamp from the current line for consistent timestamping
+	logTime, hasTime := ExtractLogTimestamp(line)
+	if p.PlayerName != "" && p.redetect {
+		p.redetect = false
+		if name != p.PlayerName {
+			p.switchPlayer(name, logTime, hasTime)
+		}
+		return
+	}
+	if p.PlayerName != "" {
+		p.trackPlayerChange(name, logTime, hasTime)
+		return
+	}
+	p.PlayerName = name
+	if hasTime {
+		p.AppendOutput(DetectedPlayerPrefix+p.PlayerName, logTime)
+	} else {
+		p.AppendOutput(DetectedPlayerPrefix + p.PlayerName)
+	}
+	p.Stats = stats.Load(p.PlayerName)
+}
+
+var (
+	nicknameRegex = regexp.MustCompile(`nickname="([^"]+)"`)
+	playerRegex   = regexp.MustCompile(`Player\[([^\]]+)\]`)
+)
+
+// extractLoginName returns the name from a nickname="…" login line, or "".
+func extractLoginName(line string) string {
+	if strings.Contains(line, "nickname=") {
+		if matches := nicknameRegex.FindStringSubmatch(line); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// extractPlayerName returns the player name a line announces, or "".
+func extractPlayerName(line string) string {
+	// Look for nickname="PlayerName" pattern in network messages
+	if name := extractLoginName(line); name != "" {
+		return name
+	}
+
+	// Fallback: Look for Player[PlayerName] pattern in inventory/other messages
+	if strings.Contains(line, "Player[") {
+		if matches := playerRegex.FindStringSubmatch(line); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+
+	// Legacy fallback for older log formats
+	if strings.Contains(line, "Character:") && strings.Contains(line, "name") {
+		parts := strings.Fields(line)
+		for i, tok := range parts {
+			if tok == "name" && i+1 < len(parts) {
+				return strings.Trim(parts[i+1], "-:[]{}\\\",'")
+			}
+		}
+	}
+	return ""
+}
+
+// playerChangeSightings is how many times in a row a new name has to be seen
+// before the active player switches to it.
+const playerChangeSightings = 3
+
+// trackPlayerChange counts sightings of a name other than the active player
+// and switches once the same name has been seen playerChangeSightings times
+// without the active player's name turning up in between.
+func (p *Processor) trackPlayerChange(name string, logTime time.Time, hasTime bool) {
+	if name == p.PlayerName {
+		p.changeCandidate, p.changeSightings = "", 0
+		return
+	}
+	if name != p.changeCandidate {
+		p.changeCandidate, p.changeSightings = name, 0
+	}
+	p.changeSightings++
+	if p.changeSightings < playerChangeSightings {
+		return
+	}
+	p.switchPlayer(name, logTime, hasTime)
+}
+
+// Feed lines noting which player is being tracked, as opposed to events.
+const (
+	DetectedPlayerPrefix = "Detected player name: "
+	PlayerChangedPrefix  = "Player changed: "
+)
+
+// ResetPlayerDetection makes the next player name found in the log replace
+// the active player straight away, e.g. when the log was recreated by a new
+// game session. A pinned player is kept.
+func (p *Processor) ResetPlayerDetection() {
+	if !p.Pinned {
+		p.redetect = true
+	}
+}
+
+// switchPlayer makes name the active player, noting it in the feed and
+// calling OnPlayerChange.
+func (p *Processor) switchPlayer(name string, logTime time.Time, hasTime bool) {
+	oldName := p.PlayerName
+	p.changeCandidate, p.changeSightings = "", 0
+	p.SetPlayer(name)
+	msg := fmt.Sprintf(PlayerChangedPrefix+"%s → %s", oldName, name)
+	if hasTime {
+		p.AppendOutput(msg, logTime)
+	} else {
+		p.AppendOutput(msg)
+	}
+	if p.OnPlayerChange != nil {
+		p.OnPlayerChange(oldName, name)
+	}
+}
+
+// ExtractLogTimestamp finds when a log line was written. It accepts an
+// RFC 3339 timestamp with any number of fractional-second digits (or none)
+// and a Z or numeric offset such as +00:00, either in angle brackets like the
+// game writes it (<2025-01-02T10:00:00.123Z>) or as a bare field anywhere in
+// the line. The time keeps the offset it was written with. ok is false, with
+// a zero time, when the line has no timestamp; callers pick their own fallback.
+func ExtractLogTimestamp(line string) (t time.Time, ok bool) {
+	// Bracketed form, usually first but not always (e.g. after a <Tag>)
+	for rest := line; ; {
+		start := strings.IndexByte(rest, '<')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '>')
+		if end == -1 {
+			break
+		}
+		if t, ok := parseLogTime(rest[start+1 : start+end]); ok {
+			return t, true
+		}
+		rest = rest[start+end+1:]
+	}
+
+	// Fallback: look in individual fields
+	for _, f := range strings.Fields(line) {
+		if t, ok := parseLogTime(strings.Trim(f, "<>[](),;:")); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseLogTime parses s as an RFC 3339 timestamp, rejecting anything that
+// can't be one before trying.
+func parseLogTime(s string) (time.Time, bool) {
+	if len(s) < 20 || s[4] != '-' || s[10] != 'T' {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, err == nil
+}
+
+// DefaultDedupWindow is the DedupWindow of a new Processor.
+const DefaultDedupWindow = time.Second
+
+// isDuplicateLine reports whether line repeats the previous line within
+// DedupWindow. The timestamps may differ slightly; the rest must match exactly.
+// A repeat further apart is a separate event and is processed normally.
+func (p *Processor) isDuplicateLine(line string, logTime time.Time) bool {
+	if p.DedupWindow <= 0 || p.LastRawLogLine == "" || p.lastLineAt.IsZero() {
+		return false
+	}
+	if stripLogTimestamp(line) != stripLogTimestamp(p.LastRawLogLine) {
+		return false
+	}
+	gap := logTime.Sub(p.lastLineAt)
+	return gap >= 0 && gap <= p.DedupWindow
+}
+
+// stripLogTimestamp removes the leading <timestamp> from a raw log line.
+func stripLogTimestamp(line string) string {
+	if strings.HasPrefix(line, "<") {
+		if end := strings.Index(line, ">"); end > 0 {
+			return strings.TrimSpace(line[end+1:])
+		}
+	}
+	return line
+}
+
+// ProcessLogLine updates stats based on a single log line.
+func (p *Processor) ProcessLogLine(line string) {
+	logTime, hasTime := ExtractLogTimestamp(line)
+
+	// Feed lines go out with a zero time when the log line had none, so
+	// AppendOutput picks the fallback (the wall clock live, the last log
+	// time when converting); counting still needs a time, so use now
+	feedTime := logTime
+	if !hasTime {
+		logTime = time.Now()
+	}
+	// The game sometimes writes the same kill twice in a row
+	if p.isDuplicateLine(line, logTime) {
+		return
+	}
+	p.LastRawLogLine = line // NEW: always set the last raw log line
+	p.lastLineAt = logTime
+	p.lastLineWall = time.Now()
+
+	// If player name not detected yet, just return without processing events
+	if p.PlayerName == "" {
+		return
+	}
+
+	// First, flush old events that are beyond the aggregation window
+	oldMessages := p.EventAggregator.FlushOldEvents(logTime, p)
+	for _, msg := range oldMessages {
+		p.AppendOutput(msg, feedTime)
+	}
+
+	// Ignored names are dropped from the feed and stats, ahead of any NPC/pet handling
+	if involvesIgnored(line, p.PlayerName) {
+		return
+	}
+
+	var eventDetected bool
+
+	// Vehicle destruction
+	if strings.Contains(line, "CVehicle::OnAdvanceDestroyLevel") {
+		if m := vehicleRegex.FindStringSubmatch(line); len(m) == 6 {
+			fullID := m[1]
+			toLevel := m[3]
+			causeRaw := m[4]
+			weaponRaw := m[5]
+
+			details := map[string]string{"destroyLevel": toLevel}
+			// Collisions and crashes aren't anyone's kill; mark them so summaries treat them alike
+			if EnvironmentalCause(causeRaw) || EnvironmentalCause(weaponRaw) {
+				details["environmental"] = "true"
+			}
+
+			// Add to event aggregator
+			event := PendingEvent{
+				Type:        EventVehicleDestruction,
+				Timestamp:   logTime,
+				PlayerName:  p.PlayerName,
+				VehicleName: fullID,
+				Cause:       causeRaw,
+				Weapon:      weaponRaw,
+				RawLine:     line,
+				Details:     details,
+			}
+			p.EventAggregator.AddEvent(event)
+			eventDetected = true
+		}
+	}
+	// Shard and game mode; feed and status only, counts are untouched
+	if p.trackShard(line, logTime) {
+		return
+	}
+	// Player deaths and kills
+	if strings.Contains(line, "CActor::Kill:") {		// suicide
+		suicidePattern := fmt.Sprintf(`CActor::Kill: '%s'.*killed by '%s'`, regexp.QuoteMeta(p.PlayerName), regexp.QuoteMeta(p.PlayerName))
+		suicideRe := regexp.MustCompile(suicidePattern)
+		if suicideRe.MatchString(line) {
+			// The game blames the player for falls and suffocation too; the damage type tells them apart
+			damageType := lineDamageType(line)
+			killer := SelfDeathKiller(damageType)
+			weapon, damageKey := "", SuicideKiller
+			if killer == EnvironmentKiller {
+				weapon, damageKey = damageType, damageType
+			}
+			p.Stats.Deaths[killer]++
+			p.SessionStats.Deaths[killer]++
+			p.Stats.DamageTypes[damageKey]++
+			p.SessionStats.DamageTypes[damageKey]++
+			p.Streak = 0
+			p.saveStats()
+
+			// Add to event aggregator
+			event := PendingEvent{
+				Type:       EventPlayerDeath,
+				Timestamp:  logTime,
+				PlayerName: p.PlayerName,
+				Cause:      killer,
+				Weapon:     weapon,
+				RawLine:    line,
+			}
+			p.EventAggregator.AddEvent(event)
+			eventDetected = true
+			if p.OnDeath != nil {
+				p.OnDeath(DeathEvent{Player: p.PlayerName, Killer: killer, Weapon: weapon, DamageType: damageType, Timestamp: logTime})
+			}
+		} else {			// Check if this player died
+			rDeath := regexp.MustCompile(`CActor::Kill: '` + regexp.QuoteMeta(p.PlayerName) + `'.*killed by '([^']+)'(?:.*using '([^']+)')?(?:.*with damage type '([^']+)')?`)
+			if m := rDeath.FindStringSubmatch(line); len(m) > 1 {
+				killer := m[1]
+				weapon := ""
+				damageType := ""
+				if len(m) >= 3 && m[2] != "" {
+					weapon = m[2]
+				}
+				if len(m) >= 4 && m[3] != "" {
+					damageType = m[3]
+				}
+				// A collision or fall named as the killer is counted in the
+				// environmental bucket, like a self-attributed one; the feed keeps the name
+				statsKiller := killer
+				if EnvironmentalCause(killer) {
+					statsKiller = EnvironmentKiller
+					if damageType == "" {
+						damageType = killer
+					}
+				}
+
+				damageKey := damageType
+				if damageKey == "" {
+					damageKey = "Unknown"
+				}
+				p.Stats.Deaths[statsKiller]++
+				p.SessionStats.Deaths[statsKiller]++
+				p.Stats.DamageTypes[damageKey]++
+				p.SessionStats.DamageTypes[damageKey]++
+				p.Streak = 0
+				p.saveStats()
+
+				// Add to event aggregator
+				event := PendingEvent{
+					Type:       EventPlayerDeath,
+					Timestamp:  logTime,
+					PlayerName: p.PlayerName,
+					Cause:      killer,
+					Weapon:     weapon,
+					RawLine:    line,
+					Details:    map[string]string{"damageType": damageType},
+				}
+				p.EventAggregator.AddEvent(event)
+				eventDetected = true
+				if p.OnDeath != nil {
+					p.OnDeath(DeathEvent{Player: p.PlayerName, Killer: killer, Weapon: weapon, DamageType: damageType, Timestamp: logTime})
+				}
+			} else {				// kill by player with method
+				rMethod := regexp.MustCompile(`CActor::Kill: '([A-Za-z0-9_]+)'.*killed by '` + regexp.QuoteMeta(p.PlayerName) + `'.*using '([^']+)'`)
+				if m := rMethod.FindStringSubmatch(line); len(m) == 3 {
+					victim := m[1]
+					method := FriendlyWeaponName(m[2])
+					// The game can name a collision or fall as the victim; that's no one's kill
+					if EnvironmentalCause(victim) {
+						return
+					}
+					if IsFriend(victim) {
+						p.countTeamKill(victim)
+						p.AppendOutput(fmt.Sprintf(TeamKillPrefix+"You killed: %s using %s", withOrgTag(line, victim), method), feedTime)
+						if p.OnKill != nil {
+							p.OnKill(KillEvent{Killer: p.PlayerName, Victim: victim, Weapon: m[2], DamageType: lineDamageType(line), Timestamp: logTime, Friendly: true})
+						}
+						return
+					}
+					p.Stats.Kills[victim]++
+					p.SessionStats.Kills[victim]++
+					p.finishIncap(victim, logTime)
+					p.Stats.AddKillHour(logTime)
+					p.SessionStats.AddKillHour(logTime)
+					streak, newBest := p.countStreakKill()
+					p.saveStats()
+					p.AppendOutput(fmt.Sprintf("You killed: %s using %s", withOrgTag(line, victim), method), feedTime)
+					p.EventAggregator.AddEvent(PendingEvent{Type: EventPlayerKill, Timestamp: logTime, PlayerName: p.PlayerName, Cause: victim, RawLine: line})
+					if p.OnKill != nil {
+						p.OnKill(KillEvent{Killer: p.PlayerName, Victim: victim, Weapon: m[2], DamageType: lineDamageType(line), Timestamp: logTime, Streak: streak, NewBest: newBest})
+					}
+					return
+				}
+				// fallback kill by player
+				rKill := regexp.MustCompile(`CActor::Kill: '([A-Za-z0-9_]+)'.*killed by '` + regexp.QuoteMeta(p.PlayerName) + `'`)
+				if m := rKill.FindStringSubmatch(line); len(m) > 1 {
+					victim := m[1]
+					if EnvironmentalCause(victim) {
+						return
+					}
+					if IsFriend(victim) {
+						p.countTeamKill(victim)
+						p.AppendOutput(TeamKillPrefix+"You killed: "+withOrgTag(line, victim), feedTime)
+						if p.OnKill != nil {
+							p.OnKill(KillEvent{Killer: p.PlayerName, Victim: victim, DamageType: lineDamageType(line), Timestamp: logTime, Friendly: true})
+						}
+						return
+					}
+					p.Stats.Kills[victim]++
+					p.SessionStats.Kills[victim]++
+					p.finishIncap(victim, logTime)
+					p.Stats.AddKillHour(logTime)
+					p.SessionStats.AddKillHour(logTime)
+					streak, newBest := p.countStreakKill()
+					p.saveStats()
+					p.AppendOutput("You killed: "+withOrgTag(line, victim), feedTime)
+					p.EventAggregator.AddEvent(PendingEvent{Type: EventPlayerKill, Timestamp: logTime, PlayerName: p.PlayerName, Cause: victim, RawLine: line})
+					if p.OnKill != nil {
+						p.OnKill(KillEvent{Killer: p.PlayerName, Victim: victim, DamageType: lineDamageType(line), Timestamp: logTime, Streak: streak, NewBest: newBest})
+					}
+					return
+				}
+			}
+		}
+	}
+	// Actor state changes of our own character; feed only, counts are untouched
+	if corpseRegex.MatchString(line) || strings.Contains(line, "Entering control state") || actorStateRegex.MatchString(line) {
+		// Try to extract the player name from the line
+		if idx := strings.Index(line, "Player '"); idx != -1 {
+			endIdx := strings.Index(line[idx+8:], "'")
+			if endIdx != -1 {
+				extracted := line[idx+8 : idx+8+endIdx]
+				if extracted != "" && extracted == p.PlayerName {
+					// Add to event aggregator for player state changes
+					event := PendingEvent{
+						Type:       EventActorState,
+						Timestamp:  logTime,
+						PlayerName: p.PlayerName,
+						Cause:      actorStateCause(line),
+						RawLine:    line,
+					}
+					p.EventAggregator.AddEvent(event)
+					eventDetected = true
+				}
+			}
+		}
+	}
+	// Revives and respawns of our own character; feed only, counts are untouched
+	if p.PlayerName != "" {
+		if m := reviveRegex.FindStringSubmatch(line); len(m) == 3 && m[1] == p.PlayerName && m[2] != p.PlayerName {
+			p.EventAggregator.AddEvent(PendingEvent{Type: EventRevive, Timestamp: logTime, PlayerName: p.PlayerName, Cause: m[2], RawLine: line})
+			eventDetected = true
+		} else if m := respawnRegex.FindStringSubmatch(line); len(m) == 2 && m[1] == p.PlayerName {
+			p.EventAggregator.AddEvent(PendingEvent{Type: EventRevive, Timestamp: logTime, PlayerName: p.PlayerName, RawLine: line})
+			eventDetected = true
+		}
+	}
+	// Player sightings (counted only, no feed output)
+	if m := appearanceRegex.FindStringSubmatch(line); len(m) == 2 {
+		name := m[1]
+		if !strings.EqualFold(name, p.PlayerName) && !stats.IsNPCName(name) {
+			p.Stats.Appearances[name]++
+			p.SessionStats.Appearances[name]++
+			p.saveStats()
+		}
+	}
+	// Incapacitations (not aggregated, output immediately)
+	if strings.Contains(line, "Logged an incap") {
+		r := regexp.MustCompile(`nickname: ([A-Za-z0-9_]+)`)
+		if m := r.FindStringSubmatch(line); len(m) > 1 && m[1] != p.PlayerName {
+			target := m[1]
+			p.Stats.Incaps[target]++
+			p.SessionStats.Incaps[target]++
+			p.recordIncap(target, logTime)
+			p.saveStats()
+			p.AppendOutput("You incapacitated: "+target, feedTime)
+			if p.OnIncap != nil {
+				p.OnIncap(IncapEvent{Player: p.PlayerName, Target: target, Timestamp: logTime})
+			}
+			return
+		}
+	}
+	// If we detected an event that should be aggregated, don't try to create a summary yet
+	// Let events accumulate in the aggregator
+	if eventDetected {
+		// Only try to create summaries when we flush old events or when forced
+		// This allows multiple related events to accumulate before processing
+	}
+}
+
+// EventType represents different types of events that can be aggregated
+type EventType int
+
+const (
+	EventVehicleDestruction EventType = iota
+	EventPlayerDeath
+	EventVehicleSpawn
+	EventActorState
+	EventRevive
+	EventShardJoin
+	EventPlayerKill // the player killed Cause; already in the feed, kept for engagement summaries
+)
+
+// PendingEvent holds information about an event waiting to be aggregated
+type PendingEvent struct {
+	Type        EventType
+	Timestamp   time.Time
+	PlayerName  string
+	VehicleName string
+	Cause       string
+	Weapon      string
+	RawLine     string
+	Details     map[string]string
+}
+
+// EventAggregator manages combining related events into mission summaries
+type EventAggregator struct {
+	PendingEvents []PendingEvent
+	TimeWindow    time.Duration // Events within this window are considered related
+}
+
+// NewEventAggregator creates a new event aggregator with a 5-second time window
+func NewEventAggregator() *EventAggregator {
+	return &EventAggregator{
+		PendingEvents: make([]PendingEvent, 0),
+		TimeWindow:    5 * time.Second, // Events within 5 seconds are considered related
+	}
+}
+
+// AddEvent adds an event to the pending list
+func (ea *EventAggregator) AddEvent(event PendingEvent) {
+	ea.PendingEvents = append(ea.PendingEvents, event)
+}
+
+// FlushAll processes and flushes every pending event, however recent.
+func (ea *EventAggregator) FlushAll(processor *Processor) []string {
+	if len(ea.PendingEvents) == 0 {
+		return nil
+	}
+	return ea.FlushOldEvents(ea.latestEvent().Add(ea.TimeWindow+time.Second), processor)
+}
+
+// latestEvent returns the time of the most recent pending event.
+func (ea *EventAggregator) latestEvent() time.Time {
+	var latest time.Time
+	for _, event := range ea.PendingEvents {
+		if event.Timestamp.After(latest) {
+			latest = event.Timestamp
+		}
+	}
+	return latest
+}
+
+// FlushOldEvents processes and flushes events older than the time window
+func (ea *EventAggregator) FlushOldEvents(currentTime time.Time, processor *Processor) []string {
+	var messages []string
+	var remainingEvents []PendingEvent
+	var oldEvents []PendingEvent
+
+	for _, event := range ea.PendingEvents {
+		if currentTime.Sub(event.Timestamp) > ea.TimeWindow {
+			oldEvents = append(oldEvents, event)
+		} else {
+			remainingEvents = append(remainingEvents, event)
+		}
+	}
+	// Group old events by player and try to create mission summaries
+	playerEvents := make(map[string][]PendingEvent)
+	for _, event := range oldEvents {
+		playerEvents[event.PlayerName] = append(playerEvents[event.PlayerName], event)
+	}
+
+	// Create mission summaries for each player
+	for _, events := range playerEvents {
+		summary, rest := ea.createMissionSummary(events)
+		if summary != "" {
+			messages = append(messages, summary)
+		}
+		// Events the summary doesn't cover, or all of them without one, get their own lines
+		for _, event := range rest {
+			if msg := ea.CreateIndividualEventMessage(event); msg != "" {
+				messages = append(messages, msg)
+			}
+		}
+	}
+
+	ea.PendingEvents = remainingEvents
+	return messages
+}
+
+// ProcessEventsForPlayer looks for related events for a specific player and creates summaries
+func (ea *EventAggregator) ProcessEventsForPlayer(playerName string, currentTime time.Time) string {
+	var relatedEvents []PendingEvent
+	var remainingEvents []PendingEvent
+
+	for _, event := range ea.PendingEvents {
+		if event.PlayerName == playerName && currentTime.Sub(event.Timestamp) <= ea.TimeWindow {
+			relatedEvents = append(relatedEvents, event)
+		} else {
+			remainingEvents = append(remainingEvents, event)
+		}
+	}
+
+	ea.PendingEvents = remainingEvents
+
+	if len(relatedEvents) > 0 {
+		summary, _ := ea.createMissionSummary(relatedEvents)
+		return summary
+	}
+
+	return ""
+}
+
+// createMissionSummary analyzes related events and creates a coherent mission summary.
+// rest holds the events the summary doesn't cover; without a summary that's all of them.
+func (ea *EventAggregator) createMissionSummary(events []PendingEvent) (summary string, rest []PendingEvent) {
+	if len(events) == 0 {
+		return "", nil
+	}
+
+	// Sort events by timestamp
+	for i := 0; i < len(events)-1; i++ {
+		for j := i + 1; j < len(events); j++ {
+			if events[i].Timestamp.After(events[j].Timestamp) {
+				events[i], events[j] = events[j], events[i]
+			}
+		}
+	}
+
+	// Analyze the sequence of events
+	var vehicleDestroyed bool
+	var playerDied bool
+	var crashCause bool
+	var playerName string
+	var vehicleName string
+	var deathEvent PendingEvent
+
+	for _, event := range events {
+		switch event.Type {
+		case EventVehicleDestruction:
+			vehicleDestroyed = true
+			vehicleName = event.VehicleName
+			if strings.ToLower(event.Cause) == "collision" || strings.ToLower(event.Weapon) == "collision" {
+				crashCause = true
+			}
+		case EventPlayerDeath:
+			playerDied = true
+			playerName = event.PlayerName
+			deathEvent = event
+			if strings.ToLower(event.Cause) == "crash" || strings.ToLower(event.Weapon) == "crash" {
+				crashCause = true
+			}
+		}
+	}
+
+	// Create mission summary based on detected patterns
+	if vehicleDestroyed && playerDied && crashCause && playerName != "" {
+		// Keep the weapon from the death event visible even when it's folded into the summary
+		killedBy := ""
+		if weapon := deathWeapon(deathEvent); weapon != "" {
+			killedBy = fmt.Sprintf(" (killed by %s using %s)", deathEvent.Cause, weapon)
+		}
+		// The summary only covers the crash itself, so a revive in the same window still gets its own line
+		for _, event := range events {
+			if event.Type == EventRevive || event.Type == EventShardJoin {
+				rest = append(rest, event)
+			}
+		}
+		if vehicleName != "" {
+			return fmt.Sprintf("Mission Event: %s crashed their %s and died%s", playerName, FriendlyVehicleName(vehicleName), killedBy), rest
+		} else {
+			return fmt.Sprintf("Mission Event: %s died in a crash%s", playerName, killedBy), rest
+		}
+	}
+
+	if summary, rest := engagementSummary(events); summary != "" {
+		return summary, rest
+	}
+
+	// If we can't create a meaningful summary, return empty string to use individual events
+	return "", events
+}
+
+// engagementSummary sums up a fight in which the player destroyed more than one
+// vehicle, or a vehicle and someone's character, e.g. "Mission Event: You
+// destroyed 2 vehicles (Anvil Hornet F7C, Drake Cutlass Black) and killed 3
+// players". Vehicles that only reached destroy level 1 are listed as disabled.
+// It covers the player's vehicle kills and character kills; anything else,
+// like the player's own death or a vehicle lost to a collision, is left in
+// rest.
+func engagementSummary(events []PendingEvent) (summary string, rest []PendingEvent) {
+	var vehicles []string
+	// A vehicle is reported again at each destroy level; count it once, as
+	// destroyed if it got that far
+	destroyed := make(map[string]bool)
+	var players, npcs int
+	for _, event := range events {
+		switch {
+		case event.Type == EventVehicleDestruction && event.Cause != "" && event.Cause == event.PlayerName &&
+			event.Details["environmental"] != "true":
+			if _, seen := destroyed[event.VehicleName]; !seen {
+				vehicles = append(vehicles, event.VehicleName)
+			}
+			destroyed[event.VehicleName] = destroyed[event.VehicleName] || !vehicleDisabled(event)
+		case event.Type == EventPlayerKill:
+			if stats.IsNPCName(event.Cause) {
+				npcs++
+			} else {
+				players++
+			}
+		default:
+			rest = append(rest, event)
+		}
+	}
+	if len(vehicles) == 0 || (len(vehicles) == 1 && players+npcs == 0) {
+		return "", events
+	}
+
+	var destroyedNames, disabledNames []string
+	for _, vehicle := range vehicles {
+		if destroyed[vehicle] {
+			destroyedNames = append(destroyedNames, FriendlyVehicleName(vehicle))
+		} else {
+			disabledNames = append(disabledNames, FriendlyVehicleName(vehicle))
+		}
+	}
+	var clauses []string
+	if len(destroyedNames) > 0 {
+		clauses = append(clauses, fmt.Sprintf("destroyed %s (%s)", countNoun(len(destroyedNames), "vehicle"), strings.Join(destroyedNames, ", ")))
+	}
+	if len(disabledNames) > 0 {
+		clauses = append(clauses, fmt.Sprintf("disabled %s (%s)", countNoun(len(disabledNames), "vehicle"), strings.Join(disabledNames, ", ")))
+	}
+	summary = "Mission Event: You " + strings.Join(clauses, " and ")
+	var killed []string
+	if players > 0 {
+		killed = append(killed, countNoun(players, "player"))
+	}
+	if npcs > 0 {
+		killed = append(killed, countNoun(npcs, "NPC"))
+	}
+	if len(killed) > 0 {
+		// "destroyed …, disabled … and killed …" rather than a chain of "and"s
+		if len(clauses) > 1 {
+			summary = "Mission Event: You " + strings.Join(clauses, ", ")
+		}
+		summary += " and killed " + strings.Join(killed, " and ")
+	}
+	return summary, rest
+}
+
+// vehicleDisabled reports whether a vehicle destruction event only took the
+// vehicle to destroy level 1, the soft death that leaves it disabled but in
+// one piece. Level 2 and anything unrecognised count as destroyed.
+func vehicleDisabled(event PendingEvent) bool {
+	return event.Details["destroyLevel"] == "1"
+}
+
+// vehicleOutcome is the verb for a vehicle destruction event: "disabled" for
+// a soft death, "destroyed" otherwise.
+func vehicleOutcome(event PendingEvent) string {
+	if vehicleDisabled(event) {
+		return "disabled"
+	}
+	return "destroyed"
+}
+
+// countNoun formats a count with its noun, pluralized with a trailing "s".
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// CreateIndividualEventMessage creates a message for a single event that couldn't be aggregated
+func (ea *EventAggregator) CreateIndividualEventMessage(event PendingEvent) string {
+	switch event.Type {
+	case EventVehicleDestruction:
+		if event.VehicleName != "" {
+			return fmt.Sprintf("Vehicle %s was %s by %s", FriendlyVehicleName(event.VehicleName), vehicleOutcome(event), event.Cause)
+		}
+		return fmt.Sprintf("Vehicle was %s by %s", vehicleOutcome(event), event.Cause)
+	case EventPlayerDeath:
+		killer := withOrgTag(event.RawLine, event.Cause)
+		if weapon := deathWeapon(event); weapon != "" {
+			return fmt.Sprintf("You were killed by: %s using %s", killer, weapon)
+		}
+		return fmt.Sprintf("You died by %s", killer)
+	case EventRevive:
+		if event.Cause != "" {
+			return "You were revived by " + withOrgTag(event.RawLine, event.Cause)
+		}
+		return "You respawned"
+	case EventPlayerKill:
+		// The kill had its own line when it happened; it's only pending for summaries
+		return ""
+	case EventShardJoin:
+		if shard := event.Details["shard"]; shard != "" {
+			return fmt.Sprintf("Joined shard %s (%s)", shard, event.Cause)
+		}
+		return fmt.Sprintf("Joined %s match", event.Cause)
+	case EventActorState:
+		switch event.Cause {
+		case ActorStateCorpse:
+			return "You turned to a corpse"
+		case ActorStateIncap:
+			return "You were incapacitated"
+		case ActorStateBledOut:
+			return "You bled out"
+		case ActorStateHardDeath:
+			return "You suffered a hard death"
+		}
+		return fmt.Sprintf("You %s", event.Cause)
+	default:
+		return event.RawLine
+	}
+}
+
+// lineDamageType extracts the "with damage type '...'" value from a raw kill line, if present.
+func lineDamageType(line string) string {
+	if m := damageTypeRegex.FindStringSubmatch(line); len(m) > 1 {
+		return m[1]
+	}
+	return ""
+}
+
+// deathWeapon returns the display name of what killed the player, falling back to the
+// damage type (e.g. "Collision") when the log doesn't name a weapon.
+func deathWeapon(event PendingEvent) string {
+	weapon := event.Weapon
+	if weapon == "" || strings.EqualFold(weapon, "unknown") {
+		weapon = event.Details["damageType"]
+	}
+	if weapon == "" || strings.EqualFold(weapon, "unknown") {
+		return ""
+	}
+	return FriendlyWeaponName(weapon)
+}
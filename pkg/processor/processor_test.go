@@ -0,0 +1,275 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"game-monitor/pkg/stats"
+)
+
+// newTestProcessor returns an offline Processor with its stats kept in a
+// temporary folder, and the feed lines it appends.
+func newTestProcessor(t *testing.T) (*Processor, *[]string) {
+	t.Helper()
+	stats.SetDir(t.TempDir())
+	t.Cleanup(func() { stats.SetDir("") })
+	p := New(nil, nil)
+	p.Offline = true
+	var lines []string
+	p.AppendOutput = func(line string, logTime ...time.Time) {
+		lines = append(lines, line)
+	}
+	return p, &lines
+}
+
+func TestPlayerChangeNeedsLoginLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantSwitch bool
+	}{
+		{"login line", `<2025-01-01T10:00:00.000Z> <Expect Incoming Connection> nickname="Other" playerGEID=1`, true},
+		{"inventory line", `<2025-01-01T10:00:00.000Z> Requesting inventory for Player[Other]`, false},
+		{"legacy character line", `<2025-01-01T10:00:00.000Z> Character: name Other`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProcessor(t)
+			p.FollowPlayerChanges = true
+			p.DetectPlayerName(`<2025-01-01T09:00:00.000Z> nickname="Me" playerGEID=2`)
+			for i := 0; i < playerChangeSightings; i++ {
+				p.DetectPlayerName(tt.line)
+			}
+			if got := p.PlayerName == "Other"; got != tt.wantSwitch {
+				t.Errorf("switched = %v, want %v (player %q)", got, tt.wantSwitch, p.PlayerName)
+			}
+		})
+	}
+}
+
+func TestRedetectNeedsLoginLine(t *testing.T) {
+	p, _ := newTestProcessor(t)
+	p.DetectPlayerName(`nickname="Me"`)
+	p.ResetPlayerDetection()
+	p.DetectPlayerName(`Requesting inventory for Player[Other]`)
+	if p.PlayerName != "Me" {
+		t.Fatalf("Player[…] line switched the player to %q", p.PlayerName)
+	}
+	p.DetectPlayerName(`nickname="Other"`)
+	if p.PlayerName != "Other" {
+		t.Errorf("player = %q, want Other", p.PlayerName)
+	}
+}
+
+func TestExtractLogTimestamp(t *testing.T) {
+	utc := func(sec, nsec int) time.Time { return time.Date(2025, 1, 2, 10, 0, sec, nsec, time.UTC) }
+	tests := []struct {
+		name   string
+		line   string
+		want   time.Time
+		wantOK bool
+	}{
+		{"bracketed first", "<2025-01-02T10:00:05.123Z> [Notice] <Actor Death>", utc(5, 123000000), true},
+		{"no fraction", "<2025-01-02T10:00:05Z> line", utc(5, 0), true},
+		{"one fraction digit", "<2025-01-02T10:00:05.1Z> line", utc(5, 100000000), true},
+		{"nanosecond fraction", "<2025-01-02T10:00:05.123456789Z> line", utc(5, 123456789), true},
+		{"numeric offset", "<2025-01-02T10:00:05.000+00:00> line", utc(5, 0), true},
+		{"after another tag", "<Actor Death> <2025-01-02T10:00:05Z> CActor::Kill", utc(5, 0), true},
+		{"bare field", "[Notice] at 2025-01-02T10:00:05Z: kill", utc(5, 0), true},
+		{"bare field in brackets", "Kill [2025-01-02T10:00:05Z]", utc(5, 0), true},
+		{"no timestamp", "<Actor Death> CActor::Kill: 'A' killed 'B'", time.Time{}, false},
+		{"empty line", "", time.Time{}, false},
+		{"date only", "<2025-01-02> line", time.Time{}, false},
+		{"unclosed bracket", "<2025-01-02T10:00:05Z", utc(5, 0), true},
+		{"invalid date", "<2025-13-02T10:00:05Z> line", time.Time{}, false},
+		{"missing zone", "<2025-01-02T10:00:05.123> line", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractLogTimestamp(tt.line)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("ExtractLogTimestamp(%q) = %v, %v; want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestExtractLogTimestampKeepsOffset(t *testing.T) {
+	got, ok := ExtractLogTimestamp("<2025-01-02T12:00:00+02:00> line")
+	if !ok {
+		t.Fatal("timestamp not found")
+	}
+	if _, offset := got.Zone(); offset != 2*60*60 {
+		t.Errorf("offset = %ds, want +02:00", offset)
+	}
+	if want := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("time = %v, want %v", got, want)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	const kill = "[Notice] <Actor Death> CActor::Kill: 'Victim_1' [1] killed by 'Me' [2] using 'gun'"
+	tests := []struct {
+		name      string
+		window    time.Duration
+		second    string
+		wantKills int
+	}{
+		{"same time", time.Second, "<2025-01-02T10:00:00.000Z> " + kill, 1},
+		{"inside the window", time.Second, "<2025-01-02T10:00:00.800Z> " + kill, 1},
+		{"at the window edge", time.Second, "<2025-01-02T10:00:01.000Z> " + kill, 1},
+		{"outside the window", time.Second, "<2025-01-02T10:00:01.200Z> " + kill, 2},
+		{"dedup off", 0, "<2025-01-02T10:00:00.000Z> " + kill, 2},
+		{"different line", time.Second, "<2025-01-02T10:00:00.100Z> " + strings.Replace(kill, "Victim_1", "Victim_2", 1), 2},
+		{"earlier timestamp", time.Second, "<2025-01-02T09:59:59.900Z> " + kill, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProcessor(t)
+			p.PlayerName = "Me"
+			p.DedupWindow = tt.window
+			p.ProcessLogLine("<2025-01-02T10:00:00.000Z> " + kill)
+			p.ProcessLogLine(tt.second)
+			if got := p.SessionStats.TotalKills(); got != tt.wantKills {
+				t.Errorf("kills = %d, want %d", got, tt.wantKills)
+			}
+		})
+	}
+}
+
+func TestFeedTimeOfUntimestampedLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want time.Time
+	}{
+		{"timestamped", "<2025-01-02T10:00:05.000Z> CActor::Kill: 'Victim_1' [1] killed by 'Me' [2]", time.Date(2025, 1, 2, 10, 0, 5, 0, time.UTC)},
+		{"no timestamp", "CActor::Kill: 'Victim_1' [1] killed by 'Me' [2]", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := newTestProcessor(t)
+			p.PlayerName = "Me"
+			var got []time.Time
+			p.AppendOutput = func(line string, logTime ...time.Time) {
+				if len(logTime) == 0 {
+					t.Fatalf("%q appended without a time", line)
+				}
+				got = append(got, logTime[0])
+			}
+			p.ProcessLogLine(tt.line)
+			if len(got) != 1 || !got[0].Equal(tt.want) {
+				t.Errorf("feed times = %v, want [%v]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngagementSummary(t *testing.T) {
+	vehicle := func(id, level string) PendingEvent {
+		return PendingEvent{Type: EventVehicleDestruction, PlayerName: "Me", Cause: "Me", VehicleName: id, Details: map[string]string{"destroyLevel": level}}
+	}
+	kill := func(victim string) PendingEvent {
+		return PendingEvent{Type: EventPlayerKill, PlayerName: "Me", Cause: victim}
+	}
+	death := PendingEvent{Type: EventPlayerDeath, PlayerName: "Me", Cause: "Enemy_1"}
+	tests := []struct {
+		name     string
+		events   []PendingEvent
+		want     string
+		wantRest int
+	}{
+		{
+			name:     "single vehicle alone is left to the vehicle message",
+			events:   []PendingEvent{vehicle("ANVL_Arrow_1", "2")},
+			want:     "",
+			wantRest: 1,
+		},
+		{
+			name:   "multiple vehicles",
+			events: []PendingEvent{vehicle("ANVL_Arrow_1", "2"), vehicle("AEGS_Gladius_2", "2")},
+			want:   "Mission Event: You destroyed 2 vehicles (Anvil Arrow, Aegis Gladius)",
+		},
+		{
+			name:   "vehicle and players",
+			events: []PendingEvent{vehicle("ANVL_Arrow_1", "2"), kill("Pilot_1"), kill("Gunner_2")},
+			want:   "Mission Event: You destroyed 1 vehicle (Anvil Arrow) and killed 2 players",
+		},
+		{
+			name:   "vehicle, player and NPC",
+			events: []PendingEvent{vehicle("ANVL_Arrow_1", "2"), kill("Pilot_1"), kill("PU_Human_Enemy_GroundCombat_NPC_1")},
+			want:   "Mission Event: You destroyed 1 vehicle (Anvil Arrow) and killed 1 player and 1 NPC",
+		},
+		{
+			name:   "repeated destroy levels count the vehicle once",
+			events: []PendingEvent{vehicle("ANVL_Arrow_1", "1"), vehicle("ANVL_Arrow_1", "2"), vehicle("AEGS_Gladius_2", "1")},
+			want:   "Mission Event: You destroyed 1 vehicle (Anvil Arrow) and disabled 1 vehicle (Aegis Gladius)",
+		},
+		{
+			name:   "destroyed, disabled and killed",
+			events: []PendingEvent{vehicle("ANVL_Arrow_1", "2"), vehicle("AEGS_Gladius_2", "1"), kill("Pilot_1")},
+			want:   "Mission Event: You destroyed 1 vehicle (Anvil Arrow), disabled 1 vehicle (Aegis Gladius) and killed 1 player",
+		},
+		{
+			name:     "other events are passed on",
+			events:   []PendingEvent{vehicle("ANVL_Arrow_1", "2"), kill("Pilot_1"), death},
+			want:     "Mission Event: You destroyed 1 vehicle (Anvil Arrow) and killed 1 player",
+			wantRest: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest := engagementSummary(tt.events)
+			if got != tt.want {
+				t.Errorf("summary = %q, want %q", got, tt.want)
+			}
+			if len(rest) != tt.wantRest {
+				t.Errorf("rest = %d events, want %d", len(rest), tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestReviveAndRespawn(t *testing.T) {
+	const ts = "<2025-01-02T10:00:00.000Z> "
+	tests := []struct {
+		name   string
+		player string
+		line   string
+		want   string // "" when no feed line is expected
+	}{
+		{"revived by a medic", "Me", ts + "[Notice] <Actor Revived> CActor::Revive: 'Me' [200] in zone 'Stanton2_Orison' revived by 'Medic_One' [201]", "You were revived by Medic_One"},
+		{"respawn", "Me", ts + "[Notice] <Spawn Flow> CSCPlayerPUSpawningComponent::OnSpawn: Player 'Me' [200] spawned at 'Orison General'", "You respawned"},
+		{"someone else revived by us", "Me", ts + "[Notice] <Actor Revived> CActor::Revive: 'Other' [201] in zone 'Stanton2_Orison' revived by 'Me' [200]", ""},
+		{"someone else respawning", "Me", ts + "[Notice] <Spawn Flow> CSCPlayerPUSpawningComponent::OnSpawn: Player 'Other' [201] spawned at 'Orison General'", ""},
+		{"unrelated line naming us", "Me", ts + "[Notice] <Party Invite> Player 'Me' invited by 'Other' to respawn together", ""},
+		{"no player yet", "", ts + "[Notice] <Spawn Flow> CSCPlayerPUSpawningComponent::OnSpawn: Player '' [200] spawned at 'Orison General'", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, lines := newTestProcessor(t)
+			p.PlayerName = tt.player
+			p.ProcessLogLine(tt.line)
+			p.FlushPending()
+			var got []string
+			for _, line := range *lines {
+				if strings.Contains(line, "revived") || strings.Contains(line, "respawned") {
+					got = append(got, line)
+				}
+			}
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("feed = %q, want no revive or respawn", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("feed = %q, want one line containing %q", got, tt.want)
+			}
+			if kills, deaths := p.SessionStats.TotalKills(), p.SessionStats.TotalDeaths(); kills != 0 || deaths != 0 {
+				t.Errorf("kills, deaths = %d, %d, want 0, 0", kills, deaths)
+			}
+		})
+	}
+}
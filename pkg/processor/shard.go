@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// "<Join PU> address[...] port[...] shard[pub_use1b_7940467_100] ..."
+	joinShardRegex = regexp.MustCompile(`<Join PU>.*?\bshard\[([^\]]+)\]`)
+	// "<Context Establisher Done> ... map="megamap" gamerules="SC_Default" ..."
+	gameRulesRegex = regexp.MustCompile(`<Context Establisher Done>.*?\bgamerules="([^"]+)"`)
+)
+
+// Game modes reported by GameModeName for the common game rules.
+const (
+	GameModePU    = "PU"
+	GameModeMenu  = "Menu"
+	GameModeArena = "Arena Commander"
+)
+
+// GameModeName turns a gamerules value from the log into a readable mode.
+func GameModeName(rules string) string {
+	switch {
+	case rules == "SC_Default":
+		return GameModePU
+	case rules == "SC_Frontend":
+		return GameModeMenu
+	case strings.HasPrefix(rules, "EA_"):
+		return GameModeArena + ": " + cleanName(strings.TrimPrefix(rules, "EA_"))
+	default:
+		return cleanName(rules)
+	}
+}
+
+// trackShard updates Shard and GameMode from a join or context line and
+// reports whether the line was one. Joining a shard or a match adds a feed
+// line; returning to the menu only clears the shard.
+func (p *Processor) trackShard(line string, logTime time.Time) bool {
+	var shard, mode string
+	if m := joinShardRegex.FindStringSubmatch(line); len(m) == 2 {
+		shard, mode = m[1], GameModePU
+	} else if m := gameRulesRegex.FindStringSubmatch(line); len(m) == 2 {
+		mode = GameModeName(m[1])
+		if mode == GameModePU {
+			// The shard follows in its own <Join PU> line
+			p.GameMode = mode
+			return true
+		}
+	} else {
+		return false
+	}
+	if shard == p.Shard && mode == p.GameMode {
+		return true
+	}
+	p.Shard, p.GameMode = shard, mode
+	if mode != GameModeMenu {
+		p.EventAggregator.AddEvent(PendingEvent{
+			Type:       EventShardJoin,
+			Timestamp:  logTime,
+			PlayerName: p.PlayerName,
+			Cause:      mode,
+			RawLine:    line,
+			Details:    map[string]string{"shard": shard},
+		})
+	}
+	if p.OnShardChange != nil {
+		p.OnShardChange(shard, mode)
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Timestamp layout presets offered in the Config tab.
+const (
+	TimestampFormat24h      = "2006-01-02 15:04:05"
+	TimestampFormat12h      = "2006-01-02 03:04:05 PM"
+	TimestampFormatDateOnly = "2006-01-02"
+)
+
+// TimestampPresets lists the preset layouts in display order.
+var TimestampPresets = []string{TimestampFormat24h, TimestampFormat12h, TimestampFormatDateOnly}
+
+var (
+	timestampMu       sync.RWMutex
+	timestampLayout   = TimestampFormat24h
+	timestampLocation = time.Local
+)
+
+// SetTimestampFormat changes the layout used by FormatTimestamp. An empty
+// layout restores the 24h default.
+func SetTimestampFormat(layout string) {
+	if layout == "" {
+		layout = TimestampFormat24h
+	}
+	timestampMu.Lock()
+	timestampLayout = layout
+	timestampMu.Unlock()
+}
+
+// TimestampFormat returns the layout currently used by FormatTimestamp.
+func TimestampFormat() string {
+	timestampMu.RLock()
+	defer timestampMu.RUnlock()
+	return timestampLayout
+}
+
+// SetTimestampLocation sets the IANA time zone used by FormatTimestamp.
+// An empty name or "Local" selects the system zone. Unknown names fall back
+// to the system zone and return an error describing the problem.
+func SetTimestampLocation(name string) error {
+	loc := time.Local
+	var err error
+	if name != "" && name != "Local" {
+		if loc, err = time.LoadLocation(name); err != nil {
+			loc = time.Local
+			err = fmt.Errorf("unknown time zone %q, using local time", name)
+		}
+	}
+	timestampMu.Lock()
+	timestampLocation = loc
+	timestampMu.Unlock()
+	return err
+}
+
+// TimestampLocation returns the zone currently used by FormatTimestamp.
+func TimestampLocation() *time.Location {
+	timestampMu.RLock()
+	defer timestampMu.RUnlock()
+	return timestampLocation
+}
+
+// FormatTimestamp renders t in the configured zone using the configured layout.
+// Only the rendering changes; stored times stay in UTC.
+func FormatTimestamp(t time.Time) string {
+	return t.In(TimestampLocation()).Format(TimestampFormat())
+}
+
+// ValidTimestampFormat reports whether layout contains at least one time
+// element, so it doesn't render as a fixed string.
+func ValidTimestampFormat(layout string) bool {
+	return layout != "" && time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(layout) != layout
+}
+
+// ParseTimestampPrefix parses a timestamp rendered by FormatTimestamp at the
+// start of s, trying the current layout first and then every preset.
+func ParseTimestampPrefix(s string) (time.Time, bool) {
+	layouts := append([]string{TimestampFormat()}, TimestampPresets...)
+	for _, layout := range layouts {
+		if len(s) < len(layout) {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, s[:len(layout)], TimestampLocation()); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
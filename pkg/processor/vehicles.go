@@ -0,0 +1,31 @@
+package processor
+
+import "strings"
+
+// FriendlyVehicleName converts an internal vehicle identifier such as
+// "ORIG_300i_3425567" into a readable name like "Origin 300i". Known ships are
+// looked up directly; otherwise the manufacturer prefix is expanded. Unknown
+// identifiers fall back to cleanName. Both tables come from vehiclenames.json.
+func FriendlyVehicleName(raw string) string {
+	names := currentMappings()
+	trimmed := strings.ReplaceAll(strings.TrimSpace(raw), " ", "_")
+
+	// Strip per-entity numeric suffixes, checking for a full match at each step
+	for trimmed != "" {
+		if name, ok := names.vehicles[strings.ToLower(trimmed)]; ok {
+			return name
+		}
+		next := instanceSuffixRegex.ReplaceAllString(trimmed, "")
+		if next == trimmed {
+			break
+		}
+		trimmed = next
+	}
+
+	if prefix, model, ok := strings.Cut(trimmed, "_"); ok && model != "" {
+		if manufacturer, known := names.manufacturers[strings.ToLower(prefix)]; known {
+			return manufacturer + " " + strings.ReplaceAll(model, "_", " ")
+		}
+	}
+	return cleanName(raw)
+}
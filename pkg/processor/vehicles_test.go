@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+)
+
+// vehicleLine returns a raw destroy-level line for vehicle going from level
+// from to level to, caused by cause with weapon.
+func vehicleLine(sec int, vehicle string, from, to int, cause, weapon string) string {
+	return fmt.Sprintf("<2025-01-02T10:00:%02d.000Z> [Notice] <Vehicle Destruction> CVehicle::OnAdvanceDestroyLevel: Vehicle '%s' [1] in zone 'space' [pos x: 1.0, y: 2.0, z: 3.0 vel x: 0, y: 0, z: 0] driven by 'Pilot_1' [2] advanced from destroy level %d to %d caused by '%s' [3] with '%s' [Team_NONE][Combat]",
+		sec, vehicle, from, to, cause, weapon)
+}
+
+func TestVehicleDestroyLevels(t *testing.T) {
+	tests := []struct {
+		name string
+		to   int
+		want string
+	}{
+		{"soft death", 1, "Vehicle Anvil Arrow was disabled by Me"},
+		{"full destruction", 2, "Vehicle Anvil Arrow was destroyed by Me"},
+		{"unknown level", 3, "Vehicle Anvil Arrow was destroyed by Me"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, lines := newTestProcessor(t)
+			p.PlayerName = "Me"
+			p.ProcessLogLine(vehicleLine(0, "ANVL_Arrow_1234", tt.to-1, tt.to, "Me", "Combat"))
+			p.FlushPending()
+			if len(*lines) != 1 || (*lines)[0] != tt.want {
+				t.Errorf("feed = %q, want [%q]", *lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFriendlyVehicleName(t *testing.T) {
+	builtinMappings(t)
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		// Known ships
+		{"DRAK_Cutlass_Black", "Drake Cutlass Black"},
+		{"DRAK_Cutlass_Black_3425567", "Drake Cutlass Black"},
+		{"RSI_Aurora_MR_12_34", "RSI Aurora MR"},
+		{"ANVL_Hornet_F7CM_Mk2_Heart_99", "Anvil F7C-M Super Hornet Heartseeker Mk II"},
+		// Manufacturer prefix fallback
+		{"ORIG_300i_3425567", "Origin 300i"},
+		{"AEGS_Gladius_1", "Aegis Gladius"},
+		{"ANVL_Arrow", "Anvil Arrow"},
+		{"MISC_Prospector_77", "MISC Prospector"},
+		// Unknown IDs keep the numeric-suffix stripping
+		{"ZZZZ_Thing_12", "ZZZZ Thing"},
+		{"Shuttle_42", "Shuttle"},
+		{"Unknown", "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := FriendlyVehicleName(tt.raw); got != tt.want {
+			t.Errorf("FriendlyVehicleName(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
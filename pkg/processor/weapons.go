@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// instanceSuffixRegex matches a trailing "_<digits>" group.
+var instanceSuffixRegex = regexp.MustCompile(`_[0-9]+$`)
+
+// FriendlyWeaponName converts an internal weapon identifier such as
+// "behr_rifle_ballistic_01_4263453" into its in-game display name. Numeric
+// suffixes are stripped one at a time until a known name matches; unknown
+// weapons fall back to cleanName. Names come from weaponnames.json.
+func FriendlyWeaponName(raw string) string {
+	names := currentMappings()
+	key := strings.ToLower(strings.TrimSpace(raw))
+	for key != "" {
+		if name, ok := names.weapons[key]; ok {
+			return name
+		}
+		trimmed := instanceSuffixRegex.ReplaceAllString(key, "")
+		if trimmed == key {
+			break
+		}
+		key = trimmed
+	}
+	return cleanName(raw)
+}
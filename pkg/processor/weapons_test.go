@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"testing"
+
+	"game-monitor/pkg/stats"
+)
+
+// builtinMappings makes the test use the built-in weapon and vehicle names
+// only, whatever an earlier test loaded.
+func builtinMappings(t *testing.T) {
+	t.Helper()
+	stats.SetDir(t.TempDir())
+	t.Cleanup(func() { stats.SetDir("") })
+	if err := ReloadMappings(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFriendlyWeaponName(t *testing.T) {
+	builtinMappings(t)
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"klwe_rifle_energy_01", "Klaus & Werner Gallant Rifle"},
+		{"behr_rifle_ballistic_01", "Behring P4-AR Rifle"},
+		{"klwe_rifle_energy_01_5012345", "Klaus & Werner Gallant Rifle"},
+		{"klwe_rifle_energy_01_12_34", "Klaus & Werner Gallant Rifle"},
+		{" KLWE_Rifle_Energy_01_77 ", "Klaus & Werner Gallant Rifle"},
+		{"behr_lasercannon_s3_9876", "Behring M5A Laser Cannon"},
+		{"abcd_widget_07_55", "abcd widget 07"},
+		{"Unknown", "Unknown"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := FriendlyWeaponName(tt.raw); got != tt.want {
+			t.Errorf("FriendlyWeaponName(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
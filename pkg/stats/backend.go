@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"game-monitor/pkg/fsutil"
+)
+
+// Backend stores players' all-time stats. The <player>_stats.json files in
+// Dir are the default; SetBackend switches to another store such as SQLite.
+type Backend interface {
+	// Load returns player's stats, or an error wrapping os.ErrNotExist if
+	// there are none.
+	Load(player string) (Stats, error)
+	Save(player string, s Stats) error
+	// Players returns the players with saved stats, sorted by name.
+	Players() ([]string, error)
+	// Reset replaces player's stats with empty ones, keeping the previous
+	// stats, if any, until the next Reset.
+	Reset(player string) error
+	// Restore puts back the stats the last Reset replaced. It returns an
+	// error wrapping os.ErrNotExist if there are none.
+	Restore(player string) error
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend // nil for the JSON files
+)
+
+// SetBackend makes b the stats backend. A nil b restores the JSON files.
+func SetBackend(b Backend) {
+	backendMu.Lock()
+	backend = b
+	backendMu.Unlock()
+}
+
+// currentBackend returns the backend Load, Save and friends use.
+func currentBackend() Backend {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	if backend == nil {
+		return jsonFiles{}
+	}
+	return backend
+}
+
+// CopyMissing copies the stats of every player with a JSON stats file into
+// b, unless b already has stats for them, so switching backends doesn't start
+// everyone from zero. It returns the number of players copied.
+func CopyMissing(b Backend) (int, error) {
+	players, err := jsonFiles{}.Players()
+	if err != nil {
+		return 0, err
+	}
+	copied := 0
+	for _, player := range players {
+		if _, err := b.Load(player); !errors.Is(err, os.ErrNotExist) {
+			if err != nil {
+				return copied, err
+			}
+			continue
+		}
+		s, err := jsonFiles{}.Load(player)
+		if err != nil {
+			return copied, err
+		}
+		if err := b.Save(player, s); err != nil {
+			return copied, fmt.Errorf("failed to copy stats for %s: %w", player, err)
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// jsonFiles keeps each player's stats in <player>_stats.json in the stats
+// dir, with the copy kept by Reset in <player>_stats.bak.json.
+type jsonFiles struct{}
+
+// resetBackupFile returns the path of the copy of a player's stats kept by
+// Reset.
+func resetBackupFile(player string) string {
+	return filepath.Join(getStatsDir(), fsutil.SanitizeFilename(player)+"_stats.bak.json")
+}
+
+func (jsonFiles) Load(player string) (Stats, error) {
+	f, err := os.Open(statsFile(player))
+	if err != nil {
+		return Stats{}, err
+	}
+	defer f.Close()
+	var s Stats
+	if err := json.NewDecoder(f).Decode(&s); err != nil {
+		return Stats{}, err
+	}
+	return s, nil
+}
+
+func (jsonFiles) Save(player string, s Stats) error {
+	f, err := os.Create(statsFile(player))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(s)
+}
+
+func (jsonFiles) Players() ([]string, error) {
+	entries, err := os.ReadDir(getStatsDir())
+	if err != nil {
+		return nil, err
+	}
+	var players []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), "_stats.json"); ok && !entry.IsDir() && name != "" {
+			players = append(players, name)
+		}
+	}
+	sort.Strings(players)
+	return players, nil
+}
+
+func (j jsonFiles) Reset(player string) error {
+	err := os.Rename(statsFile(player), resetBackupFile(player))
+	if errors.Is(err, os.ErrNotExist) {
+		// Nothing to keep; an older backup would restore the wrong stats
+		err = os.Remove(resetBackupFile(player))
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return j.Save(player, New())
+}
+
+func (jsonFiles) Restore(player string) error {
+	return os.Rename(resetBackupFile(player), statsFile(player))
+}
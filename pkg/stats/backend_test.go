@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// memBackend is an in-memory Backend for tests.
+type memBackend struct {
+	stats   map[string]Stats
+	backups map[string]Stats
+}
+
+func newMemBackend() *memBackend {
+	return &memBackend{stats: make(map[string]Stats), backups: make(map[string]Stats)}
+}
+
+func (m *memBackend) Load(player string) (Stats, error) {
+	s, ok := m.stats[player]
+	if !ok {
+		return Stats{}, os.ErrNotExist
+	}
+	return s, nil
+}
+
+func (m *memBackend) Save(player string, s Stats) error {
+	m.stats[player] = s
+	return nil
+}
+
+func (m *memBackend) Players() ([]string, error) {
+	var players []string
+	for player := range m.stats {
+		players = append(players, player)
+	}
+	sort.Strings(players)
+	return players, nil
+}
+
+func (m *memBackend) Reset(player string) error {
+	if s, ok := m.stats[player]; ok {
+		m.backups[player] = s
+	} else {
+		delete(m.backups, player)
+	}
+	m.stats[player] = New()
+	return nil
+}
+
+func (m *memBackend) Restore(player string) error {
+	s, ok := m.backups[player]
+	if !ok {
+		return os.ErrNotExist
+	}
+	delete(m.backups, player)
+	m.stats[player] = s
+	return nil
+}
+
+// useTempDir points the stats dir at a temp dir for the test.
+func useTempDir(t *testing.T) {
+	t.Helper()
+	SetDir(t.TempDir())
+	t.Cleanup(func() { SetDir("") })
+}
+
+func withKills(kills map[string]int) Stats {
+	s := New()
+	for name, c := range kills {
+		s.Kills[name] = c
+	}
+	return s
+}
+
+func TestBackendSelection(t *testing.T) {
+	useTempDir(t)
+	mem := newMemBackend()
+	if err := Save("Pilot", withKills(map[string]int{"Victim_1": 2})); err != nil {
+		t.Fatal(err)
+	}
+
+	SetBackend(mem)
+	t.Cleanup(func() { SetBackend(nil) })
+	if got := Load("Pilot"); got.TotalKills() != 0 {
+		t.Errorf("Load() from the new backend = %d kills, want 0", got.TotalKills())
+	}
+	if err := Save("Pilot", withKills(map[string]int{"Victim_2": 5})); err != nil {
+		t.Fatal(err)
+	}
+	if got := mem.stats["Pilot"].Kills["Victim_2"]; got != 5 {
+		t.Errorf("Save() didn't reach the backend, got %d kills of Victim_2", got)
+	}
+
+	// Back on the JSON files the earlier file is untouched
+	SetBackend(nil)
+	if got := Load("Pilot"); !reflect.DeepEqual(got.Kills, map[string]int{"Victim_1": 2}) {
+		t.Errorf("Load() from the JSON files = %v, want Victim_1: 2", got.Kills)
+	}
+}
+
+func TestResetAndUndo(t *testing.T) {
+	backends := []struct {
+		name string
+		b    Backend
+	}{
+		{"json files", nil},
+		{"memory", newMemBackend()},
+	}
+	for _, tt := range backends {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempDir(t)
+			SetBackend(tt.b)
+			t.Cleanup(func() { SetBackend(nil) })
+			if err := Save("Pilot", withKills(map[string]int{"Victim_1": 3})); err != nil {
+				t.Fatal(err)
+			}
+			if err := ResetAllTime("Pilot"); err != nil {
+				t.Fatalf("ResetAllTime() error = %v", err)
+			}
+			if got := Load("Pilot").TotalKills(); got != 0 {
+				t.Errorf("kills after reset = %d, want 0", got)
+			}
+			restored, err := UndoReset("Pilot")
+			if err != nil {
+				t.Fatalf("UndoReset() error = %v", err)
+			}
+			if got := restored.TotalKills(); got != 3 {
+				t.Errorf("kills after undo = %d, want 3", got)
+			}
+			if _, err := UndoReset("Pilot"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("second UndoReset() error = %v, want os.ErrNotExist", err)
+			}
+			// Resetting a player without stats leaves nothing to undo
+			if err := ResetAllTime("Nobody"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := UndoReset("Nobody"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("UndoReset() of a new player error = %v, want os.ErrNotExist", err)
+			}
+		})
+	}
+}
+
+func TestCopyMissing(t *testing.T) {
+	useTempDir(t)
+	for player, kills := range map[string]int{"Alpha": 1, "Bravo": 2} {
+		if err := Save(player, withKills(map[string]int{"Victim": kills})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mem := newMemBackend()
+	mem.stats["Bravo"] = withKills(map[string]int{"Victim": 9})
+
+	copied, err := CopyMissing(mem)
+	if err != nil {
+		t.Fatalf("CopyMissing() error = %v", err)
+	}
+	if copied != 1 {
+		t.Errorf("CopyMissing() copied %d players, want 1", copied)
+	}
+	if got := mem.stats["Alpha"].Kills["Victim"]; got != 1 {
+		t.Errorf("Alpha has %d kills in the backend, want 1", got)
+	}
+	if got := mem.stats["Bravo"].Kills["Victim"]; got != 9 {
+		t.Errorf("Bravo's existing stats were replaced: %d kills, want 9", got)
+	}
+	if players := ListPlayers(); !reflect.DeepEqual(players, []string{"Alpha", "Bravo"}) {
+		t.Errorf("ListPlayers() = %v, want [Alpha Bravo]", players)
+	}
+}
@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// backupVersion is bumped when the Backup format changes incompatibly.
+const backupVersion = 1
+
+// Backup bundles every player's all-time stats, and optionally the session
+// log, into one file so a record can be moved to another PC.
+type Backup struct {
+	Version  int              `json:"version"`
+	Created  time.Time        `json:"created"`
+	Players  map[string]Stats `json:"players"`
+	Sessions []Session        `json:"sessions,omitempty"`
+}
+
+// ImportMode selects how ImportBackup treats stats that already exist locally.
+type ImportMode int
+
+const (
+	// ImportMerge adds the backup's counts to the local ones.
+	ImportMerge ImportMode = iota
+	// ImportReplace overwrites local files with the backup's contents.
+	ImportReplace
+)
+
+// Merge adds every count in other to s.
+func (s *Stats) Merge(other Stats) {
+	s.ensureMaps()
+	mergeCounts(s.Kills, other.Kills)
+	mergeCounts(s.Deaths, other.Deaths)
+	mergeCounts(s.Incaps, other.Incaps)
+	mergeCounts(s.Appearances, other.Appearances)
+	mergeCounts(s.DamageTypes, other.DamageTypes)
+	mergeCounts(s.FriendlyKills, other.FriendlyKills)
+	s.BestStreak = max(s.BestStreak, other.BestStreak)
+	for hour, count := range other.KillHours {
+		s.KillHours[hour] += count
+	}
+}
+
+func mergeCounts(dst, src map[string]int) {
+	for name, count := range src {
+		dst[name] += count
+	}
+}
+
+// CreateBackup collects the stats of every known player.
+func CreateBackup(includeSessions bool) Backup {
+	b := Backup{Version: backupVersion, Created: time.Now().UTC(), Players: make(map[string]Stats)}
+	for _, player := range ListPlayers() {
+		b.Players[player] = Load(player)
+	}
+	if includeSessions {
+		b.Sessions = LoadSessions()
+	}
+	return b
+}
+
+// WriteBackup encodes b as indented JSON.
+func WriteBackup(w io.Writer, b Backup) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// ReadBackup decodes a backup written by WriteBackup.
+func ReadBackup(r io.Reader) (Backup, error) {
+	var b Backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return Backup{}, fmt.Errorf("not a stats backup: %w", err)
+	}
+	if b.Version == 0 || b.Version > backupVersion {
+		return Backup{}, fmt.Errorf("unsupported stats backup version %d", b.Version)
+	}
+	return b, nil
+}
+
+// ImportBackup writes the backup's stats to disk. In merge mode counts are
+// summed per name and sessions not already recorded are appended; in replace
+// mode each player's file, and the session log if the backup has one, is
+// overwritten.
+func ImportBackup(b Backup, mode ImportMode) error {
+	for player, imported := range b.Players {
+		imported.ensureMaps()
+		if mode == ImportMerge {
+			local := Load(player)
+			local.Merge(imported)
+			imported = local
+		}
+		if err := Save(player, imported); err != nil {
+			return fmt.Errorf("failed to save stats for %s: %w", player, err)
+		}
+	}
+	if len(b.Sessions) == 0 {
+		return nil
+	}
+	sessions := b.Sessions
+	if mode == ImportMerge {
+		sessions = LoadSessions()
+		for _, s := range b.Sessions {
+			if !containsSession(sessions, s) {
+				sessions = append(sessions, s)
+			}
+		}
+		sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Start.Before(sessions[j].Start) })
+	}
+	return saveSessions(sessions)
+}
+
+// containsSession reports whether a session with the same player and start time is present.
+func containsSession(sessions []Session, s Session) bool {
+	for _, existing := range sessions {
+		if existing.Player == s.Player && existing.Start.Equal(s.Start) {
+			return true
+		}
+	}
+	return false
+}
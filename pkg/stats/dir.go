@@ -0,0 +1,196 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+var (
+	dirMu       sync.RWMutex
+	dirOverride string // user-chosen data directory, "" for DefaultDir
+	portableDir string // data directory next to the executable in portable mode
+)
+
+// PortableMarker is the file that, placed next to the executable, switches on
+// portable mode just like the --portable flag.
+const PortableMarker = "portable.txt"
+
+// PortableRequested reports whether portable mode was asked for, by a
+// --portable argument in args or a PortableMarker next to the executable.
+func PortableRequested(args []string) bool {
+	if slices.Contains(args, "--portable") || slices.Contains(args, "-portable") {
+		return true
+	}
+	dir, err := programDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, PortableMarker))
+	return err == nil
+}
+
+// programDir returns the folder of the running executable, with symlinks
+// resolved so a linked program finds the folder it really lives in.
+func programDir() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Dir(exe), nil
+}
+
+// EnablePortable makes DefaultDir the citizenmon\feeds folder next to the
+// executable, so the data travels with the program. It's the same folder older
+// versions used when run from their own folder without APPDATA set.
+func EnablePortable() error {
+	dir, err := programDir()
+	if err != nil {
+		return fmt.Errorf("failed to find the program folder for portable mode: %w", err)
+	}
+	dirMu.Lock()
+	portableDir = filepath.Join(dir, "citizenmon", "feeds")
+	dirMu.Unlock()
+	return nil
+}
+
+// Portable reports whether portable mode is on.
+func Portable() bool {
+	dirMu.RLock()
+	defer dirMu.RUnlock()
+	return portableDir != ""
+}
+
+// DefaultDir returns the data directory used unless SetDir overrides it: in
+// portable mode the folder next to the executable, otherwise the platform
+// data directory: %AppData%\citizenmon\feeds on Windows,
+// ~/.config/citizenmon/feeds on Linux and
+// ~/Library/Application Support/citizenmon/feeds on macOS.
+func DefaultDir() string {
+	dirMu.RLock()
+	portable := portableDir
+	dirMu.RUnlock()
+	if portable != "" {
+		return portable
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		if base, err = os.UserHomeDir(); err != nil {
+			base = "."
+		}
+	}
+	return filepath.Join(base, "citizenmon", "feeds")
+}
+
+// legacyDir is where older versions stored their files. Outside Windows
+// APPDATA is usually unset, which made that a path relative to the working
+// directory; it's made absolute here so it's clear which folder is meant.
+func legacyDir() string {
+	dir := filepath.Join(os.Getenv("APPDATA"), "citizenmon", "feeds")
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// SetDir overrides the data directory. An empty dir restores DefaultDir.
+func SetDir(dir string) {
+	dirMu.Lock()
+	dirOverride = dir
+	dirMu.Unlock()
+}
+
+// configuredDir returns the data directory without creating it.
+func configuredDir() string {
+	dirMu.RLock()
+	override := dirOverride
+	dirMu.RUnlock()
+	if override != "" {
+		return override
+	}
+	return DefaultDir()
+}
+
+// MigrateLegacyDir moves files left in the old APPDATA-based location into the
+// current data directory, keeping any file that already exists there. It
+// returns the number of files moved.
+func MigrateLegacyDir() (int, error) {
+	return MoveFiles(legacyDir(), Dir())
+}
+
+// MoveFiles moves the files directly inside from into to, skipping names that
+// already exist in to. Subdirectories are left alone. It returns the number of
+// files moved.
+func MoveFiles(from, to string) (int, error) {
+	absFrom, err := filepath.Abs(from)
+	if err != nil {
+		return 0, err
+	}
+	absTo, err := filepath.Abs(to)
+	if err != nil {
+		return 0, err
+	}
+	if absFrom == absTo {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(absFrom)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(absTo, 0755); err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		src := filepath.Join(absFrom, entry.Name())
+		dst := filepath.Join(absTo, entry.Name())
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := moveFile(src, dst); err != nil {
+			return moved, fmt.Errorf("failed to move %s: %w", entry.Name(), err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// moveFile renames src to dst, falling back to copy and delete when they are
+// on different drives.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LeaderboardEntry is one player's totals on the combined leaderboard.
+type LeaderboardEntry struct {
+	Player string
+	Kills  int
+	Deaths int
+}
+
+// KD returns kills per death, or the kill count when the player never died.
+func (e LeaderboardEntry) KD() float64 {
+	if e.Deaths == 0 {
+		return float64(e.Kills)
+	}
+	return float64(e.Kills) / float64(e.Deaths)
+}
+
+// LeaderboardSort selects how Leaderboard ranks players.
+type LeaderboardSort int
+
+const (
+	// RankByKills ranks by total kills, then K/D.
+	RankByKills LeaderboardSort = iota
+	// RankByKD ranks by K/D, then total kills.
+	RankByKD
+)
+
+// Leaderboard loads every player with a stats file and ranks their totals.
+// With playersOnly, kills of NPCs and pets and deaths to them, suicide or the
+// environment are left out so only fights between players count.
+func Leaderboard(by LeaderboardSort, playersOnly bool) []LeaderboardEntry {
+	var entries []LeaderboardEntry
+	for _, player := range ListPlayers() {
+		s := Load(player)
+		entry := LeaderboardEntry{Player: player}
+		for name, c := range s.Kills {
+			if !playersOnly || isPlayerOpponent(name) {
+				entry.Kills += c
+			}
+		}
+		for name, c := range s.Deaths {
+			if !playersOnly || isPlayerOpponent(name) {
+				entry.Deaths += c
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if by == RankByKD && a.KD() != b.KD() {
+			return a.KD() > b.KD()
+		}
+		if a.Kills != b.Kills {
+			return a.Kills > b.Kills
+		}
+		if a.KD() != b.KD() {
+			return a.KD() > b.KD()
+		}
+		return strings.ToLower(a.Player) < strings.ToLower(b.Player)
+	})
+	return entries
+}
+
+// WriteLeaderboardCSV writes ranked entries as CSV with a header row.
+func WriteLeaderboardCSV(w io.Writer, entries []LeaderboardEntry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Rank", "Player", "Kills", "Deaths", "K/D"})
+	for i, e := range entries {
+		cw.Write([]string{strconv.Itoa(i + 1), e.Player, strconv.Itoa(e.Kills), strconv.Itoa(e.Deaths), fmt.Sprintf("%.2f", e.KD())})
+	}
+	cw.Flush()
+	return cw.Error()
+}
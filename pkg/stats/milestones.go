@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultKillMilestones are the total-kill counts celebrated when no custom list is set.
+const DefaultKillMilestones = "100, 250, 500, 1000, 2500, 5000, 10000"
+
+// ParseMilestones parses a comma-separated list of positive counts, returned in ascending order.
+func ParseMilestones(list string) ([]int, error) {
+	var milestones []int
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid milestone %q: must be a positive whole number", field)
+		}
+		milestones = append(milestones, n)
+	}
+	sort.Ints(milestones)
+	return milestones, nil
+}
+
+// CrossedMilestone returns the highest milestone m with before < m <= after, if any.
+func CrossedMilestone(before, after int, milestones []int) (int, bool) {
+	for i := len(milestones) - 1; i >= 0; i-- {
+		if m := milestones[i]; before < m && m <= after {
+			return m, true
+		}
+	}
+	return 0, false
+}
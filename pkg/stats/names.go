@@ -0,0 +1,132 @@
+package stats
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NPCPrefixes lists name prefixes of NPC archetypes seen in game.log.
+// Append to it to recognise new archetypes without touching IsNPCName.
+var NPCPrefixes = []string{
+	"PU_Human",
+	"PU_Pilots",
+	"PU_Vanduul",
+	"AIModule_",
+	"NPC_Archetypes",
+	"Vanduul_",
+	"Kopion_",
+	"Marok_",
+	"Quasigrazer_",
+	"Valakkar_",
+	"Yormandi_",
+}
+
+// NPCMarkers lists words that mark a spawned entity as an NPC, like the NPC
+// in "Outpost_Guard_NPC_Sniper_9034512". A marker only counts as a whole word
+// between _ or - separators, in a name ending in a numeric entity id, so
+// handles such as "NPC_Hunter" or "Big_AI_Fan" aren't taken for NPCs.
+var NPCMarkers = []string{
+	"NPC",
+	"AI",
+	"Unmanned",
+	"StaticCrew",
+	"Human-Criminal",
+	"Human-Xenothreat",
+	"Human-Security",
+	"Turret",
+}
+
+// entityIDRegex matches the numeric entity id the game appends to spawned
+// entities' names.
+var entityIDRegex = regexp.MustCompile(`[_-]\d{6,}$`)
+
+// npcIDRegex matches the generic <Species>_<role>_<id> shape of spawned
+// entities, e.g. "Kopion_Adult_2030393421785". Real handles don't carry a
+// ten-plus digit entity id after two or more words.
+var npcIDRegex = regexp.MustCompile(`^[A-Za-z]+(?:[_-][A-Za-z0-9]+)+_\d{10,}$`)
+
+// IsNPCName reports whether a log name belongs to an NPC rather than a player.
+func IsNPCName(name string) bool {
+	for _, prefix := range NPCPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	if entityIDRegex.MatchString(name) {
+		for _, marker := range NPCMarkers {
+			if hasWord(name, marker) {
+				return true
+			}
+		}
+	}
+	return npcIDRegex.MatchString(name)
+}
+
+// hasWord reports whether word appears in name with a _ or - separator, or
+// the start or end of name, on both sides.
+func hasWord(name, word string) bool {
+	for i := 0; ; {
+		idx := strings.Index(name[i:], word)
+		if idx < 0 {
+			return false
+		}
+		start, end := i+idx, i+idx+len(word)
+		if (start == 0 || isNameSeparator(name[start-1])) && (end == len(name) || isNameSeparator(name[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isNameSeparator(c byte) bool {
+	return c == '_' || c == '-'
+}
+
+// IsPetName reports whether a log name belongs to a creature/pet.
+func IsPetName(name string) bool {
+	return strings.Contains(strings.ToLower(name), "_pet_") ||
+		strings.HasPrefix(name, "Pet_")
+}
+
+// Killer names recorded for deaths the game attributes to the player
+// themselves, see processor.SelfDeathKiller.
+const (
+	SuicideKiller     = "Suicide"
+	EnvironmentKiller = "Environment"
+)
+
+// isPlayerOpponent reports whether a name should count towards player rivalries.
+func isPlayerOpponent(name string) bool {
+	return name != "" && !strings.EqualFold(name, SuicideKiller) && !strings.EqualFold(name, EnvironmentKiller) && !IsNPCName(name) && !IsPetName(name)
+}
+
+// topOpponent returns the player with the highest count, breaking ties alphabetically.
+func topOpponent(counts map[string]int) (string, int) {
+	names := make([]string, 0, len(counts))
+	for name, count := range counts {
+		if count > 0 && isPlayerOpponent(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", 0
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names[0], counts[names[0]]
+}
+
+// Nemesis returns the player who killed you most, or "" if there is none.
+func (s Stats) Nemesis() (string, int) {
+	return topOpponent(s.Deaths)
+}
+
+// FavoriteVictim returns the player you killed most, or "" if there is none.
+func (s Stats) FavoriteVictim() (string, int) {
+	return topOpponent(s.Kills)
+}
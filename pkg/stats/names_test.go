@@ -0,0 +1,61 @@
+package stats
+
+import "testing"
+
+func TestIsNPCName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		// NPCs from game.log
+		{"PU_Human_Enemy_GroundCombat_NPC_Faction_Grunt_2030393421785", true},
+		{"PU_Pilots-Human-Criminal-Fighter_Light_123456", true},
+		{"PU_Vanduul_Pilot", true},
+		{"AIModule_Unmanned_PU_Advocacy_9034512", true},
+		{"NPC_Archetypes-Human-Criminal-Pirate", true},
+		{"Kopion_Adult_2030393421785", true},
+		{"Marok_Alpha", true},
+		{"Outpost_Guard_NPC_Sniper_9034512", true},
+		{"Bunker_Human-Security-Guard_5551234", true},
+		{"Station_Defense_Turret_7001234", true},
+		{"Hangar_StaticCrew_Mechanic_1234567", true},
+		{"Some_Creature_Role_20303934217", true},
+		// Player handles
+		{"Pilot_1", false},
+		{"NPC_Hunter", false},
+		{"Big_AI_Fan", false},
+		{"xNPCx", false},
+		{"Turret_Master_99", false},
+		{"KillerNPC_123", false},
+		{"Maroker", false},
+		{"Unmanned-Drone", false},
+		{"AI_Pilot_2024", false},
+		{"Suicide", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsNPCName(tt.name); got != tt.want {
+			t.Errorf("IsNPCName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsPlayerOpponent(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Pilot_1", true},
+		{SuicideKiller, false},
+		{EnvironmentKiller, false},
+		{"environment", false},
+		{"PU_Human_Enemy_GroundCombat_NPC_Faction_Grunt_2030393421785", false},
+		{"Pet_Kopion", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isPlayerOpponent(tt.name); got != tt.want {
+			t.Errorf("isPlayerOpponent(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package stats
+
+import "sort"
+
+// NameCount is a name with its count, e.g. a victim and how often they were killed.
+type NameCount struct {
+	Name  string
+	Count int
+}
+
+// TopCounts returns the n highest counts, highest first, with ties ordered
+// by name. Zero and negative counts are left out; n <= 0 returns them all.
+func TopCounts(counts map[string]int, n int) []NameCount {
+	entries := make([]NameCount, 0, len(counts))
+	for name, count := range counts {
+		if count > 0 {
+			entries = append(entries, NameCount{Name: name, Count: count})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// TopKills returns the n most killed victims in s; see TopCounts.
+func TopKills(s Stats, n int) []NameCount {
+	return TopCounts(s.Kills, n)
+}
+
+// TopDeaths returns the n players, NPCs or causes that killed the player
+// most in s; see TopCounts.
+func TopDeaths(s Stats, n int) []NameCount {
+	return TopCounts(s.Deaths, n)
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopCounts(t *testing.T) {
+	counts := map[string]int{"Bravo": 3, "Alpha": 3, "Charlie": 5, "Delta": 1, "Echo": 0, "Foxtrot": -2}
+	tests := []struct {
+		name string
+		n    int
+		want []NameCount
+	}{
+		{"top one", 1, []NameCount{{"Charlie", 5}}},
+		{"ties ordered by name", 3, []NameCount{{"Charlie", 5}, {"Alpha", 3}, {"Bravo", 3}}},
+		{"n larger than the map", 10, []NameCount{{"Charlie", 5}, {"Alpha", 3}, {"Bravo", 3}, {"Delta", 1}}},
+		{"n zero returns all", 0, []NameCount{{"Charlie", 5}, {"Alpha", 3}, {"Bravo", 3}, {"Delta", 1}}},
+		{"n negative returns all", -1, []NameCount{{"Charlie", 5}, {"Alpha", 3}, {"Bravo", 3}, {"Delta", 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TopCounts(counts, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopCounts(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopCountsEmpty(t *testing.T) {
+	for _, counts := range []map[string]int{nil, {}, {"Zero": 0}} {
+		if got := TopCounts(counts, 5); len(got) != 0 {
+			t.Errorf("TopCounts(%v) = %v, want none", counts, got)
+		}
+	}
+}
+
+func TestTopKillsAndDeaths(t *testing.T) {
+	s := New()
+	s.Kills["Victim_1"] = 2
+	s.Kills["Victim_2"] = 4
+	s.Deaths[EnvironmentKiller] = 1
+	if got, want := TopKills(s, 1), []NameCount{{"Victim_2", 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TopKills = %v, want %v", got, want)
+	}
+	if got, want := TopDeaths(s, 5), []NameCount{{EnvironmentKiller, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TopDeaths = %v, want %v", got, want)
+	}
+}
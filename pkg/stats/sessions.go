@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Session records a single monitoring session.
+type Session struct {
+	Player  string    `json:"player"`
+	LogFile string    `json:"logFile"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Kills   int       `json:"kills"`
+	Deaths  int       `json:"deaths"`
+}
+
+// Duration returns how long the session lasted.
+func (s Session) Duration() time.Duration {
+	if s.End.Before(s.Start) {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// Playtime returns the total duration and kills of a player's sessions.
+func Playtime(sessions []Session, player string) (time.Duration, int) {
+	var total time.Duration
+	kills := 0
+	for _, s := range sessions {
+		if s.Player != player {
+			continue
+		}
+		total += s.Duration()
+		kills += s.Kills
+	}
+	return total, kills
+}
+
+// KillsPerHour returns the kill rate over d, or 0 when d is zero.
+func KillsPerHour(kills int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(kills) / d.Hours()
+}
+
+// sessionsFile returns the path of sessions.json in the stats dir.
+func sessionsFile() string {
+	return filepath.Join(getStatsDir(), "sessions.json")
+}
+
+// LoadSessions reads all recorded sessions, oldest first, or returns nil on error.
+func LoadSessions() []Session {
+	data, err := os.ReadFile(sessionsFile())
+	if err != nil {
+		return nil
+	}
+	var sessions []Session
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil
+	}
+	return sessions
+}
+
+// AppendSession adds a finished session to sessions.json.
+func AppendSession(s Session) error {
+	return saveSessions(append(LoadSessions(), s))
+}
+
+// saveSessions overwrites sessions.json with the given sessions.
+func saveSessions(sessions []Session) error {
+	f, err := os.Create(sessionsFile())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sessions)
+}
@@ -1,100 +1,210 @@
-package stats
-
-import (
-	"encoding/json"
-	"os"
-	"path/filepath"
-)
-
-// Stats holds tracked player interactions.
-type Stats struct {
-	Kills       map[string]int `json:"kills"`
-	Deaths      map[string]int `json:"deaths"`
-	Incaps      map[string]int `json:"incaps"`
-	Appearances map[string]int `json:"appearances"`
-}
-
-// Global current session stats (resets when app restarts)
-var currentSessionStats = make(map[string]Stats)
-
-// New initializes an empty Stats.
-func New() Stats {
-	return Stats{
-		Kills:       make(map[string]int),
-		Deaths:      make(map[string]int),
-		Incaps:      make(map[string]int),
-		Appearances: make(map[string]int),
-	}
-}
-
-// ResetCurrentSession clears the current session stats for all players
-func ResetCurrentSession() {
-	currentSessionStats = make(map[string]Stats)
-}
-
-// GetCurrentSession returns the current session stats for a player
-func GetCurrentSession(player string) Stats {
-	if player == "" {
-		return New()
-	}
-	if stats, exists := currentSessionStats[player]; exists {
-		return stats
-	}
-	return New()
-}
-
-// UpdateCurrentSession updates the current session stats for a player
-func UpdateCurrentSession(player string, allTimeStats Stats) {
-	if player == "" {
-		return
-	}
-	currentSessionStats[player] = allTimeStats
-}
-
-// getStatsDir returns the directory for saving stats files (same as feeds)
-func getStatsDir() string {
-	dir := filepath.Join(os.Getenv("APPDATA"), "citizenmon", "feeds")
-	os.MkdirAll(dir, 0755)
-	return dir
-}
-
-// Load reads stats from <player>_stats.json in the stats dir, or returns empty on error.
-func Load(player string) Stats {
-	if player == "" {
-		return New()
-	}
-	fname := filepath.Join(getStatsDir(), player+"_stats.json")
-	f, err := os.Open(fname)
-	if err != nil {
-		return New()
-	}
-	defer f.Close()
-	var s Stats
-	if err := json.NewDecoder(f).Decode(&s); err != nil {
-		return New()
-	}
-	return s
-}
-
-// Save writes stats to <player>_stats.json in the stats dir.
-func Save(player string, s Stats) error {
-	if player == "" {
-		return nil
-	}
-	fname := filepath.Join(getStatsDir(), player+"_stats.json")
-	f, err := os.Create(fname)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	return json.NewEncoder(f).Encode(s)
-}
-
-// ResetAllTime resets all-time stats for a player (saves empty stats to file)
-func ResetAllTime(player string) error {
-	if player == "" {
-		return nil
-	}
-	emptyStats := New()
-	return Save(player, emptyStats)
-}
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"game-monitor/pkg/fsutil"
+)
+
+// Stats holds tracked player interactions.
+type Stats struct {
+	Kills       map[string]int `json:"kills"`
+	Deaths      map[string]int `json:"deaths"`
+	Incaps      map[string]int `json:"incaps"`
+	Appearances map[string]int `json:"appearances"`
+	DamageTypes map[string]int `json:"damageTypes"`
+	// FriendlyKills counts kills of players on the friends list, which are left out of Kills
+	FriendlyKills map[string]int `json:"friendlyKills"`
+	BestStreak    int            `json:"bestStreak,omitempty"` // longest run of kills without dying
+	// KillHours counts kills by the UTC hour of day they happened in; see KillsByLocalHour
+	KillHours [24]int `json:"killHoursUTC"`
+}
+
+// Global current session stats (resets when app restarts)
+var currentSessionStats = make(map[string]Stats)
+
+// New initializes an empty Stats.
+func New() Stats {
+	return Stats{
+		Kills:         make(map[string]int),
+		Deaths:        make(map[string]int),
+		Incaps:        make(map[string]int),
+		Appearances:   make(map[string]int),
+		DamageTypes:   make(map[string]int),
+		FriendlyKills: make(map[string]int),
+	}
+}
+
+// ensureMaps fills in any maps missing from older stats files so callers can write to them safely.
+func (s *Stats) ensureMaps() {
+	if s.Kills == nil {
+		s.Kills = make(map[string]int)
+	}
+	if s.Deaths == nil {
+		s.Deaths = make(map[string]int)
+	}
+	if s.Incaps == nil {
+		s.Incaps = make(map[string]int)
+	}
+	if s.Appearances == nil {
+		s.Appearances = make(map[string]int)
+	}
+	if s.DamageTypes == nil {
+		s.DamageTypes = make(map[string]int)
+	}
+	if s.FriendlyKills == nil {
+		s.FriendlyKills = make(map[string]int)
+	}
+}
+
+// TotalKills returns the number of kills across all victims.
+func (s Stats) TotalKills() int {
+	total := 0
+	for _, c := range s.Kills {
+		total += c
+	}
+	return total
+}
+
+// TotalDeaths returns the number of deaths across all killers.
+func (s Stats) TotalDeaths() int {
+	total := 0
+	for _, c := range s.Deaths {
+		total += c
+	}
+	return total
+}
+
+// ResetCurrentSession clears the current session stats for all players
+func ResetCurrentSession() {
+	currentSessionStats = make(map[string]Stats)
+}
+
+// ResetPlayerSession clears the current session stats for one player
+func ResetPlayerSession(player string) {
+	delete(currentSessionStats, player)
+}
+
+// GetCurrentSession returns the current session stats for a player
+func GetCurrentSession(player string) Stats {
+	if player == "" {
+		return New()
+	}
+	if stats, exists := currentSessionStats[player]; exists {
+		return stats
+	}
+	return New()
+}
+
+// UpdateCurrentSession updates the current session stats for a player
+func UpdateCurrentSession(player string, allTimeStats Stats) {
+	if player == "" {
+		return
+	}
+	currentSessionStats[player] = allTimeStats
+}
+
+// Dir returns the directory where stats files are stored.
+func Dir() string {
+	return getStatsDir()
+}
+
+// getStatsDir returns the directory for saving stats files (same as feeds)
+func getStatsDir() string {
+	dir := configuredDir()
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// statsFile returns the path of a player's stats file, with the name made safe for the file system.
+func statsFile(player string) string {
+	return filepath.Join(getStatsDir(), fsutil.SanitizeFilename(player)+"_stats.json")
+}
+
+// ListPlayers returns the players the stats backend has stats for, sorted by name.
+func ListPlayers() []string {
+	players, err := currentBackend().Players()
+	if err != nil {
+		return nil
+	}
+	return players
+}
+
+// Load reads a player's stats from the stats backend, or returns empty on error.
+func Load(player string) Stats {
+	if player == "" {
+		return New()
+	}
+	s, err := currentBackend().Load(player)
+	if err != nil {
+		return New()
+	}
+	s.ensureMaps()
+	return s
+}
+
+// Save writes a player's stats to the stats backend.
+func Save(player string, s Stats) error {
+	if player == "" {
+		return nil
+	}
+	return currentBackend().Save(player, s)
+}
+
+// CommitSession adds player's current session counts onto their saved
+// all-time stats, saves them and clears the session, so committing again
+// straight away adds nothing. It returns the new all-time stats.
+func CommitSession(player string) (Stats, error) {
+	allTime := Load(player)
+	if player == "" {
+		return allTime, nil
+	}
+	allTime.Merge(GetCurrentSession(player))
+	if err := Save(player, allTime); err != nil {
+		return Load(player), err
+	}
+	ResetPlayerSession(player)
+	return allTime, nil
+}
+
+// ResetAllTime resets all-time stats for a player to empty ones. The backend
+// keeps the previous stats, replacing any older copy, so the reset can be
+// taken back with UndoReset.
+func ResetAllTime(player string) error {
+	if player == "" {
+		return nil
+	}
+	return currentBackend().Reset(player)
+}
+
+// UndoReset puts back the stats the last ResetAllTime for player replaced,
+// discarding anything counted since, and returns them.
+func UndoReset(player string) (Stats, error) {
+	if player == "" {
+		return New(), nil
+	}
+	if err := currentBackend().Restore(player); err != nil {
+		return Stats{}, err
+	}
+	return Load(player), nil
+}
+
+// AddKillHour counts a kill in the hour of day it happened, in UTC.
+func (s *Stats) AddKillHour(t time.Time) {
+	s.KillHours[t.UTC().Hour()]++
+}
+
+// KillsByLocalHour returns the kills per hour of day in loc, shifted from
+// the stored UTC hours by loc's current offset. Zones offset by a fraction of
+// an hour are shifted by the whole hours only.
+func (s Stats) KillsByLocalHour(loc *time.Location) [24]int {
+	_, offset := time.Now().In(loc).Zone()
+	shift := offset / 3600
+	var hours [24]int
+	for utcHour, count := range s.KillHours {
+		hours[((utcHour+shift)%24+24)%24] += count
+	}
+	return hours
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Event log record types.
+const (
+	LogKill     = "kill"
+	LogTeamKill = "team_kill"
+	LogDeath    = "death"
+	LogIncap    = "incap"
+)
+
+// LogRecord is one line of the event log.
+type LogRecord struct {
+	Type       string    `json:"type"`   // LogKill, LogTeamKill, LogDeath or LogIncap
+	Actor      string    `json:"actor"`  // killer, or the player for an incap
+	Target     string    `json:"target"` // victim, or the player for a death
+	Weapon     string    `json:"weapon"` // weapon class as logged, "" if unknown
+	DamageType string    `json:"damage_type"`
+	Timestamp  time.Time `json:"timestamp"` // log time, UTC
+}
+
+// EventLog appends LogRecords as JSON Lines to events-YYYY-MM-DD.jsonl in a
+// directory, starting a new file each local day, for tools that read the
+// events as they happen.
+type EventLog struct {
+	mu   sync.Mutex
+	dir  string
+	day  string
+	file *os.File
+}
+
+// NewEventLog creates an event log writing to dir. Files are opened on the
+// first record.
+func NewEventLog(dir string) *EventLog {
+	return &EventLog{dir: dir}
+}
+
+// EventLogName returns the event log file name for the day of t.
+func EventLogName(t time.Time) string {
+	return "events-" + t.Format("2006-01-02") + ".jsonl"
+}
+
+// Record appends r to today's file.
+func (l *EventLog) Record(r LogRecord) error {
+	r.Timestamp = r.Timestamp.UTC()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if day := time.Now().Format("2006-01-02"); l.file == nil || day != l.day {
+		if l.file != nil {
+			l.file.Close()
+			l.file = nil
+		}
+		f, err := os.OpenFile(filepath.Join(l.dir, EventLogName(time.Now())), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		l.file, l.day = f, day
+	}
+	_, err = l.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the current file.
+func (l *EventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
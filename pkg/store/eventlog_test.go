@@ -0,0 +1,69 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventLogRecord(t *testing.T) {
+	dir := t.TempDir()
+	l := NewEventLog(dir)
+	local := time.FixedZone("UTC+2", 2*3600)
+	records := []LogRecord{
+		{Type: LogKill, Actor: "Pilot", Target: "Victim_1", Weapon: "rifle", DamageType: "Bullet", Timestamp: time.Date(2025, 1, 2, 12, 0, 0, 0, local)},
+		{Type: LogDeath, Actor: "Killer_1", Target: "Pilot", Timestamp: time.Date(2025, 1, 2, 12, 5, 0, 0, time.UTC)},
+	}
+	for _, r := range records {
+		if err := l.Record(r); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, EventLogName(time.Now())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []LogRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r LogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q isn't JSON: %v", scanner.Text(), err)
+		}
+		got = append(got, r)
+	}
+	// Timestamps are written in UTC
+	want := []LogRecord{records[0], records[1]}
+	want[0].Timestamp = want[0].Timestamp.UTC()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("event log holds %+v, want %+v", got, want)
+	}
+}
+
+func TestEventLogAppendsAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		l := NewEventLog(dir)
+		if err := l.Record(LogRecord{Type: LogIncap, Actor: "Pilot", Target: "Victim_1"}); err != nil {
+			t.Fatal(err)
+		}
+		l.Close()
+	}
+	data, err := os.ReadFile(filepath.Join(dir, EventLogName(time.Now())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\n")); n != 2 {
+		t.Errorf("event log has %d lines, want 2", n)
+	}
+}
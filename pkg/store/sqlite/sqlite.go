@@ -0,0 +1,189 @@
+// Package sqlite implements store.EventStore and stats.Backend on top of an
+// SQLite database using the pure-Go modernc.org/sqlite driver, so no cgo is
+// required.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"game-monitor/pkg/stats"
+	"game-monitor/pkg/store"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS events (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	player      TEXT    NOT NULL,
+	target      TEXT    NOT NULL,
+	weapon      TEXT    NOT NULL DEFAULT '',
+	damage_type TEXT    NOT NULL DEFAULT '',
+	timestamp   INTEGER NOT NULL,
+	is_kill     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_events_player_time ON events (player, timestamp);
+CREATE TABLE IF NOT EXISTS player_stats (
+	player TEXT PRIMARY KEY,
+	data   TEXT NOT NULL,
+	backup TEXT
+);
+`
+
+// Store is an SQLite-backed event store and stats backend.
+type Store struct {
+	db *sql.DB
+}
+
+var (
+	_ store.EventStore = (*Store)(nil)
+	_ stats.Backend    = (*Store)(nil)
+)
+
+// Open opens (or creates) the database at path and ensures the schema exists.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Record inserts a single event. Timestamps are stored as UTC unix milliseconds.
+func (s *Store) Record(e store.Event) error {
+	_, err := s.db.Exec(
+		`INSERT INTO events (player, target, weapon, damage_type, timestamp, is_kill) VALUES (?, ?, ?, ?, ?, ?)`,
+		e.Player, e.Target, e.Weapon, e.DamageType, e.Timestamp.UTC().UnixMilli(), e.IsKill,
+	)
+	return err
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// KillsBetween counts the player's kills with from <= timestamp < to.
+func (s *Store) KillsBetween(player string, from, to time.Time) (int, error) {
+	var count int
+	err := s.db.QueryRow(
+		`SELECT COUNT(*) FROM events WHERE player = ? AND is_kill = 1 AND timestamp >= ? AND timestamp < ?`,
+		player, from.UTC().UnixMilli(), to.UTC().UnixMilli(),
+	).Scan(&count)
+	return count, err
+}
+
+// TopVictimsSince returns up to n victims the player killed most since the given time.
+func (s *Store) TopVictimsSince(player string, since time.Time, n int) ([]store.TargetCount, error) {
+	rows, err := s.db.Query(
+		`SELECT target, COUNT(*) AS c FROM events
+		 WHERE player = ? AND is_kill = 1 AND timestamp >= ?
+		 GROUP BY target ORDER BY c DESC, target ASC LIMIT ?`,
+		player, since.UTC().UnixMilli(), n,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []store.TargetCount
+	for rows.Next() {
+		var tc store.TargetCount
+		if err := rows.Scan(&tc.Name, &tc.Count); err != nil {
+			return nil, err
+		}
+		result = append(result, tc)
+	}
+	return result, rows.Err()
+}
+
+// Load returns a player's all-time stats, kept as JSON in player_stats.
+func (s *Store) Load(player string) (stats.Stats, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM player_stats WHERE player = ?`, player).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return stats.Stats{}, fmt.Errorf("no stats for %s: %w", player, os.ErrNotExist)
+	}
+	if err != nil {
+		return stats.Stats{}, err
+	}
+	var st stats.Stats
+	if err := json.Unmarshal([]byte(data), &st); err != nil {
+		return stats.Stats{}, err
+	}
+	return st, nil
+}
+
+// Save replaces a player's all-time stats.
+func (s *Store) Save(player string, st stats.Stats) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO player_stats (player, data) VALUES (?, ?)
+		 ON CONFLICT (player) DO UPDATE SET data = excluded.data`,
+		player, string(data),
+	)
+	return err
+}
+
+// Players returns the players with saved stats, sorted by name.
+func (s *Store) Players() ([]string, error) {
+	rows, err := s.db.Query(`SELECT player FROM player_stats ORDER BY player`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var players []string
+	for rows.Next() {
+		var player string
+		if err := rows.Scan(&player); err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+	return players, rows.Err()
+}
+
+// Reset empties a player's stats, keeping the previous ones in the backup
+// column. A player without stats is left without a backup too.
+func (s *Store) Reset(player string) error {
+	data, err := json.Marshal(stats.New())
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO player_stats (player, data, backup) VALUES (?, ?, NULL)
+		 ON CONFLICT (player) DO UPDATE SET backup = data, data = excluded.data`,
+		player, string(data),
+	)
+	return err
+}
+
+// Restore puts back the stats the last Reset replaced.
+func (s *Store) Restore(player string) error {
+	res, err := s.db.Exec(
+		`UPDATE player_stats SET data = backup, backup = NULL WHERE player = ? AND backup IS NOT NULL`,
+		player,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("no reset to undo for %s: %w", player, os.ErrNotExist)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package sqlite
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"game-monitor/pkg/stats"
+	"game-monitor/pkg/store"
+)
+
+// openTestStore opens a store on a fresh database in a temp dir.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+var day = time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+// at returns a time the given number of hours into day.
+func at(hours int) time.Time {
+	return day.Add(time.Duration(hours) * time.Hour)
+}
+
+func recordAll(t *testing.T, s *Store, events []store.Event) {
+	t.Helper()
+	for _, e := range events {
+		if err := s.Record(e); err != nil {
+			t.Fatalf("Record(%+v) error = %v", e, err)
+		}
+	}
+}
+
+func TestKillsBetween(t *testing.T) {
+	s := openTestStore(t)
+	recordAll(t, s, []store.Event{
+		{Player: "Pilot", Target: "Victim_1", Weapon: "rifle", Timestamp: at(1), IsKill: true},
+		{Player: "Pilot", Target: "Victim_2", Timestamp: at(5), IsKill: true},
+		{Player: "Pilot", Target: "Killer_1", Timestamp: at(6)}, // a death
+		{Player: "Pilot", Target: "Victim_1", Timestamp: at(30), IsKill: true},
+		{Player: "Other", Target: "Victim_1", Timestamp: at(2), IsKill: true},
+	})
+	tests := []struct {
+		name     string
+		player   string
+		from, to time.Time
+		want     int
+	}{
+		{"whole day", "Pilot", at(0), at(24), 2},
+		{"from is inclusive", "Pilot", at(5), at(24), 1},
+		{"to is exclusive", "Pilot", at(0), at(5), 1},
+		{"two days", "Pilot", at(0), at(48), 3},
+		{"other player", "Other", at(0), at(24), 1},
+		{"no kills", "Nobody", at(0), at(48), 0},
+		{"other time zone", "Pilot", at(0).In(time.FixedZone("UTC+10", 10*3600)), at(24), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.KillsBetween(tt.player, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("KillsBetween() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("KillsBetween() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopVictimsSince(t *testing.T) {
+	s := openTestStore(t)
+	recordAll(t, s, []store.Event{
+		{Player: "Pilot", Target: "Bravo", Timestamp: at(1), IsKill: true},
+		{Player: "Pilot", Target: "Alpha", Timestamp: at(2), IsKill: true},
+		{Player: "Pilot", Target: "Charlie", Timestamp: at(3), IsKill: true},
+		{Player: "Pilot", Target: "Charlie", Timestamp: at(4), IsKill: true},
+		{Player: "Pilot", Target: "Bravo", Timestamp: at(10), IsKill: true},
+		{Player: "Pilot", Target: "Charlie", Timestamp: at(11)}, // a death, not a kill
+		{Player: "Other", Target: "Alpha", Timestamp: at(12), IsKill: true},
+	})
+	tests := []struct {
+		name  string
+		since time.Time
+		n     int
+		want  []store.TargetCount
+	}{
+		{"all", at(0), 10, []store.TargetCount{{Name: "Bravo", Count: 2}, {Name: "Charlie", Count: 2}, {Name: "Alpha", Count: 1}}},
+		{"limited", at(0), 2, []store.TargetCount{{Name: "Bravo", Count: 2}, {Name: "Charlie", Count: 2}}},
+		{"since is inclusive", at(4), 10, []store.TargetCount{{Name: "Bravo", Count: 1}, {Name: "Charlie", Count: 1}}},
+		{"nothing since", at(20), 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.TopVictimsSince("Pilot", tt.since, tt.n)
+			if err != nil {
+				t.Fatalf("TopVictimsSince() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopVictimsSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReopenKeepsEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordAll(t, s, []store.Event{{Player: "Pilot", Target: "Victim_1", Timestamp: at(1), IsKill: true}})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got, err := s.KillsBetween("Pilot", at(0), at(24)); err != nil || got != 1 {
+		t.Errorf("KillsBetween() after reopening = %d, %v, want 1", got, err)
+	}
+}
+
+func TestStatsBackend(t *testing.T) {
+	s := openTestStore(t)
+	if _, err := s.Load("Pilot"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() of a new player error = %v, want os.ErrNotExist", err)
+	}
+
+	saved := stats.New()
+	saved.Kills["Victim_1"] = 3
+	saved.Deaths["Killer_1"] = 1
+	saved.BestStreak = 3
+	saved.KillHours[14] = 3
+	for _, player := range []string{"Pilot", "Alpha"} {
+		if err := s.Save(player, saved); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	got, err := s.Load("Pilot")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, saved) {
+		t.Errorf("Load() = %+v, want %+v", got, saved)
+	}
+	if players, err := s.Players(); err != nil || !reflect.DeepEqual(players, []string{"Alpha", "Pilot"}) {
+		t.Errorf("Players() = %v, %v, want [Alpha Pilot]", players, err)
+	}
+
+	if err := s.Reset("Pilot"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if got, _ := s.Load("Pilot"); got.TotalKills() != 0 {
+		t.Errorf("kills after Reset() = %d, want 0", got.TotalKills())
+	}
+	if err := s.Restore("Pilot"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got, _ := s.Load("Pilot"); !reflect.DeepEqual(got, saved) {
+		t.Errorf("Load() after Restore() = %+v, want %+v", got, saved)
+	}
+	if err := s.Restore("Pilot"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("second Restore() error = %v, want os.ErrNotExist", err)
+	}
+	if err := s.Reset("Nobody"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Restore("Nobody"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Restore() of a new player error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,27 @@
+// Package store records individual kill and death events so they can be
+// queried later, alongside the aggregate JSON stats files.
+package store
+
+import "time"
+
+// Event is a single kill or death involving the tracked player.
+type Event struct {
+	Player     string // the tracked player
+	Target     string // victim for kills, killer for deaths
+	Weapon     string
+	DamageType string
+	Timestamp  time.Time
+	IsKill     bool
+}
+
+// TargetCount pairs an opponent name with how often they appear.
+type TargetCount struct {
+	Name  string
+	Count int
+}
+
+// EventStore persists events as they happen.
+type EventStore interface {
+	Record(e Event) error
+	Close() error
+}
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxAutocompleteRows bounds the height of the suggestion popup.
+const maxAutocompleteRows = 8
+
+// autocompleteEntry is an Entry that shows a popup list of the options
+// containing the typed text. Arrow keys move through the list, Enter or a
+// click picks an option, and Escape closes it.
+type autocompleteEntry struct {
+	widget.Entry
+	OnTextChanged func(text string) // called after every change, typed or picked
+
+	canvas  fyne.Canvas
+	options []string
+	matches []string
+	list    *autocompleteList
+	popup   *widget.PopUp
+}
+
+// newAutocompleteEntry creates an entry whose popup is shown on canvas.
+func newAutocompleteEntry(canvas fyne.Canvas) *autocompleteEntry {
+	e := &autocompleteEntry{canvas: canvas}
+	e.ExtendBaseWidget(e)
+	e.list = newAutocompleteList(e)
+	e.popup = widget.NewPopUp(e.list, canvas)
+	e.popup.Hide()
+	e.OnChanged = func(text string) {
+		e.refreshMatches()
+		if e.OnTextChanged != nil {
+			e.OnTextChanged(text)
+		}
+	}
+	return e
+}
+
+// SetOptions replaces the list of suggestions.
+func (e *autocompleteEntry) SetOptions(options []string) {
+	e.options = options
+	e.hidePopup()
+}
+
+// refreshMatches filters the options by the current text and shows or hides
+// the popup. Nothing is shown for an empty text or an exact match.
+func (e *autocompleteEntry) refreshMatches() {
+	query := strings.ToLower(e.Text)
+	e.matches = e.matches[:0]
+	for _, option := range e.options {
+		if option == e.Text {
+			e.hidePopup()
+			return
+		}
+		if strings.Contains(strings.ToLower(option), query) {
+			e.matches = append(e.matches, option)
+		}
+	}
+	if query == "" || len(e.matches) == 0 {
+		e.hidePopup()
+		return
+	}
+	e.list.UnselectAll()
+	e.list.selected = -1
+	e.list.Refresh()
+	e.list.ScrollToTop()
+	e.showPopup()
+}
+
+// showPopup opens the suggestion list just below the entry and gives it the
+// keyboard; it forwards typing back to the entry.
+func (e *autocompleteEntry) showPopup() {
+	if e.canvas == nil || e.Size().Width == 0 {
+		return
+	}
+	rowHeight := widget.NewLabel("").MinSize().Height + theme.SeparatorThicknessSize()
+	rows := min(len(e.matches), maxAutocompleteRows)
+	e.popup.Resize(fyne.NewSize(e.Size().Width, float32(rows)*rowHeight))
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(e)
+	e.popup.ShowAtPosition(pos.Add(fyne.NewPos(0, e.Size().Height)))
+	e.canvas.Focus(e.list)
+}
+
+// hidePopup closes the suggestion list.
+func (e *autocompleteEntry) hidePopup() {
+	if e.popup.Visible() {
+		e.popup.Hide()
+	}
+}
+
+// choose fills in an option, which closes the popup and reports it through OnTextChanged.
+func (e *autocompleteEntry) choose(option string) {
+	e.hidePopup()
+	e.SetText(option)
+	e.CursorColumn = len([]rune(option))
+	if e.canvas != nil {
+		e.canvas.Focus(e)
+	}
+}
+
+// autocompleteList is the popup's list. It holds the keyboard focus while the
+// popup is open, handling navigation keys and passing everything else to the entry.
+type autocompleteList struct {
+	widget.List
+	entry    *autocompleteEntry
+	selected int // highlighted row, -1 for none
+}
+
+func newAutocompleteList(e *autocompleteEntry) *autocompleteList {
+	l := &autocompleteList{entry: e, selected: -1}
+	l.Length = func() int { return len(e.matches) }
+	l.CreateItem = func() fyne.CanvasObject { return widget.NewLabel("") }
+	l.UpdateItem = func(id widget.ListItemID, o fyne.CanvasObject) {
+		if id < len(e.matches) {
+			o.(*widget.Label).SetText(e.matches[id])
+		}
+	}
+	l.OnSelected = func(id widget.ListItemID) {
+		// Arrow keys only highlight; a click picks the row
+		if id != l.selected && id < len(e.matches) {
+			e.choose(e.matches[id])
+		}
+	}
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+// TypedKey moves through the list, picks the highlighted row or closes the popup.
+func (l *autocompleteList) TypedKey(event *fyne.KeyEvent) {
+	switch event.Name {
+	case fyne.KeyDown:
+		l.highlight(min(l.selected+1, len(l.entry.matches)-1))
+	case fyne.KeyUp:
+		l.highlight(max(l.selected-1, 0))
+	case fyne.KeyReturn, fyne.KeyEnter:
+		if l.selected >= 0 && l.selected < len(l.entry.matches) {
+			l.entry.choose(l.entry.matches[l.selected])
+		}
+	case fyne.KeyEscape:
+		l.entry.hidePopup()
+		l.entry.canvas.Focus(l.entry)
+	default:
+		l.entry.TypedKey(event)
+	}
+}
+
+// TypedRune keeps typing going into the entry while the popup has focus.
+func (l *autocompleteList) TypedRune(r rune) {
+	l.entry.TypedRune(r)
+}
+
+// highlight selects a row without picking it.
+func (l *autocompleteList) highlight(id int) {
+	if id < 0 {
+		return
+	}
+	l.selected = id
+	l.Select(id)
+	l.ScrollTo(id)
+}
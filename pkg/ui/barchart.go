@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"game-monitor/pkg/processor"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// barChartWidth is the length of the longest bar in a barChart.
+const barChartWidth = 220
+
+// barChart is a horizontal bar chart of counts drawn with plain canvas
+// rectangles, largest first, each bar labelled with its count and share.
+type barChart struct {
+	rows  *fyne.Container
+	empty string // shown when there is nothing to chart
+}
+
+func newBarChart(empty string) *barChart {
+	c := &barChart{rows: container.NewVBox(), empty: empty}
+	c.set(nil)
+	return c
+}
+
+// set redraws the chart for counts; zero counts are left out.
+func (c *barChart) set(counts map[string]int) {
+	type bar struct {
+		name  string
+		count int
+	}
+	var bars []bar
+	total, most := 0, 0
+	for name, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		bars = append(bars, bar{name, count})
+		total += count
+		most = max(most, count)
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].count != bars[j].count {
+			return bars[i].count > bars[j].count
+		}
+		return bars[i].name < bars[j].name
+	})
+
+	c.rows.RemoveAll()
+	if total == 0 {
+		c.rows.Add(widget.NewLabel(c.empty))
+		return
+	}
+	for _, b := range bars {
+		rect := canvas.NewRectangle(theme.Color(theme.ColorNameError))
+		rect.SetMinSize(fyne.NewSize(max(barChartWidth*float32(b.count)/float32(most), 2), theme.TextSize()))
+		c.rows.Add(container.NewGridWithColumns(2,
+			widget.NewLabel(b.name),
+			container.NewHBox(container.NewCenter(rect), widget.NewLabel(fmt.Sprintf("%d (%d%%)", b.count, b.count*100/total))),
+		))
+	}
+}
+
+// Death cause groups charted on the Statistics tab.
+const (
+	deathCauseBallistic = "Ballistic"
+	deathCauseEnergy    = "Energy"
+	deathCauseExplosive = "Explosive"
+	deathCauseCollision = "Collision"
+	deathCauseSuicide   = "Suicide"
+	deathCauseEnviron   = "Environment"
+	deathCauseOther     = "Other"
+)
+
+// deathCauseGroup sorts a recorded damage type into one of the broad groups
+// charted on the Statistics tab.
+func deathCauseGroup(damageType string) string {
+	lower := strings.ToLower(damageType)
+	switch {
+	case damageType == processor.SuicideKiller:
+		return deathCauseSuicide
+	// Crashes count as environmental deaths too, but get their own group
+	case strings.Contains(lower, "collision") || strings.Contains(lower, "crash"):
+		return deathCauseCollision
+	case processor.SelfDeathKiller(damageType) == processor.EnvironmentKiller:
+		return deathCauseEnviron
+	case strings.Contains(lower, "bullet") || strings.Contains(lower, "ballistic"):
+		return deathCauseBallistic
+	case strings.Contains(lower, "energy") || strings.Contains(lower, "laser") ||
+		strings.Contains(lower, "electric") || strings.Contains(lower, "distortion"):
+		return deathCauseEnergy
+	case strings.Contains(lower, "explosion") || strings.Contains(lower, "explosive") ||
+		strings.Contains(lower, "missile") || strings.Contains(lower, "vehicledestruction"):
+		return deathCauseExplosive
+	default:
+		return deathCauseOther
+	}
+}
+
+// groupDeathCauses totals damage-type counts by deathCauseGroup.
+func groupDeathCauses(damageTypes map[string]int) map[string]int {
+	groups := make(map[string]int)
+	for damageType, count := range damageTypes {
+		groups[deathCauseGroup(damageType)] += count
+	}
+	return groups
+}
+
+// hourChartHeight is the height of the tallest column in an hourChart.
+const hourChartHeight = 80
+
+// hourChart is a 24-column histogram of counts by hour of day, drawn with
+// canvas rectangles, with a line naming the busiest hour.
+type hourChart struct {
+	columns *fyne.Container
+	peak    *widget.Label
+}
+
+func newHourChart() *hourChart {
+	c := &hourChart{columns: container.NewHBox(), peak: widget.NewLabel("")}
+	c.set([24]int{})
+	return c
+}
+
+// object returns the chart for placing in a layout.
+func (c *hourChart) object() fyne.CanvasObject {
+	return container.NewVBox(c.columns, c.peak)
+}
+
+// set redraws the chart for counts indexed by hour.
+func (c *hourChart) set(counts [24]int) {
+	most, busiest := 0, 0
+	for hour, count := range counts {
+		if count > most {
+			most, busiest = count, hour
+		}
+	}
+	c.columns.RemoveAll()
+	for hour, count := range counts {
+		height := float32(0)
+		if most > 0 {
+			height = hourChartHeight * float32(count) / float32(most)
+		}
+		rect := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+		rect.SetMinSize(fyne.NewSize(14, max(height, 1)))
+		label := canvas.NewText(fmt.Sprintf("%02d", hour), theme.Color(theme.ColorNameForeground))
+		label.TextSize = theme.CaptionTextSize()
+		label.Alignment = fyne.TextAlignCenter
+		c.columns.Add(container.NewBorder(nil, label, nil, nil,
+			container.NewVBox(layout.NewSpacer(), container.NewCenter(rect))))
+	}
+	if most == 0 {
+		c.peak.SetText("No kills recorded yet")
+		return
+	}
+	c.peak.SetText(fmt.Sprintf("Most active: %02d:00–%02d:00 (%d kills)", busiest, (busiest+1)%24, most))
+}
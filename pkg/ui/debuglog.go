@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"game-monitor/pkg/stats"
+)
+
+// debugLog receives verbose feed diagnostics. It discards everything unless
+// the "debug" preference is on; see configureDebugLog.
+var debugLog = slog.New(slog.DiscardHandler)
+
+// debugLogFile is the open debug.log, if file logging is enabled.
+var debugLogFile *os.File
+
+// configureDebugLog switches verbose logging on or off. When toFile is set the
+// output is also appended to debug.log in the app data directory.
+func configureDebugLog(enabled, toFile bool) error {
+	if debugLogFile != nil {
+		debugLogFile.Close()
+		debugLogFile = nil
+	}
+	if !enabled {
+		debugLog = slog.New(slog.DiscardHandler)
+		return nil
+	}
+	var out io.Writer = os.Stderr
+	var err error
+	if toFile {
+		path := filepath.Join(stats.Dir(), "debug.log")
+		debugLogFile, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			err = fmt.Errorf("failed to open debug log file: %w", err)
+		} else {
+			out = io.MultiWriter(os.Stderr, debugLogFile)
+		}
+	}
+	debugLog = slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return err
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"game-monitor/pkg/convert"
+)
+
+// dropAction is what a file dropped onto the window is used for.
+type dropAction int
+
+const (
+	dropIgnored dropAction = iota
+	dropMonitor            // the game's live log: monitor it
+	dropHistory            // a saved feed: show it in History
+	dropConvert            // any other game log: convert it to a feed
+)
+
+// classifyDrop decides by file name what to do with a dropped file. Game.log
+// is the log the game writes to while running, so it's monitored; older logs
+// (logbackups, .txt copies, gzipped logs) are converted instead.
+func classifyDrop(path string) dropAction {
+	name := filepath.Base(path)
+	switch {
+	case strings.EqualFold(name, "Game.log"):
+		return dropMonitor
+	case hasFeedExt(name):
+		return dropHistory
+	case convert.IsConvertibleLog(name):
+		return dropConvert
+	default:
+		return dropIgnored
+	}
+}
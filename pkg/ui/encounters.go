@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"game-monitor/pkg/processor"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// encounter is one recorded event between the player and another player.
+type encounter struct {
+	when   string // date and time, as near as the feed line records it
+	kind   feedEventKind
+	weapon string // "" when the line names none
+}
+
+// describe returns the encounter as a line of the drill-down list.
+func (e encounter) describe() string {
+	var what string
+	switch e.kind {
+	case feedEventKill:
+		what = "🎯 Kill"
+	case feedEventTeamKill:
+		what = "⚠️ Team kill"
+	case feedEventIncap:
+		what = "🩹 Incap"
+	case feedEventDeath:
+		what = "💀 Death"
+	}
+	if e.weapon != "" {
+		what += " • " + e.weapon
+	}
+	if e.when == "" {
+		return what
+	}
+	return e.when + "  " + what
+}
+
+// opponentEncounters lists every kill, team kill, incap and death between
+// player and opponent recorded in player's saved feeds in dir, oldest first.
+// Unreadable feeds are skipped.
+func opponentEncounters(dir, player, opponent string) ([]encounter, error) {
+	paths, err := playerFeedFiles(dir, player)
+	if err != nil {
+		return nil, err
+	}
+	var found []encounter
+	for _, path := range paths {
+		lines, err := loadFeedFile(path)
+		if err != nil {
+			continue
+		}
+		_, day, _, _ := parseFeedBase(feedBaseName(filepath.Base(path)))
+		for _, line := range lines {
+			event, ok := parseFeedEvent(feedLineText(line))
+			if !ok || !strings.EqualFold(event.name, opponent) {
+				continue
+			}
+			found = append(found, encounter{when: encounterTime(line, day), kind: event.kind, weapon: event.weapon})
+		}
+	}
+	return found, nil
+}
+
+// encounterTime formats a feed line's timestamp with its date, taking the
+// date from the feed's day when the timestamp format leaves it out.
+func encounterTime(line []FeedSegment, day string) string {
+	at, ok := feedLineTime(line)
+	if !ok {
+		return day
+	}
+	if at.Year() == 0 {
+		return day + " " + at.Format("15:04:05")
+	}
+	return at.In(processor.TimestampLocation()).Format("2006-01-02 15:04:05")
+}
+
+// showEncounters opens the drill-down for opponent: the kills, deaths and
+// incaps between them and player, each with its time and weapon. The feeds
+// are read in the background so a long history doesn't stall the window.
+func showEncounters(w fyne.Window, dir, player, opponent string) {
+	go func() {
+		found, err := opponentEncounters(dir, player, opponent)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("reading feed history: %w", err), w)
+				return
+			}
+			counts := make(map[feedEventKind]int)
+			for _, e := range found {
+				counts[e.kind]++
+			}
+			summary := fmt.Sprintf("Kills: %d  •  Deaths: %d  •  Incaps: %d",
+				counts[feedEventKill], counts[feedEventDeath], counts[feedEventIncap])
+			if n := counts[feedEventTeamKill]; n > 0 {
+				summary += fmt.Sprintf("  •  Team kills: %d", n)
+			}
+			var body fyne.CanvasObject = widget.NewLabel("No encounters in the saved feeds.")
+			if len(found) > 0 {
+				list := widget.NewList(
+					func() int { return len(found) },
+					func() fyne.CanvasObject { return widget.NewLabel("") },
+					func(id widget.ListItemID, o fyne.CanvasObject) {
+						o.(*widget.Label).SetText(found[id].describe())
+					},
+				)
+				body = list
+			}
+			content := container.NewBorder(
+				container.NewVBox(
+					widget.NewLabelWithStyle(summary, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+					widget.NewLabel(fmt.Sprintf("From %s's saved feeds, oldest first.", player)),
+				),
+				nil, nil, nil, body)
+			d := dialog.NewCustom("Encounters with "+opponent, "Close", content, w)
+			d.Resize(fyne.NewSize(520, 420))
+			d.Show()
+		})
+	}()
+}
+
+// newEncounterRow makes a stats list row: the name, linking to its RSI page,
+// and a button opening the encounters drill-down for it.
+func newEncounterRow() fyne.CanvasObject {
+	return container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.InfoIcon(), nil), widget.NewHyperlink("", nil))
+}
+
+// encounterRowParts returns the link and drill-down button of a row made by
+// newEncounterRow.
+func encounterRowParts(o fyne.CanvasObject) (*widget.Hyperlink, *widget.Button) {
+	row := o.(*fyne.Container)
+	return row.Objects[0].(*widget.Hyperlink), row.Objects[1].(*widget.Button)
+}
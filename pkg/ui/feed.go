@@ -1,68 +1,429 @@
 package ui
 
 import (
-	"fmt"
 	"game-monitor/pkg/processor"
+	"game-monitor/pkg/watcher"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
+// feedCategory classifies a feed line so the Feed tab can filter by event type.
+type feedCategory int
+
+const (
+	feedCategoryOther feedCategory = iota
+	feedCategoryKill
+	feedCategoryDeath
+	feedCategoryVehicle
+	feedCategoryTeamKill // kill of someone on the friends list; filtered with the kills
+)
+
+// categoryColor returns the theme color used for a category's text, or "" for the default.
+// Theme color names resolve per variant, so the lines stay readable in light and dark mode.
+func categoryColor(category feedCategory) fyne.ThemeColorName {
+	switch category {
+	case feedCategoryKill:
+		return theme.ColorNameSuccess
+	case feedCategoryDeath:
+		return theme.ColorNameError
+	case feedCategoryTeamKill:
+		return theme.ColorNameWarning
+	default:
+		return ""
+	}
+}
+
+// feedEntry is a single rendered feed line plus the raw log line that produced it.
+type feedEntry struct {
+	segments   []widget.RichTextSegment
+	rawLogLine string
+	category   feedCategory
+	info       bool      // informational line, see isInfoLine
+	at         time.Time // leading timestamp of the line, or when it arrived
+	shard      string    // shard the player was on when the line arrived
+	sample     bool      // from the sample log; shown but never saved
+}
+
+// classifyFeedLine determines the category of a processor output line based on its message prefix.
+// The line may still carry the leading timestamp, so the checks look anywhere in the line.
+func classifyFeedLine(line string) feedCategory {
+	switch {
+	case strings.Contains(line, processor.TeamKillPrefix):
+		return feedCategoryTeamKill
+	case strings.Contains(line, "You killed:") || strings.Contains(line, "You incapacitated:") ||
+		strings.Contains(line, "Mission Event: You destroyed") || strings.Contains(line, "Mission Event: You disabled"):
+		return feedCategoryKill
+	case strings.Contains(line, "You were killed by:") ||
+		strings.Contains(line, "You died") ||
+		strings.Contains(line, "You turned to a corpse") ||
+		strings.Contains(line, "You were incapacitated") ||
+		strings.Contains(line, "You bled out") ||
+		strings.Contains(line, "You suffered a hard death") ||
+		(strings.Contains(line, "Mission Event:") && strings.Contains(line, "died")):
+		return feedCategoryDeath
+	case strings.Contains(line, "Vehicle ") && (strings.Contains(line, " destroyed") || strings.Contains(line, " disabled")):
+		return feedCategoryVehicle
+	default:
+		return feedCategoryOther
+	}
+}
+
+// monitoringPrefix starts the feed line naming the log being monitored.
+const monitoringPrefix = "Monitoring: "
+
+// isInfoLine reports whether a feed line is informational, such as the
+// detected player or the monitored log, rather than an in-game event. Like
+// classifyFeedLine it allows for a leading timestamp.
+func isInfoLine(line string) bool {
+	for _, prefix := range []string{processor.DetectedPlayerPrefix, processor.PlayerChangedPrefix, monitoringPrefix} {
+		if strings.HasPrefix(line, prefix) || strings.Contains(line, " "+prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // logHandlerAdapter routes processed log events into the UI and uses native Fyne toasts.
 type logHandlerAdapter struct {
 	proc          *processor.Processor
 	outputRich    *widget.RichText
 	window        fyne.Window
 	onStatsUpdate func(playerName string) // callback to update stats
-	allSegments   []struct {
-		segments   []widget.RichTextSegment
-		rawLogLine string
-	} // stores all lines with raw log line
+	allSegments   []feedEntry             // stores all lines with raw log line
+	stored        int                     // lines ever stored, including ones since dropped from allSegments
+	feedFilter    map[feedCategory]bool   // categories currently shown in the feed
+	shardFilter   string                  // when set, only lines from this shard are shown
+	hideInfo      bool                    // when true, informational lines are left out of the feed
+	paused        bool                    // when true, new lines are buffered but not rendered
+	pausedAt      int                     // len(allSegments) when the feed was paused
+	onBuffered    func(pending int)       // called when a line is buffered while paused
+	displayed     []int                   // segment count of each line currently in outputRich, oldest first
+	scroll        *container.Scroll       // scroll container around outputRich
+	jumpBtn       *widget.Button          // "Jump to latest", shown when new lines arrive while scrolled up
+	lineLimit     int                     // max lines held by outputRich, see setLineLimit
+	backfilling   bool                    // true while existing log lines are replayed; suppresses sounds and notifications
+	grouped       bool                    // show the grouped view instead of the flat feed
+	groups        []*feedGroup            // visible lines grouped for the grouped view, oldest first
+	groupedLines  int                     // lines held by groups, kept within displayLimit
+	groupBox      *fyne.Container         // rows of the grouped view, one per group
+	groupScroll   *container.Scroll       // scroll container around groupBox, hidden unless grouped
+	recent        *recentKills            // recent kills panel, fed from storeEntry
+	beforeDrop    func()                  // called before storeEntry drops the oldest lines, e.g. to save them first
+	onProgress    func(watcher.Progress)  // called on the UI thread with the watcher's progress
+}
+
+// activeScroll returns the scroll container of the view being shown: the
+// grouped view or the flat feed.
+func (a *logHandlerAdapter) activeScroll() *container.Scroll {
+	if a.grouped && a.groupScroll != nil {
+		return a.groupScroll
+	}
+	return a.scroll
+}
+
+// atBottom reports whether the feed scroll is at (or within a few pixels of) the bottom.
+func (a *logHandlerAdapter) atBottom() bool {
+	scroll := a.activeScroll()
+	if scroll == nil || scroll.Content == nil {
+		return true
+	}
+	const threshold = 20
+	return scroll.Offset.Y+scroll.Size().Height >= scroll.Content.MinSize().Height-threshold
+}
+
+// followLatest keeps the feed pinned to the bottom if it was there before new
+// content arrived; otherwise it leaves the scroll position alone and offers
+// the "Jump to latest" button.
+func (a *logHandlerAdapter) followLatest(wasAtBottom bool) {
+	scroll := a.activeScroll()
+	if scroll == nil {
+		return
+	}
+	if wasAtBottom {
+		scroll.ScrollToBottom()
+		if a.jumpBtn != nil {
+			a.jumpBtn.Hide()
+		}
+		return
+	}
+	if a.jumpBtn != nil {
+		a.jumpBtn.Show()
+	}
+}
+
+// jumpToLatest scrolls to the newest line and hides the jump button.
+func (a *logHandlerAdapter) jumpToLatest() {
+	if scroll := a.activeScroll(); scroll != nil {
+		scroll.ScrollToBottom()
+	}
+	if a.jumpBtn != nil {
+		a.jumpBtn.Hide()
+	}
+}
+
+// The feed line limit bounds how many lines the feed RichText holds at once. Older
+// lines stay in allSegments (for saving and re-rendering) but are dropped from
+// the widget, so each append costs the same regardless of session length.
+const (
+	defaultFeedLineLimit = 1000
+	minFeedLineLimit     = 100
+	maxFeedLineLimit     = 50000
+	// maxStoredLines caps allSegments itself so memory stays bounded in very long sessions
+	maxStoredLines = 2 * maxFeedLineLimit
+)
+
+// displayLimit returns the effective feed line limit, kept within
+// minFeedLineLimit–maxFeedLineLimit whatever the preferences hold.
+func (a *logHandlerAdapter) displayLimit() int {
+	if a.lineLimit <= 0 {
+		return defaultFeedLineLimit
+	}
+	return min(max(a.lineLimit, minFeedLineLimit), maxFeedLineLimit)
+}
+
+// setLineLimit changes the feed line limit and re-renders the feed with it.
+func (a *logHandlerAdapter) setLineLimit(limit int) {
+	a.lineLimit = limit
+	a.refreshFeedDisplay()
+}
+
+// storeEntry appends an entry to allSegments, discarding the oldest entries past maxStoredLines.
+func (a *logHandlerAdapter) storeEntry(entry feedEntry) {
+	a.allSegments = append(a.allSegments, entry)
+	a.stored++
+	if a.recent != nil {
+		a.recent.add(entry)
+	}
+	if drop := len(a.allSegments) - maxStoredLines; drop > 0 {
+		if a.beforeDrop != nil {
+			a.beforeDrop()
+		}
+		a.allSegments = a.allSegments[drop:]
+		a.pausedAt = max(a.pausedAt-drop, 0)
+	}
+}
+
+// displaySegments returns the segments used to render an entry, including its
+// raw log line when raw display is enabled.
+func displaySegments(entry feedEntry) []widget.RichTextSegment {
+	if !ShowRawLogLines || entry.rawLogLine == "" {
+		return linkSegments(entry.segments)
+	}
+	segments := make([]widget.RichTextSegment, 0, len(entry.segments)+3)
+	segments = append(segments, linkSegments(entry.segments)...)
+	// Add a subtle separator before the raw log line
+	segments = append(segments,
+		&widget.TextSegment{Text: "    ↳ Raw: ", Style: widget.RichTextStyle{Inline: true}},
+		&widget.TextSegment{Text: entry.rawLogLine, Style: widget.RichTextStyle{Inline: true}},
+		&widget.TextSegment{Text: "\n", Style: widget.RichTextStyle{Inline: true}},
+	)
+	return segments
+}
+
+// appendToDisplay adds one entry to the bottom of the feed, dropping the oldest
+// displayed line once the window is full. Dropping is a reslice of the segment
+// slice, so appends stay roughly constant-time: the widget never holds more
+// than maxDisplayLines lines no matter how large allSegments grows.
+func (a *logHandlerAdapter) appendToDisplay(entry feedEntry) {
+	wasAtBottom := a.atBottom()
+	segments := displaySegments(entry)
+	a.outputRich.Segments = append(a.outputRich.Segments, segments...)
+	a.displayed = append(a.displayed, len(segments))
+	for len(a.displayed) > a.displayLimit() {
+		a.outputRich.Segments = a.outputRich.Segments[a.displayed[0]:]
+		a.displayed = a.displayed[1:]
+	}
+	a.outputRich.Refresh()
+	if a.grouped && a.groupBox != nil {
+		a.addToGroups(entry)
+	}
+	a.followLatest(wasAtBottom)
+}
+
+// setPaused pauses or resumes feed rendering. Lines arriving while paused are
+// kept in allSegments and flushed to the display on resume.
+func (a *logHandlerAdapter) setPaused(paused bool) {
+	if paused == a.paused {
+		return
+	}
+	a.paused = paused
+	if paused {
+		a.pausedAt = len(a.allSegments)
+		return
+	}
+	a.refreshFeedDisplay()
+}
+
+// clearLines empties the feed and its display. Stats and the stored line
+// count are left alone, so saving carries on after the cleared lines.
+func (a *logHandlerAdapter) clearLines() {
+	a.allSegments = make([]feedEntry, 0)
+	a.pausedAt = 0
+	if a.paused && a.onBuffered != nil {
+		a.onBuffered(0)
+	}
+	a.refreshFeedDisplay()
+	if a.jumpBtn != nil {
+		a.jumpBtn.Hide()
+	}
+}
+
+// renderedEntries returns the entries the display may show: everything, or only
+// the lines that existed when the feed was paused.
+func (a *logHandlerAdapter) renderedEntries() []feedEntry {
+	if a.paused && a.pausedAt <= len(a.allSegments) {
+		return a.allSegments[:a.pausedAt]
+	}
+	return a.allSegments
+}
+
+// isVisible reports whether an entry passes the current feed filter.
+func (a *logHandlerAdapter) isVisible(entry feedEntry) bool {
+	if a.shardFilter != "" && entry.shard != a.shardFilter {
+		return false
+	}
+	if a.hideInfo && entry.info {
+		return false
+	}
+	if a.feedFilter == nil {
+		return true
+	}
+	if entry.category == feedCategoryTeamKill {
+		return a.feedFilter[feedCategoryKill]
+	}
+	return a.feedFilter[entry.category]
+}
+
+// plainText returns an entry's text without markup, timestamp included.
+func (e feedEntry) plainText() string {
+	var b strings.Builder
+	for _, seg := range e.segments {
+		switch s := seg.(type) {
+		case *widget.TextSegment:
+			b.WriteString(s.Text)
+		case *widget.HyperlinkSegment:
+			b.WriteString(s.Text)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// visibleEntries returns the renderable entries that pass the feed filter.
+func (a *logHandlerAdapter) visibleEntries() []feedEntry {
+	entries := a.renderedEntries()
+	visible := make([]feedEntry, 0, len(entries))
+	for _, entry := range entries {
+		if a.isVisible(entry) {
+			visible = append(visible, entry)
+		}
+	}
+	return visible
+}
+
+// visibleText returns the plain text of every line passing the feed filter, one per line.
+func (a *logHandlerAdapter) visibleText() string {
+	var lines []string
+	for _, entry := range a.visibleEntries() {
+		lines = append(lines, entry.plainText())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isKillLine reports whether a feed line is one of the player's kills, team
+// kills included. Incaps and mission summaries share the kill category but
+// aren't kills.
+func isKillLine(line string) bool {
+	return strings.Contains(line, "You killed:")
+}
+
+// lastKillText returns the plain text of the most recent kill line, or "" if there is none.
+func (a *logHandlerAdapter) lastKillText() string {
+	for i := len(a.allSegments) - 1; i >= 0; i-- {
+		if text := a.allSegments[i].plainText(); isKillLine(text) {
+			return text
+		}
+	}
+	return ""
+}
+
+// recentKillNames returns the other participants of the latest kill and death
+// lines, newest first and without duplicates, for picking a name to ignore.
+func (a *logHandlerAdapter) recentKillNames(limit int) []string {
+	var names []string
+	seen := map[string]bool{}
+	for i := len(a.allSegments) - 1; i >= 0 && len(names) < limit; i-- {
+		victim, killer, ok := processor.KillParticipants(a.allSegments[i].rawLogLine)
+		if !ok {
+			continue
+		}
+		for _, name := range []string{victim, killer} {
+			if name != a.proc.PlayerName && !seen[name] && len(names) < limit {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// setFeedFilter shows or hides a category and re-renders the feed immediately.
+func (a *logHandlerAdapter) setFeedFilter(category feedCategory, show bool) {
+	if a.feedFilter == nil {
+		a.feedFilter = map[feedCategory]bool{
+			feedCategoryOther:   true,
+			feedCategoryKill:    true,
+			feedCategoryDeath:   true,
+			feedCategoryVehicle: true,
+		}
+	}
+	a.feedFilter[category] = show
+	a.refreshFeedDisplay()
+}
+
+// setHideInfo hides or shows informational lines and re-renders the feed.
+func (a *logHandlerAdapter) setHideInfo(hide bool) {
+	a.hideInfo = hide
+	a.refreshFeedDisplay()
 }
 
 // Helper to refresh outputRich based on ShowRawLogLines
 func (a *logHandlerAdapter) refreshFeedDisplay() {
-	// Debug: Print info about refresh
-	fmt.Printf("RefreshFeedDisplay called: allSegments count: %d, ShowRawLogLines: %v\n", len(a.allSegments), ShowRawLogLines)
+	debugLog.Debug("refreshing feed display", "stored", len(a.allSegments), "showRaw", ShowRawLogLines)
 
 	// Create a completely new segments array
-	displaySegments := make([]widget.RichTextSegment, 0)
+	rendered := make([]widget.RichTextSegment, 0)
+
+	// Only lines passing the filter are rendered; the underlying allSegments stay intact
+	// While paused only the lines from before the pause are shown, even if the
+	// raw-log or filter toggles force a re-render
+	visible := a.visibleEntries()
 
 	// Limit the number of displayed lines to prevent performance issues
-	const maxDisplayLines = 1000
+	limit := a.displayLimit()
 	startIdx := 0
-	if len(a.allSegments) > maxDisplayLines {
-		startIdx = len(a.allSegments) - maxDisplayLines
-		fmt.Printf("Limiting display: showing last %d lines (from %d to %d)\n", maxDisplayLines, startIdx, len(a.allSegments))
+	if len(visible) > limit {
+		startIdx = len(visible) - limit
+		debugLog.Debug("limiting feed display", "limit", limit, "from", startIdx, "to", len(visible))
 	}
 
-	for i := startIdx; i < len(a.allSegments); i++ {
-		entry := a.allSegments[i]
-
-		// Add the main message segments - make sure to copy each segment properly
-		for _, seg := range entry.segments {
-			displaySegments = append(displaySegments, seg)
-		}
-		// Add raw log line if enabled and available
-		if ShowRawLogLines && entry.rawLogLine != "" {
-			// Add a subtle separator before the raw log line
-			displaySegments = append(displaySegments, &widget.TextSegment{
-				Text:  "    ↳ Raw: ",
-				Style: widget.RichTextStyle{Inline: true},
-			})
-			displaySegments = append(displaySegments, &widget.TextSegment{
-				Text:  entry.rawLogLine,
-				Style: widget.RichTextStyle{Inline: true},
-			})
-			displaySegments = append(displaySegments, &widget.TextSegment{
-				Text:  "\n",
-				Style: widget.RichTextStyle{Inline: true},
-			})
-		}
+	a.displayed = a.displayed[:0]
+	for i := startIdx; i < len(visible); i++ {
+		// Add the main message segments plus the raw log line if enabled
+		segments := displaySegments(visible[i])
+		rendered = append(rendered, segments...)
+		a.displayed = append(a.displayed, len(segments))
 	}
 	// Replace the segments completely and force a refresh
-	a.outputRich.Segments = displaySegments
+	wasAtBottom := a.atBottom()
+	a.outputRich.Segments = rendered
 	a.outputRich.Refresh()
+	a.refreshGroups()
+	a.followLatest(wasAtBottom)
 
-	fmt.Printf("RefreshFeedDisplay completed: Set %d segments in outputRich\n", len(displaySegments))
+	debugLog.Debug("feed display refreshed", "segments", len(rendered))
 }
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newFeedAdapter returns a handler with a feed widget holding at most limit lines.
+func newFeedAdapter(limit int) *logHandlerAdapter {
+	return &logHandlerAdapter{outputRich: widget.NewRichText(), lineLimit: limit}
+}
+
+func TestAppendToDisplayKeepsWindow(t *testing.T) {
+	test.NewTempApp(t)
+	h := newFeedAdapter(100)
+	for i := 0; i < 250; i++ {
+		entry := textEntry(fmt.Sprintf("line %d", i))
+		h.storeEntry(entry)
+		h.appendToDisplay(entry)
+	}
+	if len(h.displayed) != 100 || len(h.outputRich.Segments) != 100 {
+		t.Fatalf("widget holds %d lines (%d segments), want 100", len(h.displayed), len(h.outputRich.Segments))
+	}
+	if first := h.outputRich.Segments[0].(*widget.TextSegment).Text; first != "line 150\n" {
+		t.Errorf("oldest displayed line = %q, want line 150", first)
+	}
+	if len(h.allSegments) != 250 {
+		t.Errorf("stored %d lines, want all 250 kept for saving and search", len(h.allSegments))
+	}
+}
+
+// BenchmarkFeedAppend appends a line to feeds of growing length. The time
+// per append should stay flat: the widget only ever holds the line limit.
+func TestDisplayLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, defaultFeedLineLimit},
+		{-5, defaultFeedLineLimit},
+		{10, minFeedLineLimit},
+		{minFeedLineLimit, minFeedLineLimit},
+		{2500, 2500},
+		{maxFeedLineLimit, maxFeedLineLimit},
+		{1000000, maxFeedLineLimit},
+	}
+	for _, tt := range tests {
+		h := &logHandlerAdapter{lineLimit: tt.limit}
+		if got := h.displayLimit(); got != tt.want {
+			t.Errorf("displayLimit() with lineLimit %d = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestLastKillText(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{name: "empty feed", want: ""},
+		{
+			name:  "kill before an incap",
+			lines: []string{"10:00:00 You killed: Pilot_One using Gallant", "10:00:05 You incapacitated: Pilot_Two"},
+			want:  "10:00:00 You killed: Pilot_One using Gallant",
+		},
+		{
+			name:  "kill before a mission summary",
+			lines: []string{"10:00:00 You killed: Pilot_One using Gallant", "10:00:05 Mission Event: You destroyed 2 vehicles (Anvil Hornet F7C, Drake Cutlass Black)"},
+			want:  "10:00:00 You killed: Pilot_One using Gallant",
+		},
+		{
+			name:  "team kill",
+			lines: []string{"10:00:00 You killed: Pilot_One using Gallant", "10:00:05 Team kill! You killed: Pilot_Two using Gallant"},
+			want:  "10:00:05 Team kill! You killed: Pilot_Two using Gallant",
+		},
+		{
+			name:  "only incaps",
+			lines: []string{"10:00:00 You incapacitated: Pilot_One", "10:00:05 Mission Event: You disabled 1 vehicle (Anvil Hornet F7C)"},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &logHandlerAdapter{}
+			for _, line := range tt.lines {
+				entry := textEntry(line)
+				entry.category = classifyFeedLine(line)
+				h.allSegments = append(h.allSegments, entry)
+			}
+			if got := h.lastKillText(); got != tt.want {
+				t.Errorf("lastKillText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiniFeedAdd(t *testing.T) {
+	m := &miniFeed{limit: 10}
+	at := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	for _, message := range []string{
+		"You killed: Pilot_One using Gallant",
+		"You incapacitated: Pilot_Two",
+		"Mission Event: You destroyed 2 vehicles (Anvil Hornet F7C, Drake Cutlass Black)",
+		"Team kill! You killed: Pilot_Three using Gallant",
+		"You were killed by: Pilot_Four using Gallant",
+		"Vehicle Anvil Hornet F7C destroyed",
+		"Monitoring: Game.log",
+	} {
+		m.add(message, at)
+	}
+	var got []string
+	for _, line := range m.lines {
+		got = append(got, line.plainText())
+	}
+	want := []string{
+		"10:00  You killed: Pilot_One using Gallant",
+		"10:00  Team kill! You killed: Pilot_Three using Gallant",
+		"10:00  You were killed by: Pilot_Four using Gallant",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mini feed lines = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkFeedAppend(b *testing.B) {
+	test.NewTempApp(b)
+	for _, session := range []int{1000, 10000, 50000} {
+		b.Run(fmt.Sprintf("session=%d", session), func(b *testing.B) {
+			h := newFeedAdapter(defaultFeedLineLimit)
+			for i := 0; i < session; i++ {
+				h.storeEntry(textEntry(fmt.Sprintf("line %d", i)))
+			}
+			h.refreshFeedDisplay()
+			entry := textEntry("You killed: Pilot_1 using Gallant Rifle")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.storeEntry(entry)
+				h.appendToDisplay(entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"strings"
+
+	"game-monitor/pkg/processor"
+)
+
+// unknownKiller is counted for a death whose feed line doesn't name the killer.
+const unknownKiller = "Unknown"
+
+// feedEventKind is the kind of event a saved feed line records.
+type feedEventKind int
+
+const (
+	feedEventKill feedEventKind = iota + 1
+	feedEventTeamKill
+	feedEventIncap
+	feedEventDeath
+)
+
+// feedEvent is a kill, incap or death read back from a saved feed line.
+type feedEvent struct {
+	kind   feedEventKind
+	name   string // the other player, NPC or cause
+	weapon string // "" when the line names none
+}
+
+// feedLineText joins a saved feed line's segments into its plain text.
+func feedLineText(line []FeedSegment) string {
+	var b strings.Builder
+	for _, seg := range line {
+		b.WriteString(seg.Text)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// parseFeedEvent reads the player's kill, team kill, incap or death from a
+// feed line's text, timestamp and all. Feeds keep only the rendered text, so
+// NPCs carry the display name the feed showed, and a death folded into a
+// crash summary without a killer is put down to unknownKiller.
+func parseFeedEvent(text string) (feedEvent, bool) {
+	switch {
+	case strings.Contains(text, "You killed: "):
+		victim, weapon, ok := parseKillMessage(text)
+		if !ok {
+			return feedEvent{}, false
+		}
+		kind := feedEventKill
+		if strings.Contains(text, processor.TeamKillPrefix) {
+			kind = feedEventTeamKill
+		}
+		return feedEvent{kind: kind, name: feedName(victim), weapon: weapon}, true
+	case strings.Contains(text, "You incapacitated: "):
+		_, target, _ := strings.Cut(text, "You incapacitated: ")
+		if target = feedName(target); target == "" {
+			return feedEvent{}, false
+		}
+		return feedEvent{kind: feedEventIncap, name: target}, true
+	case strings.Contains(text, "You were killed by: "):
+		_, rest, _ := strings.Cut(text, "You were killed by: ")
+		killer, weapon, _ := strings.Cut(rest, " using ")
+		return deathEvent(killer, weapon), true
+	case strings.Contains(text, "You died by "):
+		_, killer, _ := strings.Cut(text, "You died by ")
+		return deathEvent(killer, ""), true
+	case strings.Contains(text, "Mission Event:") && (strings.Contains(text, "and died") || strings.Contains(text, "died in a crash")):
+		killer, weapon := "", ""
+		if _, rest, ok := strings.Cut(text, "(killed by "); ok {
+			killer, weapon, _ = strings.Cut(strings.TrimSuffix(rest, ")"), " using ")
+		}
+		return deathEvent(killer, weapon), true
+	}
+	return feedEvent{}, false
+}
+
+// deathEvent builds a death to killer, or to unknownKiller when the line
+// names none.
+func deathEvent(killer, weapon string) feedEvent {
+	if killer = feedName(killer); killer == "" {
+		killer = unknownKiller
+	}
+	return feedEvent{kind: feedEventDeath, name: killer, weapon: strings.TrimSpace(weapon)}
+}
+
+// feedName strips the friend mark and org tag the feed adds to a name.
+func feedName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), friendMark)
+	name, _ = processor.StripOrgTag(name)
+	return name
+}
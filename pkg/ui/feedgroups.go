@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// feedGroupWindow matches the processor's aggregation window: consecutive
+// lines this close together are folded into one block in the grouped view.
+const feedGroupWindow = 5 * time.Second
+
+// feedGroup is one block of the grouped view: a run of lines about the same
+// player, each following the previous one within feedGroupWindow. Lines that
+// name no one, like mission summaries, join the run they fall in. Each group
+// owns its row, so its expanded state stays put as older groups are dropped.
+type feedGroup struct {
+	player  string // "" until a line names one
+	entries []feedEntry
+	row     *fyne.Container  // toggle and headline, with the lines below when expanded
+	toggle  *widget.Button   // shown once the group has more than one line
+	header  *widget.RichText // headline plus the number of events
+	body    *widget.RichText // every line of the group, shown when expanded
+}
+
+// feedEntryPlayer returns the other player, NPC or cause a kill, incap or
+// death line is about, or "" for any other line.
+func feedEntryPlayer(entry feedEntry) string {
+	event, ok := parseFeedEvent(entry.plainText())
+	if !ok {
+		return ""
+	}
+	return event.name
+}
+
+// accepts reports whether an entry about player continues the group.
+func (g *feedGroup) accepts(entry feedEntry, player string) bool {
+	gap := entry.at.Sub(g.entries[len(g.entries)-1].at)
+	return gap >= 0 && gap <= feedGroupWindow && (player == "" || g.player == "" || player == g.player)
+}
+
+// groupHeadline picks the line that represents a group: its mission summary
+// if the aggregator produced one, otherwise the first line.
+func groupHeadline(group []feedEntry) feedEntry {
+	for _, entry := range group {
+		if strings.Contains(entry.plainText(), "Mission Event:") {
+			return entry
+		}
+	}
+	return group[0]
+}
+
+// rowSegments returns an entry's segments without the trailing newline, for single-line rows.
+func rowSegments(entry feedEntry) []widget.RichTextSegment {
+	segments := linkSegments(entry.segments)
+	if n := len(segments); n > 0 {
+		if text, ok := segments[n-1].(*widget.TextSegment); ok && text.Text == "\n" {
+			segments = segments[:n-1]
+		}
+	}
+	return segments
+}
+
+// add appends an entry about player that the group accepts.
+func (g *feedGroup) add(entry feedEntry, player string) {
+	g.entries = append(g.entries, entry)
+	if g.player == "" {
+		g.player = player
+	}
+}
+
+// groupFeedEntries splits entries into groups, without building their rows.
+func groupFeedEntries(entries []feedEntry) []*feedGroup {
+	var groups []*feedGroup
+	for _, entry := range entries {
+		player := feedEntryPlayer(entry)
+		if n := len(groups); n > 0 && groups[n-1].accepts(entry, player) {
+			groups[n-1].add(entry, player)
+			continue
+		}
+		groups = append(groups, &feedGroup{player: player, entries: []feedEntry{entry}})
+	}
+	return groups
+}
+
+// buildRow creates the group's row and draws it.
+func (g *feedGroup) buildRow() {
+	g.header = widget.NewRichText()
+	g.body = widget.NewRichText()
+	g.body.Hide()
+	g.toggle = widget.NewButtonWithIcon("", theme.MenuExpandIcon(), func() {
+		if g.body.Visible() {
+			g.body.Hide()
+			g.toggle.SetIcon(theme.MenuExpandIcon())
+		} else {
+			g.body.Show()
+			g.toggle.SetIcon(theme.MenuDropDownIcon())
+		}
+		g.row.Refresh()
+	})
+	g.toggle.Importance = widget.LowImportance
+	g.toggle.Hide()
+	g.row = container.NewBorder(nil, g.body, g.toggle, nil, g.header)
+	g.refresh()
+}
+
+// refresh redraws the group's headline and lines.
+func (g *feedGroup) refresh() {
+	if len(g.entries) == 1 {
+		g.header.Segments = rowSegments(g.entries[0])
+		g.toggle.SetIcon(theme.MenuExpandIcon())
+		g.toggle.Hide()
+		g.body.Hide()
+	} else {
+		headline := rowSegments(groupHeadline(g.entries))
+		segments := make([]widget.RichTextSegment, 0, len(headline)+1)
+		segments = append(segments, headline...)
+		segments = append(segments, &widget.TextSegment{
+			Text:  fmt.Sprintf("  (%d events)", len(g.entries)),
+			Style: widget.RichTextStyle{Inline: true, TextStyle: fyne.TextStyle{Italic: true}},
+		})
+		g.header.Segments = segments
+		var lines []widget.RichTextSegment
+		for _, entry := range g.entries {
+			lines = append(lines, linkSegments(entry.segments)...)
+		}
+		g.body.Segments = lines
+		g.body.Refresh()
+		g.toggle.Show()
+	}
+	g.header.Refresh()
+}
+
+// addToGroups extends the grouped view with one more visible line, joining
+// the last group when it continues it, and drops the oldest lines past the
+// display limit. Only the groups touched are redrawn.
+func (a *logHandlerAdapter) addToGroups(entry feedEntry) {
+	player := feedEntryPlayer(entry)
+	if n := len(a.groups); n > 0 && a.groups[n-1].accepts(entry, player) {
+		a.groups[n-1].add(entry, player)
+		a.groups[n-1].refresh()
+	} else {
+		g := &feedGroup{player: player, entries: []feedEntry{entry}}
+		g.buildRow()
+		a.groups = append(a.groups, g)
+		a.groupBox.Objects = append(a.groupBox.Objects, g.row)
+	}
+	a.groupedLines++
+	a.trimGroups()
+	a.groupBox.Refresh()
+}
+
+// trimGroups drops the oldest grouped lines until no more than the display
+// limit are shown, removing groups left empty.
+func (a *logHandlerAdapter) trimGroups() {
+	for a.groupedLines > a.displayLimit() && len(a.groups) > 0 {
+		first := a.groups[0]
+		drop := min(a.groupedLines-a.displayLimit(), len(first.entries))
+		first.entries = first.entries[drop:]
+		a.groupedLines -= drop
+		if len(first.entries) == 0 {
+			a.groups = a.groups[1:]
+			a.groupBox.Objects = a.groupBox.Objects[1:]
+			continue
+		}
+		first.refresh()
+	}
+}
+
+// refreshGroups rebuilds the grouped view from the visible lines, e.g. after
+// the filter changed. New lines are added with addToGroups instead.
+func (a *logHandlerAdapter) refreshGroups() {
+	if a.groupBox == nil || !a.grouped {
+		return
+	}
+	visible := a.visibleEntries()
+	if limit := a.displayLimit(); len(visible) > limit {
+		visible = visible[len(visible)-limit:]
+	}
+	a.groups = groupFeedEntries(visible)
+	a.groupBox.Objects = make([]fyne.CanvasObject, 0, len(a.groups))
+	for _, g := range a.groups {
+		g.buildRow()
+		a.groupBox.Objects = append(a.groupBox.Objects, g.row)
+	}
+	a.groupedLines = len(visible)
+	a.groupBox.Refresh()
+}
+
+// setGrouped switches between the flat feed and the grouped view.
+func (a *logHandlerAdapter) setGrouped(grouped bool) {
+	a.grouped = grouped
+	if a.groupScroll == nil || a.scroll == nil {
+		return
+	}
+	if grouped {
+		a.scroll.Hide()
+		a.groupScroll.Show()
+		a.refreshGroups()
+	} else {
+		a.groupScroll.Hide()
+		a.scroll.Show()
+	}
+	a.jumpToLatest()
+}
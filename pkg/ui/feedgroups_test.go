@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestGroupFeedEntries(t *testing.T) {
+	base := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	entry := func(sec int, text string) feedEntry {
+		e := textEntry(text)
+		e.at = base.Add(time.Duration(sec) * time.Second)
+		return e
+	}
+	tests := []struct {
+		name    string
+		entries []feedEntry
+		want    []int // group sizes
+	}{
+		{
+			name: "same player within window",
+			entries: []feedEntry{
+				entry(0, "You incapacitated: Pilot_One"),
+				entry(2, "You killed: Pilot_One using Gallant"),
+			},
+			want: []int{2},
+		},
+		{
+			name: "different players within window",
+			entries: []feedEntry{
+				entry(0, "You killed: Pilot_One using Gallant"),
+				entry(2, "You killed: Pilot_Two using Gallant"),
+			},
+			want: []int{1, 1},
+		},
+		{
+			name: "same player outside window",
+			entries: []feedEntry{
+				entry(0, "You incapacitated: Pilot_One"),
+				entry(10, "You killed: Pilot_One using Gallant"),
+			},
+			want: []int{1, 1},
+		},
+		{
+			name: "summary joins its player's run",
+			entries: []feedEntry{
+				entry(0, "You killed: Pilot_One using Gallant"),
+				entry(1, "Mission Event: You destroyed 2 vehicles (Anvil Hornet F7C, Drake Cutlass Black) and killed 1 player"),
+				entry(2, "You were killed by: Pilot_One using Gallant"),
+			},
+			want: []int{3},
+		},
+		{
+			name: "unnamed line then a player",
+			entries: []feedEntry{
+				entry(0, "Mission Event: You destroyed 2 vehicles (Anvil Hornet F7C, Drake Cutlass Black)"),
+				entry(1, "You killed: Pilot_One using Gallant"),
+				entry(2, "You killed: Pilot_Two using Gallant"),
+			},
+			want: []int{2, 1},
+		},
+		{
+			name: "clock going backwards",
+			entries: []feedEntry{
+				entry(5, "You incapacitated: Pilot_One"),
+				entry(0, "You killed: Pilot_One using Gallant"),
+			},
+			want: []int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, group := range groupFeedEntries(tt.entries) {
+				got = append(got, len(group.entries))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("group sizes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newGroupedAdapter returns a handler showing the grouped view, holding at most limit lines.
+func newGroupedAdapter(limit int) *logHandlerAdapter {
+	h := newFeedAdapter(limit)
+	h.scroll = container.NewScroll(h.outputRich)
+	h.groupBox = container.NewVBox()
+	h.groupScroll = container.NewVScroll(h.groupBox)
+	h.jumpBtn = widget.NewButton("Jump to latest", nil)
+	h.jumpBtn.Hide()
+	h.setGrouped(true)
+	return h
+}
+
+func TestAddToGroups(t *testing.T) {
+	test.NewTempApp(t)
+	base := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	h := newGroupedAdapter(minFeedLineLimit)
+	add := func(sec int, text string) {
+		e := textEntry(text)
+		e.at = base.Add(time.Duration(sec) * time.Second)
+		h.storeEntry(e)
+		h.appendToDisplay(e)
+	}
+	add(0, "You incapacitated: Pilot_One")
+	add(2, "You killed: Pilot_One using Gallant")
+	add(3, "You killed: Pilot_Two using Gallant")
+	if len(h.groups) != 2 || len(h.groups[0].entries) != 2 || len(h.groupBox.Objects) != 2 {
+		t.Fatalf("got %d groups (%d rows), want a group of 2 and one of 1", len(h.groups), len(h.groupBox.Objects))
+	}
+
+	// An expanded group keeps its row, and stays expanded, as older lines drop out
+	first := h.groups[1]
+	first.toggle.OnTapped()
+	if !first.body.Visible() {
+		t.Fatal("tapping the toggle didn't expand the group")
+	}
+	add(4, "You killed: Pilot_Two using Gallant") // joins the expanded group
+	for i := 0; i < minFeedLineLimit; i++ {
+		if h.groups[0] == first {
+			break
+		}
+		add(100+i*10, fmt.Sprintf("You killed: Pilot_%d using Gallant", i))
+	}
+	if h.groupedLines > minFeedLineLimit {
+		t.Errorf("grouped view holds %d lines, want at most %d", h.groupedLines, minFeedLineLimit)
+	}
+	if h.groups[0] != first || h.groupBox.Objects[0] != first.row {
+		t.Fatal("the expanded group should now be the oldest one shown")
+	}
+	if !first.body.Visible() {
+		t.Error("the expanded group collapsed when older groups were dropped")
+	}
+	lines := 0
+	for _, g := range h.groups {
+		lines += len(g.entries)
+	}
+	if lines != h.groupedLines {
+		t.Errorf("groups hold %d lines, groupedLines says %d", lines, h.groupedLines)
+	}
+}
+
+func TestGroupedViewFollowsOnlyAtBottom(t *testing.T) {
+	test.NewTempApp(t)
+	h := newGroupedAdapter(minFeedLineLimit)
+	h.groupScroll.Resize(fyne.NewSize(300, 100))
+	base := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	add := func(i int) {
+		e := textEntry(fmt.Sprintf("You killed: Pilot_%d using Gallant", i))
+		e.at = base.Add(time.Duration(i) * time.Minute)
+		h.storeEntry(e)
+		h.appendToDisplay(e)
+	}
+	for i := 0; i < 30; i++ {
+		add(i)
+	}
+	if !h.atBottom() {
+		t.Fatal("the grouped view should follow new lines while at the bottom")
+	}
+	h.groupScroll.ScrollToTop()
+	add(30)
+	if h.groupScroll.Offset.Y != 0 {
+		t.Errorf("scrolled away from the top to %v while reading older lines", h.groupScroll.Offset.Y)
+	}
+	if !h.jumpBtn.Visible() {
+		t.Error("the jump button should show when lines arrive while scrolled up")
+	}
+}
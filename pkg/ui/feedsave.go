@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// feedAutosaveInterval is how often the live feed is written to disk while
+// new lines keep arriving, so a crash loses at most this much of it.
+const feedAutosaveInterval = 30 * time.Second
+
+// feedSegments converts an entry back into the saved feed form, one
+// FeedSegment per rendered segment with the trailing "\n" kept.
+func (e feedEntry) feedSegments() []FeedSegment {
+	line := make([]FeedSegment, 0, len(e.segments))
+	for _, seg := range e.segments {
+		switch s := seg.(type) {
+		case *widget.TextSegment:
+			line = append(line, FeedSegment{Type: "text", Text: s.Text})
+		case *widget.HyperlinkSegment:
+			link := ""
+			if s.URL != nil {
+				link = s.URL.String()
+			}
+			line = append(line, FeedSegment{Type: "hyperlink", Text: s.Text, URL: link})
+		}
+	}
+	return line
+}
+
+// savedLines returns every stored feed line in the saved feed form,
+// including lines hidden by the filter or scrolled out of the display.
+// Sample lines are left out.
+func (a *logHandlerAdapter) savedLines() [][]FeedSegment {
+	lines := make([][]FeedSegment, 0, len(a.allSegments))
+	for _, entry := range a.allSegments {
+		if !entry.sample {
+			lines = append(lines, entry.feedSegments())
+		}
+	}
+	return lines
+}
+
+// linesSince returns, in the saved feed form, the lines stored after the
+// first count lines. Lines already dropped from allSegments and sample lines
+// are skipped.
+func (a *logHandlerAdapter) linesSince(count int) [][]FeedSegment {
+	newLines := max(min(a.stored-count, len(a.allSegments)), 0)
+	lines := make([][]FeedSegment, 0, newLines)
+	for _, entry := range a.allSegments[len(a.allSegments)-newLines:] {
+		if !entry.sample {
+			lines = append(lines, entry.feedSegments())
+		}
+	}
+	return lines
+}
+
+// restoreLines puts lines from a saved feed in front of the live feed, e.g.
+// when resuming today's feed file after a restart.
+func (a *logHandlerAdapter) restoreLines(lines [][]FeedSegment) {
+	restored := make([]feedEntry, 0, len(lines)+len(a.allSegments))
+	for _, line := range lines {
+		var text strings.Builder
+		for _, seg := range line {
+			text.WriteString(seg.Text)
+		}
+		entry := feedEntry{
+			segments: renderFeedLines([][]FeedSegment{line}),
+			category: classifyFeedLine(text.String()),
+		}
+		entry.at, _ = feedLineTime(line)
+		restored = append(restored, entry)
+	}
+	if a.paused {
+		a.pausedAt += len(restored)
+	}
+	a.allSegments = append(restored, a.allSegments...)
+	a.stored += len(restored)
+	a.refreshFeedDisplay()
+}
+
+// latestFeedFile returns the most recently written feed saved for player on
+// date (Player_YYYY-MM-DD.jsonl or Player_YYYY-MM-DD_N.jsonl, or the legacy
+// .json forms), or "".
+func latestFeedFile(dir, player, date string) string {
+	pattern := regexp.MustCompile(`^` + regexp.QuoteMeta(player+"_"+date) + `(_\d+)?\.jsonl?$`)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	latest := ""
+	var latestMod time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !pattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestMod) {
+			latest, latestMod = filepath.Join(dir, entry.Name()), info.ModTime()
+		}
+	}
+	return latest
+}
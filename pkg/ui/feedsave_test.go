@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestFeedEntrySegments(t *testing.T) {
+	link, _ := url.Parse("https://robertsspaceindustries.com/citizens/Pilot_1")
+	tests := []struct {
+		name     string
+		segments []widget.RichTextSegment
+		want     []FeedSegment
+	}{
+		{
+			name:     "text only",
+			segments: []widget.RichTextSegment{&widget.TextSegment{Text: "10:00:00 Monitoring: Game.log\n"}},
+			want:     []FeedSegment{{Type: "text", Text: "10:00:00 Monitoring: Game.log\n"}},
+		},
+		{
+			name: "text and hyperlink",
+			segments: []widget.RichTextSegment{
+				&widget.TextSegment{Text: "10:00:00 You killed: "},
+				&widget.HyperlinkSegment{Text: "Pilot_1", URL: link},
+				&widget.TextSegment{Text: "\n"},
+			},
+			want: []FeedSegment{
+				{Type: "text", Text: "10:00:00 You killed: "},
+				{Type: "hyperlink", Text: "Pilot_1", URL: link.String()},
+				{Type: "text", Text: "\n"},
+			},
+		},
+		{
+			name:     "hyperlink without a URL",
+			segments: []widget.RichTextSegment{&widget.HyperlinkSegment{Text: "Pilot_1"}},
+			want:     []FeedSegment{{Type: "hyperlink", Text: "Pilot_1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (feedEntry{segments: tt.segments}).feedSegments(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("feedSegments = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// textEntry returns a feed entry holding a single text line.
+func textEntry(text string) feedEntry {
+	return feedEntry{segments: []widget.RichTextSegment{&widget.TextSegment{Text: text + "\n"}}}
+}
+
+func TestStoreEntrySavesBeforeDrop(t *testing.T) {
+	h := &logHandlerAdapter{}
+	var saved [][]FeedSegment
+	savedCount := 0
+	h.beforeDrop = func() {
+		saved = append(saved, h.linesSince(savedCount)...)
+		savedCount = h.stored
+	}
+	total := maxStoredLines + 10
+	for i := 0; i < total; i++ {
+		h.storeEntry(textEntry(fmt.Sprintf("line %d", i)))
+	}
+	if len(h.allSegments) != maxStoredLines {
+		t.Fatalf("stored %d lines, want the cap of %d", len(h.allSegments), maxStoredLines)
+	}
+	// Everything stored, saved or not, is either in the file or in the buffer
+	saved = append(saved, h.linesSince(savedCount)...)
+	if len(saved) != total {
+		t.Fatalf("saved %d lines, want %d", len(saved), total)
+	}
+	for i, line := range saved {
+		if want := fmt.Sprintf("line %d\n", i); len(line) != 1 || line[0].Text != want {
+			t.Fatalf("saved line %d = %+v, want %q", i, line, want)
+		}
+	}
+	if lines := h.savedLines(); len(lines) != maxStoredLines || lines[0][0].Text != "line 10\n" {
+		t.Errorf("savedLines starts at %q with %d lines, want line 10 and %d lines", lines[0][0].Text, len(lines), maxStoredLines)
+	}
+}
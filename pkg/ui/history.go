@@ -1,11 +1,25 @@
 package ui
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"game-monitor/pkg/processor"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -23,8 +37,518 @@ func NewHistoryView() *HistoryView {
 
 // Update updates the history view with new data.
 func (h *HistoryView) Update(data string, logTime time.Time) {
-	localTime := logTime.Local().Format("02.01.2006, 15:04 (MST)")
+	localTime := processor.FormatTimestamp(logTime)
 	formattedData := fmt.Sprintf("[%s] %s", localTime, data)
 	// Append formattedData to the history view
 	h.container.Add(widget.NewLabel(formattedData))
 }
+
+// renderFeedLines converts saved feed lines into RichText segments for the
+// history view, coloring kill and death lines the same way as the live feed.
+func renderFeedLines(linesData [][]FeedSegment) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	for _, line := range linesData {
+		var lineText strings.Builder
+		for _, seg := range line {
+			lineText.WriteString(seg.Text)
+		}
+		style := widget.RichTextStyle{Inline: true, ColorName: categoryColor(classifyFeedLine(lineText.String()))}
+
+		var lineSegments []widget.RichTextSegment
+		var textBuffer strings.Builder
+		for _, seg := range line {
+			if seg.Type == "text" {
+				if seg.Text == "\n" {
+					if textBuffer.Len() > 0 {
+						lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: style})
+						textBuffer.Reset()
+					}
+					continue
+				}
+				textBuffer.WriteString(seg.Text)
+			} else if seg.Type == "hyperlink" {
+				if textBuffer.Len() > 0 {
+					lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: style})
+					textBuffer.Reset()
+				}
+				u, _ := url.Parse(seg.URL)
+				text := seg.Text
+				if strings.Contains(seg.URL, "/citizens/") {
+					text = markFriend(seg.Text, seg.Text)
+				}
+				lineSegments = append(lineSegments, &widget.HyperlinkSegment{Text: text, URL: u})
+			}
+		}
+		if textBuffer.Len() > 0 {
+			lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: style})
+		}
+		lineSegments = append(lineSegments, &widget.TextSegment{Text: "\n", Style: widget.RichTextStyle{Inline: true}})
+		segments = append(segments, lineSegments...)
+	}
+	return segments
+}
+
+// feedLineTime parses the leading timestamp from a saved feed line's first text segment.
+func feedLineTime(line []FeedSegment) (time.Time, bool) {
+	if len(line) == 0 || line[0].Type != "text" {
+		return time.Time{}, false
+	}
+	return processor.ParseTimestampPrefix(line[0].Text)
+}
+
+// Saved feeds are JSON Lines, one []FeedSegment per line, so new lines can be
+// appended without rewriting the file. Older feeds are a single indented JSON
+// document and are still read.
+const (
+	feedExt       = ".jsonl"
+	legacyFeedExt = ".json"
+)
+
+// feedNameRegex matches the base name of a saved feed: a name, usually the
+// player's, then _YYYY-MM-DD, then optionally _ and a suffix, such as the _2
+// of a second feed that day or the _merged of a merged log.
+var feedNameRegex = regexp.MustCompile(`^(.+?)_([0-9]{4}-[0-9]{2}-[0-9]{2})(?:_(.+))?$`)
+
+// parseFeedBase splits a feed's base name, without extension, into the name,
+// the day and the suffix after it, reporting whether it's named like a feed.
+func parseFeedBase(base string) (name, day, suffix string, ok bool) {
+	m := feedNameRegex.FindStringSubmatch(base)
+	if m == nil {
+		return "", "", "", false
+	}
+	if _, err := time.Parse("2006-01-02", m[2]); err != nil {
+		return "", "", "", false
+	}
+	return m[1], m[2], m[3], true
+}
+
+// hasFeedExt reports whether a file name has a feed extension, either form.
+func hasFeedExt(name string) bool {
+	return strings.HasSuffix(name, feedExt) || strings.HasSuffix(name, legacyFeedExt)
+}
+
+// isFeedFile reports whether a file name in the data dir is a saved feed. The
+// data folder can be any folder the user picks, so only names following the
+// feed pattern count; stats, the event log and anything else are left alone.
+func isFeedFile(name string) bool {
+	if !hasFeedExt(name) {
+		return false
+	}
+	_, _, _, ok := parseFeedBase(feedBaseName(name))
+	return ok
+}
+
+// feedBaseName strips the feed extension, either form, from a file name.
+func feedBaseName(name string) string {
+	if base, ok := strings.CutSuffix(name, feedExt); ok {
+		return base
+	}
+	return strings.TrimSuffix(name, legacyFeedExt)
+}
+
+// notesExt is the suffix of the plain-text notes kept next to a feed, e.g.
+// Player_2024-05-01.notes.txt for Player_2024-05-01.jsonl.
+const notesExt = ".notes.txt"
+
+// feedNotesPath returns the notes file belonging to a feed file.
+func feedNotesPath(feedPath string) string {
+	return filepath.Join(filepath.Dir(feedPath), feedBaseName(filepath.Base(feedPath))+notesExt)
+}
+
+// loadFeedNotes returns a feed's notes, or "" if it has none.
+func loadFeedNotes(feedPath string) string {
+	data, err := os.ReadFile(feedNotesPath(feedPath))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// saveFeedNotes writes a feed's notes. Blank notes remove the file.
+func saveFeedNotes(feedPath, notes string) error {
+	path := feedNotesPath(feedPath)
+	if strings.TrimSpace(notes) == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(notes), 0644)
+}
+
+// parseJumpTime parses a time typed into the history's jump box: a full date
+// and time, a timestamp in the feed's format, or just a clock time, which is
+// taken on the same day as ref.
+func parseJumpTime(text string, ref time.Time) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	loc := processor.TimestampLocation()
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t, true
+		}
+	}
+	if t, ok := processor.ParseTimestampPrefix(text); ok {
+		return t, true
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			day := ref.In(loc)
+			return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// firstLineAtOrAfter returns the index of the first line stamped at or after
+// t. Lines without a readable timestamp carry the time of the line before
+// them, like in mergeFeeds, so they never match ahead of their neighbours.
+func firstLineAtOrAfter(lines [][]FeedSegment, t time.Time) (int, bool) {
+	var last time.Time
+	for i, line := range lines {
+		if at, ok := feedLineTime(line); ok {
+			last = at
+		}
+		if !last.IsZero() && !last.Before(t) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// firstFeedTime returns the first readable timestamp in a feed.
+func firstFeedTime(lines [][]FeedSegment) (time.Time, bool) {
+	for _, line := range lines {
+		if t, ok := feedLineTime(line); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// loadFeedFile reads a saved feed in either format.
+func loadFeedFile(path string) ([][]FeedSegment, error) {
+	if !strings.HasSuffix(path, feedExt) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var lines [][]FeedSegment
+		if err := json.Unmarshal(data, &lines); err != nil {
+			return nil, fmt.Errorf("%s is not a valid feed file: %w", filepath.Base(path), err)
+		}
+		return lines, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines [][]FeedSegment
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line []FeedSegment
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("%s is not a valid feed file (line %d): %w", filepath.Base(path), n, err)
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// mergeFeeds combines two feeds in timestamp order. Lines without a
+// timestamp keep the time of the line before them so they stay in place.
+func mergeFeeds(a, b [][]FeedSegment) [][]FeedSegment {
+	type timedLine struct {
+		at   time.Time
+		line []FeedSegment
+	}
+	var merged []timedLine
+	for _, feed := range [][][]FeedSegment{a, b} {
+		var last time.Time
+		for _, line := range feed {
+			if t, ok := feedLineTime(line); ok {
+				last = t
+			}
+			merged = append(merged, timedLine{at: last, line: line})
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].at.Before(merged[j].at)
+	})
+	out := make([][]FeedSegment, len(merged))
+	for i, m := range merged {
+		out[i] = m.line
+	}
+	return out
+}
+
+// listFeedFiles returns the names of the saved feeds in dir, newest first by
+// modification time.
+func listFeedFiles(dir string) []string {
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	modTimes := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.IsDir() || !isFeedFile(entry.Name()) {
+			continue
+		}
+		names = append(names, entry.Name())
+		if info, err := entry.Info(); err == nil {
+			modTimes[entry.Name()] = info.ModTime()
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return modTimes[names[i]].After(modTimes[names[j]])
+	})
+	return names
+}
+
+// isSessionFeed reports whether a feed file name is one the app saved for a
+// session, Player_YYYY-MM-DD with an optional _N, rather than a merged or
+// renamed log.
+func isSessionFeed(name string) bool {
+	if !isFeedFile(name) {
+		return false
+	}
+	_, _, suffix, _ := parseFeedBase(feedBaseName(name))
+	if suffix == "" {
+		return true
+	}
+	n, err := strconv.Atoi(suffix)
+	return err == nil && n >= 1
+}
+
+// pruneFeeds deletes the session feeds in dir beyond the newest keep,
+// together with their .txt copies and notes, and returns how many feeds were
+// deleted and the bytes freed. keep <= 0 keeps everything. Only files named
+// like a session feed are considered, so stats, merged or renamed logs and
+// anything else in the folder are safe.
+func pruneFeeds(dir string, keep int) (removed int, freed int64, err error) {
+	var names []string
+	for _, name := range listFeedFiles(dir) {
+		if isSessionFeed(name) {
+			names = append(names, name)
+		}
+	}
+	if keep <= 0 || len(names) <= keep {
+		return 0, 0, nil
+	}
+	var errs []error
+	for _, name := range names[keep:] {
+		feedRemoved := false
+		for i, path := range pairedFeedFiles(dir, name) {
+			info, statErr := os.Stat(path)
+			if rmErr := os.Remove(path); rmErr != nil {
+				errs = append(errs, rmErr)
+				continue
+			}
+			if statErr == nil {
+				freed += info.Size()
+			}
+			// The feed itself comes first; its companions don't make it a removed feed
+			if i == 0 {
+				feedRemoved = true
+			}
+		}
+		if feedRemoved {
+			removed++
+		}
+	}
+	return removed, freed, errors.Join(errs...)
+}
+
+// clearLogsAndStats deletes every saved feed in dir, with its .txt copy and
+// notes, and every player's stats file. The data folder can be one the user
+// also keeps other files in, so nothing else is touched: not the session log,
+// name rules and mappings, the event log or a stats reset backup.
+func clearLogsAndStats(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	remove := func(path string) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+		case isFeedFile(name):
+			for _, path := range pairedFeedFiles(dir, name) {
+				remove(path)
+			}
+		case strings.HasSuffix(name, "_stats.json"):
+			remove(filepath.Join(dir, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// formatBytes formats a size for display, e.g. "3.2 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, prefix := float64(n)/unit, 0
+	for value >= unit && prefix < 3 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGT"[prefix])
+}
+
+// writeFeedFile saves feed lines, as JSON Lines unless path has the legacy .json extension.
+func writeFeedFile(path string, lines [][]FeedSegment) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	if !strings.HasSuffix(path, feedExt) {
+		enc.SetIndent("", "  ")
+		return enc.Encode(lines)
+	}
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendFeedFile adds lines to the end of a JSON Lines feed, creating it if needed.
+func appendFeedFile(path string, lines [][]FeedSegment) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// migrateFeeds rewrites every legacy .json feed in dir as .jsonl and removes
+// the original, keeping its modification time so the history order holds.
+// Files that don't parse as a feed (e.g. sessions.json) are left alone.
+func migrateFeeds(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isFeedFile(name) || strings.HasSuffix(name, feedExt) {
+			continue
+		}
+		oldPath := filepath.Join(dir, name)
+		newPath := filepath.Join(dir, feedBaseName(name)+feedExt)
+		if _, err := os.Stat(newPath); err == nil {
+			continue
+		}
+		lines, err := loadFeedFile(oldPath)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if err := writeFeedFile(newPath, lines); err != nil {
+			return migrated, fmt.Errorf("failed to migrate %s: %w", name, err)
+		}
+		os.Chtimes(newPath, info.ModTime(), info.ModTime())
+		if err := os.Remove(oldPath); err != nil {
+			return migrated, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// showMergeDialog lets the user pick two saved feeds and write them into a
+// new combined file. The original files are left untouched.
+func showMergeDialog(feedDir string, feedFiles []string, parent fyne.Window, onMerged func(filename string)) {
+	if len(feedFiles) < 2 {
+		dialog.ShowInformation("Merge Logs", "At least two history logs are needed to merge.", parent)
+		return
+	}
+	firstSelect := widget.NewSelect(feedFiles, nil)
+	secondSelect := widget.NewSelect(feedFiles, nil)
+	nameEntry := widget.NewEntry()
+	firstSelect.OnChanged = func(selected string) {
+		nameEntry.SetText(feedBaseName(selected) + "_merged")
+	}
+	firstSelect.SetSelectedIndex(0)
+	secondSelect.SetSelectedIndex(1)
+
+	dialog.ShowForm("Merge Logs", "Merge", "Cancel", []*widget.FormItem{
+		widget.NewFormItem("First log", firstSelect),
+		widget.NewFormItem("Second log", secondSelect),
+		widget.NewFormItem("Save as", nameEntry),
+	}, func(confirm bool) {
+		if !confirm {
+			return
+		}
+		if firstSelect.Selected == "" || secondSelect.Selected == "" || firstSelect.Selected == secondSelect.Selected {
+			dialog.ShowError(fmt.Errorf("please select two different logs"), parent)
+			return
+		}
+		name := feedBaseName(strings.TrimSpace(nameEntry.Text))
+		if err := validateFeedName(name); err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		outName := name + feedExt
+		if outName == firstSelect.Selected || outName == secondSelect.Selected {
+			dialog.ShowError(fmt.Errorf("the merged log must not replace one of its sources"), parent)
+			return
+		}
+		first, err := loadFeedFile(filepath.Join(feedDir, firstSelect.Selected))
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		second, err := loadFeedFile(filepath.Join(feedDir, secondSelect.Selected))
+		if err != nil {
+			dialog.ShowError(err, parent)
+			return
+		}
+		outPath := filepath.Join(feedDir, outName)
+		save := func() {
+			if err := writeFeedFile(outPath, mergeFeeds(first, second)); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to save merged log: %w", err), parent)
+				return
+			}
+			if onMerged != nil {
+				onMerged(outName)
+			}
+		}
+		if _, err := os.Stat(outPath); err == nil {
+			dialog.ShowConfirm("Overwrite Log?", outName+" already exists. Overwrite it?", func(overwrite bool) {
+				if overwrite {
+					save()
+				}
+			}, parent)
+			return
+		}
+		save()
+	}, parent)
+}
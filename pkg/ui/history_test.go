@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsFeedFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Player_2024-05-01.jsonl", true},
+		{"Player_2024-05-01_2.jsonl", true},
+		{"Player_2024-05-01.json", true},
+		{"Some_Player_2024-05-01.jsonl", true},
+		{"Player_2024-05-01_merged.jsonl", true},
+		{"Player_stats.json", false},
+		{"Player_stats.bak.json", false},
+		{"events-2024-05-01.jsonl", false},
+		{"sessions.json", false},
+		{"namerules.json", false},
+		{"weaponnames.json", false},
+		{"package.json", false},
+		{"Player_2024-13-01.jsonl", false},
+		{"Player_2024-05-01.txt", false},
+		{"Player_2024-05-01.notes.txt", false},
+	}
+	for _, tt := range tests {
+		if got := isFeedFile(tt.name); got != tt.want {
+			t.Errorf("isFeedFile(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseFeedBase(t *testing.T) {
+	name, day, suffix, ok := parseFeedBase("Foo_Bar_2024-05-01_3")
+	if !ok || name != "Foo_Bar" || day != "2024-05-01" || suffix != "3" {
+		t.Errorf("parseFeedBase = %q, %q, %q, %v", name, day, suffix, ok)
+	}
+	if _, _, _, ok := parseFeedBase("Player"); ok {
+		t.Error("parseFeedBase accepted a name without a date")
+	}
+}
+
+func TestClearLogsAndStats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]bool{ // name: deleted
+		"Player_2024-05-01.jsonl":       true,
+		"Player_2024-05-01.txt":         true,
+		"Player_2024-05-01.notes.txt":   true,
+		"Player_2024-05-02.json":        true,
+		"Player_stats.json":             true,
+		"Player_stats.bak.json":         false,
+		"sessions.json":                 false,
+		"namerules.json":                false,
+		"weaponnames.json":              false,
+		"events-2024-05-01.jsonl":       false,
+		"package.json":                  false,
+		"todo.txt":                      false,
+		"Player_2024-05-01_summary.pdf": false,
+	}
+	for name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clearLogsAndStats(dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, deleted := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists == deleted {
+			t.Errorf("%s: exists = %v, want %v", name, exists, !deleted)
+		}
+	}
+}
+
+func TestPruneFeeds(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, age time.Duration) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("[]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		at := time.Now().Add(-age)
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Player_2024-05-03.jsonl", time.Hour)
+	write("Player_2024-05-02_2.jsonl", 2*time.Hour)
+	write("Player_2024-05-01.jsonl", 3*time.Hour)
+	write("Player_2024-05-01.notes.txt", 3*time.Hour)
+	write("Player_2024-04-01_merged.jsonl", 4*time.Hour)
+	write("export.json", 5*time.Hour)
+
+	removed, freed, err := pruneFeeds(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 || freed != 6 {
+		t.Errorf("pruneFeeds = %d removed, %d bytes freed, want 1 and 6", removed, freed)
+	}
+	for name, want := range map[string]bool{
+		"Player_2024-05-03.jsonl":        true,
+		"Player_2024-05-02_2.jsonl":      true,
+		"Player_2024-05-01.jsonl":        false,
+		"Player_2024-05-01.notes.txt":    false,
+		"Player_2024-04-01_merged.jsonl": true,
+		"export.json":                    true,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%s: exists = %v, want %v", name, err == nil, want)
+		}
+	}
+}
+
+func TestLoadFeedFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    int // lines
+		wantErr bool
+	}{
+		{name: "json lines", file: "Pilot_2025-01-02.jsonl", content: `[{"type":"text","text":"You killed: Pilot_One\n"}]` + "\n\n" + `[{"type":"text","text":"You died\n"}]` + "\n", want: 2},
+		{name: "legacy json", file: "Pilot_2025-01-02.json", content: `[[{"type":"text","text":"You killed: Pilot_One\n"}]]`, want: 1},
+		{name: "other json", file: "settings.json", content: `{"theme":"dark"}`, wantErr: true},
+		{name: "bad json line", file: "Pilot_2025-01-02.jsonl", content: `[{"type":"text","text":"ok\n"}]` + "\nnot json\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			lines, err := loadFeedFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadFeedFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(lines) != tt.want {
+				t.Errorf("loadFeedFile() read %d lines, want %d", len(lines), tt.want)
+			}
+		})
+	}
+}
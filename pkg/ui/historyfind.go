@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Styles of text matching the history find box; the current match stands out.
+var (
+	findMatchStyle   = widget.RichTextStyle{Inline: true, ColorName: theme.ColorNamePrimary, TextStyle: fyne.TextStyle{Bold: true}}
+	findCurrentStyle = widget.RichTextStyle{Inline: true, ColorName: theme.ColorNameWarning, TextStyle: fyne.TextStyle{Bold: true, Underline: true}}
+)
+
+// highlightMatches restyles every case-insensitive occurrence of query in
+// rendered feed lines, one line per "\n" segment as renderFeedLines makes
+// them. The match numbered current gets findCurrentStyle. A link containing a
+// match becomes plain text so it can be styled, until the search is cleared.
+// It returns the new segments and the line of each match, in order.
+func highlightMatches(segments []widget.RichTextSegment, query string, current int) ([]widget.RichTextSegment, []int) {
+	if query == "" {
+		return segments, nil
+	}
+	var out []widget.RichTextSegment
+	var matchLines []int
+	line := 0
+	for _, seg := range segments {
+		var text string
+		var style widget.RichTextStyle
+		switch s := seg.(type) {
+		case *widget.TextSegment:
+			if s.Text == "\n" {
+				out = append(out, seg)
+				line++
+				continue
+			}
+			text, style = s.Text, s.Style
+		case *widget.HyperlinkSegment:
+			text, style = s.Text, widget.RichTextStyle{Inline: true}
+		default:
+			out = append(out, seg)
+			continue
+		}
+		parts := splitMatches(text, query)
+		if len(parts) == 1 {
+			out = append(out, seg)
+			continue
+		}
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			partStyle := style
+			// Odd parts are the matches
+			if i%2 == 1 {
+				partStyle = findMatchStyle
+				if len(matchLines) == current {
+					partStyle = findCurrentStyle
+				}
+				matchLines = append(matchLines, line)
+			}
+			out = append(out, &widget.TextSegment{Text: part, Style: partStyle})
+		}
+	}
+	return out, matchLines
+}
+
+// splitMatches splits text around case-insensitive occurrences of query, so
+// the parts alternate between non-matching and matching text, starting and
+// ending with non-matching text (possibly empty).
+func splitMatches(text, query string) []string {
+	lower, lowerQuery := strings.ToLower(text), strings.ToLower(query)
+	// Lower-casing can change byte lengths for a few runes; match exactly then
+	if len(lower) != len(text) || len(lowerQuery) != len(query) {
+		lower, lowerQuery = text, query
+	}
+	var parts []string
+	start := 0
+	for {
+		idx := strings.Index(lower[start:], lowerQuery)
+		if idx < 0 {
+			return append(parts, text[start:])
+		}
+		parts = append(parts, text[start:start+idx], text[start+idx:start+idx+len(query)])
+		start += idx + len(query)
+	}
+}
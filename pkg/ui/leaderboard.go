@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"game-monitor/pkg/processor"
+	"game-monitor/pkg/stats"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Choices offered by the leaderboard export dialog.
+const (
+	leaderboardByKills = "Kills"
+	leaderboardByKD    = "K/D"
+	leaderboardCSV     = "CSV"
+	leaderboardHTML    = "HTML"
+)
+
+// showLeaderboardExport asks how to rank and format the combined leaderboard
+// of every player with saved stats, then saves it where the user chooses.
+func showLeaderboardExport(parent fyne.Window) {
+	sortRadio := widget.NewRadioGroup([]string{leaderboardByKills, leaderboardByKD}, nil)
+	sortRadio.Horizontal = true
+	sortRadio.Required = true
+	sortRadio.SetSelected(leaderboardByKills)
+	formatRadio := widget.NewRadioGroup([]string{leaderboardCSV, leaderboardHTML}, nil)
+	formatRadio.Horizontal = true
+	formatRadio.Required = true
+	formatRadio.SetSelected(leaderboardCSV)
+	playersOnly := widget.NewCheck("Only count fights between players (no suicides, environment or NPCs)", nil)
+	playersOnly.SetChecked(true)
+	form := container.NewVBox(
+		widget.NewLabel("Rank by:"), sortRadio,
+		widget.NewLabel("Format:"), formatRadio,
+		playersOnly,
+	)
+	dialog.ShowCustomConfirm("Export Leaderboard", "Choose File", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		by := stats.RankByKills
+		if sortRadio.Selected == leaderboardByKD {
+			by = stats.RankByKD
+		}
+		entries := stats.Leaderboard(by, playersOnly.Checked)
+		if len(entries) == 0 {
+			dialog.ShowInformation("Export Leaderboard", "No player stats found in "+stats.Dir(), parent)
+			return
+		}
+		ext := ".csv"
+		if formatRadio.Selected == leaderboardHTML {
+			ext = ".html"
+		}
+		save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+			if err != nil || w == nil {
+				return
+			}
+			defer w.Close()
+			if ext == ".html" {
+				_, err = w.Write([]byte(renderLeaderboardHTML(entries, sortRadio.Selected)))
+			} else {
+				err = stats.WriteLeaderboardCSV(w, entries)
+			}
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("failed to write leaderboard: %w", err), parent)
+			}
+		}, parent)
+		save.SetFileName("citizenmon-leaderboard-" + time.Now().Format("2006-01-02") + ext)
+		save.Show()
+	}, parent)
+}
+
+// renderLeaderboardHTML renders ranked entries as a standalone page with a
+// table, player names linked to their citizen pages.
+func renderLeaderboardHTML(entries []stats.LeaderboardEntry, rankedBy string) string {
+	var b strings.Builder
+	b.WriteString("<html><head><meta charset='utf-8'><title>CitizenMon Leaderboard</title>" +
+		"<style>body{font-family:sans-serif;background:#1e1e1e;color:#ddd}" +
+		"table{border-collapse:collapse}th,td{padding:4px 12px;text-align:left}" +
+		"tr:nth-child(even){background:#2a2a2a}a{color:#6cb6ff}</style>" +
+		"</head><body>\n")
+	fmt.Fprintf(&b, "<h1>Leaderboard</h1>\n<p>Ranked by %s • %s</p>\n",
+		html.EscapeString(rankedBy), html.EscapeString(processor.FormatTimestamp(time.Now())))
+	b.WriteString("<table><tr><th>#</th><th>Player</th><th>Kills</th><th>Deaths</th><th>K/D</th></tr>\n")
+	for i, e := range entries {
+		fmt.Fprintf(&b, "<tr><td>%d</td><td><a href=\"%s\">%s</a></td><td>%d</td><td>%d</td><td>%.2f</td></tr>\n",
+			i+1, html.EscapeString(citizenURL(e.Player)), html.EscapeString(e.Player), e.Kills, e.Deaths, e.KD())
+	}
+	b.WriteString("</table></body></html>\n")
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"game-monitor/pkg/processor"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// What clicking a player or org link in the feed does (preference linkMode).
+const (
+	linkModeOpen = "Open in browser"
+	linkModeCopy = "Copy link"
+	linkModeOff  = "Plain text"
+)
+
+// linkMode is the current link behaviour, one of the linkMode constants.
+var linkMode = linkModeOpen
+
+// onLinkCopied, if set, is called after a link is copied in linkModeCopy,
+// e.g. to confirm it on screen.
+var onLinkCopied func(link string)
+
+// linkSegments applies linkMode to segments about to be displayed: links are
+// turned into plain text, or copy their URL instead of opening it. The
+// segments passed in are left alone, so stored and saved lines keep their
+// links whatever the mode.
+func linkSegments(segments []widget.RichTextSegment) []widget.RichTextSegment {
+	if linkMode != linkModeCopy && linkMode != linkModeOff {
+		return segments
+	}
+	out := make([]widget.RichTextSegment, len(segments))
+	style := widget.RichTextStyle{Inline: true}
+	for i, seg := range segments {
+		link, ok := seg.(*widget.HyperlinkSegment)
+		if !ok {
+			// Plain-text links take the color of the text around them
+			if text, isText := seg.(*widget.TextSegment); isText && text.Text != "\n" {
+				style = text.Style
+			}
+			out[i] = seg
+			continue
+		}
+		if linkMode == linkModeOff || link.URL == nil {
+			out[i] = &widget.TextSegment{Text: link.Text, Style: style}
+			continue
+		}
+		target := link.URL.String()
+		copied := *link
+		copied.OnTapped = func() {
+			fyne.CurrentApp().Clipboard().SetContent(target)
+			if onLinkCopied != nil {
+				onLinkCopied(target)
+			}
+		}
+		out[i] = &copied
+	}
+	return out
+}
+
+// defaultRSIBaseURL is the RSI site root, including the locale, that citizen
+// and org links are built from.
+const defaultRSIBaseURL = "https://robertsspaceindustries.com/en/"
+
+// rsiBaseURL is the configured site root (preference rsiBaseURL), always ending in "/".
+var rsiBaseURL = defaultRSIBaseURL
+
+// setRSIBaseURL validates and applies a new site root. A blank value restores
+// the default; anything that isn't an absolute http(s) URL is rejected and
+// leaves the current value in place.
+func setRSIBaseURL(raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		rsiBaseURL = defaultRSIBaseURL
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid RSI base URL: %q", raw)
+	}
+	if !strings.HasSuffix(raw, "/") {
+		raw += "/"
+	}
+	rsiBaseURL = raw
+	return nil
+}
+
+// citizenURL returns the RSI citizen page for a player handle.
+func citizenURL(name string) string {
+	return rsiBaseURL + "citizens/" + url.PathEscape(name)
+}
+
+// friendMark is shown in front of names on the friends list.
+const friendMark = "★"
+
+// markFriend prefixes display with friendMark when name is on the friends list.
+func markFriend(name, display string) string {
+	if processor.IsFriend(name) {
+		return friendMark + display
+	}
+	return display
+}
+
+// orgURL returns the RSI organization page for an org SID.
+func orgURL(tag string) string {
+	return rsiBaseURL + "orgs/" + url.PathEscape(tag)
+}
+
+// orgTagWord reports whether a feed word is an org tag such as "[TAG]" and returns the tag.
+func orgTagWord(word string) (string, bool) {
+	if !strings.HasPrefix(word, "[") || !strings.HasSuffix(word, "]") {
+		return "", false
+	}
+	_, tag := processor.StripOrgTag("x " + word)
+	return tag, tag != ""
+}
+
+// appendOrgSegments adds " [TAG]" with the tag linked to its RSI org page. No-op when tag is empty.
+func appendOrgSegments(segments []FeedSegment, tag string) []FeedSegment {
+	if tag == "" {
+		return segments
+	}
+	return append(segments,
+		FeedSegment{Type: "text", Text: " ["},
+		FeedSegment{Type: "hyperlink", Text: tag, URL: orgURL(tag)},
+		FeedSegment{Type: "text", Text: "]"},
+	)
+}
+
+// deathLineKiller returns the killer named by a "You were killed by: <name> ..." feed line, or "".
+func deathLineKiller(line string) string {
+	rest, ok := strings.CutPrefix(line, "You were killed by: ")
+	if !ok {
+		return ""
+	}
+	if i := strings.Index(rest, " using "); i >= 0 {
+		rest = rest[:i]
+	}
+	name, _ := processor.StripOrgTag(rest)
+	return name
+}
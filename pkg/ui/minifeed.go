@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// defaultMiniFeedLines is how many kill/death lines the pop-out feed keeps.
+const defaultMiniFeedLines = 10
+
+// miniFeed is a small second window showing only the latest kill and death
+// lines, for keeping on another monitor. Lines are collected while it's
+// closed too, so it opens with the recent history.
+//
+// Fyne has no always-on-top or frameless option for normal windows, so it is
+// a regular small window; pin it with the OS or window manager if needed.
+type miniFeed struct {
+	win   fyne.Window
+	rich  *widget.RichText
+	lines []feedEntry
+	limit int
+}
+
+// add records a kill or death message; other lines are ignored. Must be called on the UI thread.
+func (m *miniFeed) add(message string, at time.Time) {
+	category := classifyFeedLine(message)
+	if category != feedCategoryDeath && !isKillLine(message) {
+		return
+	}
+	text := at.Format("15:04") + "  " + message
+	m.lines = append(m.lines, feedEntry{
+		segments: []widget.RichTextSegment{&widget.TextSegment{
+			Text:  text,
+			Style: widget.RichTextStyle{ColorName: categoryColor(category)},
+		}},
+		category: category,
+		at:       at,
+	})
+	if drop := len(m.lines) - m.limit; drop > 0 {
+		m.lines = m.lines[drop:]
+	}
+	m.refresh()
+}
+
+// refresh redraws the window, if it is open.
+func (m *miniFeed) refresh() {
+	if m.win == nil {
+		return
+	}
+	var segments []widget.RichTextSegment
+	for _, line := range m.lines {
+		segments = append(segments, line.segments...)
+	}
+	if len(segments) == 0 {
+		segments = []widget.RichTextSegment{&widget.TextSegment{Text: "No kills or deaths yet."}}
+	}
+	m.rich.Segments = segments
+	m.rich.Refresh()
+}
+
+// open shows the window, or brings it forward if it is already open.
+// Closing it only hides the mirror; monitoring carries on.
+func (m *miniFeed) open(a fyne.App) {
+	if m.win != nil {
+		m.win.RequestFocus()
+		return
+	}
+	m.rich = widget.NewRichText()
+	m.rich.Wrapping = fyne.TextWrapWord
+	m.win = a.NewWindow("Citizenmon Feed")
+	m.win.SetContent(container.NewVScroll(m.rich))
+	m.win.Resize(fyne.NewSize(380, 220))
+	m.win.SetOnClosed(func() {
+		m.win = nil
+	})
+	m.refresh()
+	m.win.Show()
+}
+
+// close closes the window if it is open, so it doesn't outlive the main window.
+func (m *miniFeed) close() {
+	if m.win != nil {
+		m.win.Close()
+	}
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"game-monitor/pkg/stats"
+)
+
+// defaultNameRulesJSON holds the built-in player name rules. A namerules.json
+// in the app data directory overrides any field it sets.
+//
+//go:embed namerules.json
+var defaultNameRulesJSON []byte
+
+// nameRuleset controls which words isValidPlayerName accepts as player names.
+type nameRuleset struct {
+	MinLength     int      `json:"minLength"`
+	MaxLength     int      `json:"maxLength"`
+	ExtraChars    string   `json:"extraChars"`    // allowed besides letters, digits and "_", but not at either end
+	CommonWords   []string `json:"commonWords"`   // rejected when the whole name matches (case-insensitive)
+	ContainsWords []string `json:"containsWords"` // rejected when the name contains one (case-insensitive)
+}
+
+var activeNameRules atomic.Pointer[nameRuleset]
+
+// nameRulesPath returns the location of the user override file.
+func nameRulesPath() string {
+	return filepath.Join(stats.Dir(), "namerules.json")
+}
+
+// ReloadNameRules reloads the built-in rules and applies the user override
+// file on top. If the override can't be parsed the built-in rules stay active
+// and the error is returned.
+func ReloadNameRules() error {
+	var rules nameRuleset
+	if err := json.Unmarshal(defaultNameRulesJSON, &rules); err != nil {
+		return fmt.Errorf("built-in name rules are invalid: %w", err)
+	}
+	defaults := rules
+	data, err := os.ReadFile(nameRulesPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		activeNameRules.Store(&defaults)
+		return fmt.Errorf("failed to read name rules: %w", err)
+	}
+	if err == nil {
+		// Fields missing from the override keep their built-in values
+		if err := json.Unmarshal(data, &rules); err != nil {
+			activeNameRules.Store(&defaults)
+			return fmt.Errorf("failed to parse %s: %w", nameRulesPath(), err)
+		}
+		if rules.MinLength < 1 || rules.MaxLength < rules.MinLength {
+			activeNameRules.Store(&defaults)
+			return fmt.Errorf("invalid name length bounds %d–%d in %s", rules.MinLength, rules.MaxLength, nameRulesPath())
+		}
+	}
+	activeNameRules.Store(&rules)
+	return nil
+}
+
+// currentNameRules returns the active rules, loading them on first use.
+func currentNameRules() *nameRuleset {
+	if rules := activeNameRules.Load(); rules != nil {
+		return rules
+	}
+	_ = ReloadNameRules()
+	return activeNameRules.Load()
+}
+
+// allowsExtra reports whether r is one of the rules' extra name characters.
+func (r *nameRuleset) allowsExtra(c rune) bool {
+	return strings.ContainsRune(r.ExtraChars, c)
+}
+
+// rejects reports whether the rules exclude a lower-cased name as a common word.
+func (r *nameRuleset) rejects(lowerName string) bool {
+	for _, word := range r.CommonWords {
+		if lowerName == strings.ToLower(word) {
+			return true
+		}
+	}
+	for _, word := range r.ContainsWords {
+		if strings.Contains(lowerName, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
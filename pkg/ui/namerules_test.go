@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"game-monitor/pkg/stats"
+)
+
+// withNameRules points the data directory at a temp dir holding the given
+// namerules.json (none when empty) and reloads the rules. The built-in rules
+// are reloaded lazily once the test ends.
+func withNameRules(t *testing.T, override string) error {
+	t.Helper()
+	dir := t.TempDir()
+	if override != "" {
+		if err := os.WriteFile(filepath.Join(dir, "namerules.json"), []byte(override), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats.SetDir(dir)
+	t.Cleanup(func() {
+		stats.SetDir("")
+		activeNameRules.Store(nil)
+	})
+	return ReloadNameRules()
+}
+
+func TestReloadNameRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		wantErr  bool
+		wantMin  int
+		wantMax  int
+		wantXtra string
+	}{
+		{name: "no override", wantMin: 3, wantMax: 30, wantXtra: "-."},
+		{name: "partial override", override: `{"maxLength": 20}`, wantMin: 3, wantMax: 20, wantXtra: "-."},
+		{name: "extra chars", override: `{"extraChars": "-"}`, wantMin: 3, wantMax: 30, wantXtra: "-"},
+		{name: "invalid json", override: `{"minLength": `, wantErr: true, wantMin: 3, wantMax: 30, wantXtra: "-."},
+		{name: "zero min length", override: `{"minLength": 0}`, wantErr: true, wantMin: 3, wantMax: 30, wantXtra: "-."},
+		{name: "max below min", override: `{"minLength": 10, "maxLength": 5}`, wantErr: true, wantMin: 3, wantMax: 30, wantXtra: "-."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := withNameRules(t, tt.override)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReloadNameRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			rules := currentNameRules()
+			if rules.MinLength != tt.wantMin || rules.MaxLength != tt.wantMax || rules.ExtraChars != tt.wantXtra {
+				t.Errorf("rules = %d–%d %q, want %d–%d %q",
+					rules.MinLength, rules.MaxLength, rules.ExtraChars, tt.wantMin, tt.wantMax, tt.wantXtra)
+			}
+			if len(rules.CommonWords) == 0 || len(rules.ContainsWords) == 0 {
+				t.Errorf("word lists should keep their built-in values, got %d common and %d contains words",
+					len(rules.CommonWords), len(rules.ContainsWords))
+			}
+		})
+	}
+}
+
+func TestNameRulesOverrideValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		player   string
+		want     bool
+	}{
+		{name: "built-in accepts", player: "Pilot_One", want: true},
+		{name: "built-in common word", player: "Killed", want: false},
+		{name: "built-in contained word", player: "ServerBot", want: false},
+		{name: "built-in too short", player: "Ab", want: false},
+		{name: "lower min length", override: `{"minLength": 2}`, player: "Ab", want: true},
+		{name: "lower max length", override: `{"maxLength": 8}`, player: "Pilot_One", want: false},
+		{name: "added common word", override: `{"commonWords": ["Pilot_One"]}`, player: "pilot_one", want: false},
+		{name: "replaced common words", override: `{"commonWords": []}`, player: "Killed", want: true},
+		{name: "added contained word", override: `{"containsWords": ["BOT"]}`, player: "Gunbot_7", want: false},
+		{name: "no extra chars", override: `{"extraChars": ""}`, player: "Pilot-One", want: false},
+		{name: "invalid override keeps built-in", override: `{"minLength": 0}`, player: "Pilot_One", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = withNameRules(t, tt.override)
+			if got := isValidPlayerName(tt.player); got != tt.want {
+				t.Errorf("isValidPlayerName(%q) = %v, want %v", tt.player, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"game-monitor/pkg/processor"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// recentKillLimit is how many kills the recent kills panel shows.
+const recentKillLimit = 5
+
+// recentKill is one line of the recent kills panel.
+type recentKill struct {
+	victim string
+	weapon string // "" when the log didn't name one
+	at     time.Time
+}
+
+// recentKills is the Feed tab panel listing the player's latest kills,
+// newest first, so they stay in view however busy the feed gets.
+type recentKills struct {
+	kills []recentKill
+	list  *fyne.Container
+}
+
+func newRecentKills() *recentKills {
+	r := &recentKills{list: container.NewVBox()}
+	r.refresh()
+	return r
+}
+
+// object returns the panel, with its title, for placing in a layout.
+func (r *recentKills) object() fyne.CanvasObject {
+	return container.NewVBox(
+		widget.NewLabelWithStyle("Recent Kills", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		r.list,
+	)
+}
+
+// add records a stored feed entry if it's one of the player's kills; deaths,
+// team kills, vehicles and everything else are ignored. Must be called on the
+// UI thread.
+func (r *recentKills) add(entry feedEntry) {
+	if entry.category != feedCategoryKill {
+		return
+	}
+	victim, weapon, ok := parseKillMessage(entry.plainText())
+	if !ok {
+		return
+	}
+	at := entry.at
+	if at.IsZero() {
+		at = time.Now()
+	}
+	r.kills = append(r.kills, recentKill{victim: victim, weapon: weapon, at: at})
+	if drop := len(r.kills) - recentKillLimit; drop > 0 {
+		r.kills = r.kills[drop:]
+	}
+	r.refresh()
+}
+
+// clear empties the panel, e.g. when the session stats are reset.
+func (r *recentKills) clear() {
+	r.kills = nil
+	r.refresh()
+}
+
+// refresh rebuilds the panel from kills, newest first.
+func (r *recentKills) refresh() {
+	r.list.RemoveAll()
+	if len(r.kills) == 0 {
+		r.list.Add(widget.NewLabel("No kills yet."))
+		return
+	}
+	for i := len(r.kills) - 1; i >= 0; i-- {
+		kill := r.kills[i]
+		detail := kill.at.In(processor.TimestampLocation()).Format("15:04")
+		if kill.weapon != "" {
+			detail = kill.weapon + " • " + detail
+		}
+		r.list.Add(container.NewVBox(
+			widget.NewLabelWithStyle(kill.victim, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewLabel(detail),
+		))
+	}
+}
+
+// parseKillMessage splits a "You killed: <victim> using <weapon>" feed line,
+// with or without its timestamp, into victim and weapon.
+func parseKillMessage(text string) (victim, weapon string, ok bool) {
+	const marker = "You killed: "
+	idx := strings.Index(text, marker)
+	if idx < 0 {
+		return "", "", false
+	}
+	victim, weapon, _ = strings.Cut(text[idx+len(marker):], " using ")
+	victim = strings.TrimSpace(victim)
+	return victim, strings.TrimSpace(weapon), victim != ""
+}
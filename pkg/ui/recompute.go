@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"game-monitor/pkg/fsutil"
+	"game-monitor/pkg/processor"
+	"game-monitor/pkg/stats"
+)
+
+// playerFeedFiles returns the saved feeds in dir that belong to player, named
+// Player_YYYY-MM-DD with an optional _N suffix, sorted by name so the days
+// come in order.
+func playerFeedFiles(dir, player string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	owner := fsutil.SanitizeFilename(player)
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		// Merged and other renamed logs repeat lines from other feeds
+		if entry.IsDir() || !isSessionFeed(name) {
+			continue
+		}
+		if feedPlayer, _, _, _ := parseFeedBase(feedBaseName(name)); feedPlayer != owner {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// feedTally re-counts a player's stats from saved feed lines, fed in order.
+//
+// Feeds keep only the rendered text, so the tally is limited to what the
+// lines name: kills, team kills, incaps and deaths with the other player.
+// Damage types and appearances aren't in the feed and aren't counted. NPCs
+// are counted under the display name the feed showed for them, and deaths
+// folded into a crash summary without a killer count as unknownKiller.
+// Lines naming someone on the current ignore list are skipped, as the
+// processor would skip them now.
+type feedTally struct {
+	stats       stats.Stats
+	incapWindow time.Duration // as Processor.IncapKillWindow
+	streak      int
+	lastIncaps  map[string]time.Time
+}
+
+func newFeedTally(incapWindow time.Duration) *feedTally {
+	return &feedTally{stats: stats.New(), incapWindow: incapWindow, lastIncaps: make(map[string]time.Time)}
+}
+
+// add counts one saved feed line.
+func (t *feedTally) add(line []FeedSegment) {
+	event, ok := parseFeedEvent(feedLineText(line))
+	if !ok || processor.IsIgnored(event.name) {
+		return
+	}
+	at, hasTime := feedLineTime(line)
+	switch event.kind {
+	case feedEventTeamKill:
+		t.stats.FriendlyKills[event.name]++
+	case feedEventKill:
+		victim := event.name
+		t.stats.Kills[victim]++
+		if hasTime {
+			t.stats.AddKillHour(at)
+		}
+		t.streak++
+		t.stats.BestStreak = max(t.stats.BestStreak, t.streak)
+		// Like the processor, an incap finished off shortly after counts only as the kill
+		if incappedAt, ok := t.lastIncaps[victim]; ok && hasTime {
+			delete(t.lastIncaps, victim)
+			if gap := at.Sub(incappedAt); gap >= 0 && gap <= t.incapWindow && t.stats.Incaps[victim] > 0 {
+				t.stats.Incaps[victim]--
+				if t.stats.Incaps[victim] == 0 {
+					delete(t.stats.Incaps, victim)
+				}
+			}
+		}
+	case feedEventIncap:
+		t.stats.Incaps[event.name]++
+		if hasTime && t.incapWindow > 0 {
+			t.lastIncaps[event.name] = at
+		}
+	case feedEventDeath:
+		t.stats.Deaths[event.name]++
+		t.streak = 0
+	}
+}
+
+// recomputeStats re-tallies player's all-time stats from every saved feed of
+// theirs in dir, oldest first. Appearances and damage types can't be rebuilt
+// from a feed, so they're carried over from current. It returns the stats and
+// the number of feeds read; unreadable feeds are skipped and reported in the
+// error alongside the result.
+func recomputeStats(dir, player string, current stats.Stats, incapWindow time.Duration) (stats.Stats, int, error) {
+	paths, err := playerFeedFiles(dir, player)
+	if err != nil {
+		return stats.Stats{}, 0, err
+	}
+	tally := newFeedTally(incapWindow)
+	read := 0
+	var skipped []string
+	for _, path := range paths {
+		lines, err := loadFeedFile(path)
+		if err != nil {
+			skipped = append(skipped, filepath.Base(path))
+			continue
+		}
+		for _, line := range lines {
+			tally.add(line)
+		}
+		read++
+	}
+	result := tally.stats
+	if current.Appearances != nil {
+		result.Appearances = current.Appearances
+	}
+	if current.DamageTypes != nil {
+		result.DamageTypes = current.DamageTypes
+	}
+	if len(skipped) > 0 {
+		return result, read, fmt.Errorf("skipped unreadable feeds: %s", strings.Join(skipped, ", "))
+	}
+	return result, read, nil
+}
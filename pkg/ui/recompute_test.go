@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"game-monitor/pkg/processor"
+	"game-monitor/pkg/stats"
+)
+
+// feedLine returns a saved feed line reading text at the given second past 10:00.
+func feedLine(sec int, text string) []FeedSegment {
+	at := time.Date(2025, 1, 2, 10, 0, sec, 0, time.UTC)
+	return []FeedSegment{{Type: "text", Text: processor.FormatTimestamp(at) + " " + text + "\n"}}
+}
+
+func TestFeedTally(t *testing.T) {
+	tests := []struct {
+		name       string
+		window     time.Duration
+		lines      [][]FeedSegment
+		wantKills  map[string]int
+		wantIncaps map[string]int
+		wantDeaths map[string]int
+		wantBest   int
+	}{
+		{
+			name:   "incap finished off inside the window",
+			window: 30 * time.Second,
+			lines: [][]FeedSegment{
+				feedLine(0, "You incapacitated: Pilot_1"),
+				feedLine(10, "You killed: Pilot_1 using Gallant Rifle"),
+			},
+			wantKills:  map[string]int{"Pilot_1": 1},
+			wantIncaps: map[string]int{},
+			wantDeaths: map[string]int{},
+			wantBest:   1,
+		},
+		{
+			name:   "incap finished off too late",
+			window: 30 * time.Second,
+			lines: [][]FeedSegment{
+				feedLine(0, "You incapacitated: Pilot_1"),
+				feedLine(45, "You killed: Pilot_1"),
+			},
+			wantKills:  map[string]int{"Pilot_1": 1},
+			wantIncaps: map[string]int{"Pilot_1": 1},
+			wantDeaths: map[string]int{},
+			wantBest:   1,
+		},
+		{
+			name:   "incaps kept with the window off",
+			window: 0,
+			lines: [][]FeedSegment{
+				feedLine(0, "You incapacitated: Pilot_1"),
+				feedLine(5, "You killed: Pilot_1"),
+			},
+			wantKills:  map[string]int{"Pilot_1": 1},
+			wantIncaps: map[string]int{"Pilot_1": 1},
+			wantDeaths: map[string]int{},
+			wantBest:   1,
+		},
+		{
+			name: "crash deaths",
+			lines: [][]FeedSegment{
+				feedLine(0, "You killed: Pilot_1"),
+				feedLine(1, "You killed: Pilot_2"),
+				feedLine(5, "Mission Event: Me crashed their Anvil Arrow and died (killed by Pilot_3 using Laser Repeater)"),
+				feedLine(6, "You killed: Pilot_4"),
+				feedLine(9, "Mission Event: Me died in a crash"),
+			},
+			wantKills:  map[string]int{"Pilot_1": 1, "Pilot_2": 1, "Pilot_4": 1},
+			wantIncaps: map[string]int{},
+			wantDeaths: map[string]int{"Pilot_3": 1, unknownKiller: 1},
+			wantBest:   2,
+		},
+		{
+			name: "other lines are skipped",
+			lines: [][]FeedSegment{
+				feedLine(0, "Monitoring: Game.log"),
+				feedLine(1, "Player appeared: Pilot_1"),
+			},
+			wantKills:  map[string]int{},
+			wantIncaps: map[string]int{},
+			wantDeaths: map[string]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := newFeedTally(tt.window)
+			for _, line := range tt.lines {
+				tally.add(line)
+			}
+			if !reflect.DeepEqual(tally.stats.Kills, tt.wantKills) {
+				t.Errorf("kills = %v, want %v", tally.stats.Kills, tt.wantKills)
+			}
+			if !reflect.DeepEqual(tally.stats.Incaps, tt.wantIncaps) {
+				t.Errorf("incaps = %v, want %v", tally.stats.Incaps, tt.wantIncaps)
+			}
+			if !reflect.DeepEqual(tally.stats.Deaths, tt.wantDeaths) {
+				t.Errorf("deaths = %v, want %v", tally.stats.Deaths, tt.wantDeaths)
+			}
+			if tally.stats.BestStreak != tt.wantBest {
+				t.Errorf("best streak = %d, want %d", tally.stats.BestStreak, tt.wantBest)
+			}
+		})
+	}
+}
+
+func TestRecomputeStats(t *testing.T) {
+	dir := t.TempDir()
+	feeds := map[string][][]FeedSegment{
+		"Me_2025-01-01.jsonl":        {feedLine(0, "You killed: Pilot_1"), feedLine(1, "You killed: Ignored_Pilot")},
+		"Me_2025-01-02.jsonl":        {feedLine(0, "You were killed by: Pilot_1 using Gallant Rifle")},
+		"Me_2025-01-02_merged.jsonl": {feedLine(0, "You killed: Pilot_1")},
+		"Other_2025-01-02.jsonl":     {feedLine(0, "You killed: Pilot_2")},
+	}
+	for name, lines := range feeds {
+		if err := writeFeedFile(filepath.Join(dir, name), lines); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Me_2025-01-03.jsonl"), []byte("not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := processor.SetIgnoreList("ignored_"); err != nil {
+		t.Fatal(err)
+	}
+	defer processor.SetIgnoreList("")
+
+	current := stats.New()
+	current.Appearances["Pilot_9"] = 4
+	got, read, err := recomputeStats(dir, "Me", current, 30*time.Second)
+	if err == nil {
+		t.Error("the unreadable feed wasn't reported")
+	}
+	if read != 2 {
+		t.Errorf("read %d feeds, want 2", read)
+	}
+	if want := map[string]int{"Pilot_1": 1}; !reflect.DeepEqual(got.Kills, want) {
+		t.Errorf("kills = %v, want %v", got.Kills, want)
+	}
+	if want := map[string]int{"Pilot_1": 1}; !reflect.DeepEqual(got.Deaths, want) {
+		t.Errorf("deaths = %v, want %v", got.Deaths, want)
+	}
+	if got.Appearances["Pilot_9"] != 4 {
+		t.Errorf("appearances weren't carried over: %v", got.Appearances)
+	}
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"game-monitor/pkg/processor"
+)
+
+// samplePlayer is the made-up player the sample log is played as, so its
+// counts never mix with a real player's.
+const samplePlayer = "Sample_Citizen"
+
+// sampleTag marks every feed line produced from the sample log.
+const sampleTag = "[Sample]"
+
+// sampleLogLines returns a short made-up game log for samplePlayer, timed from
+// start: kills of a player and an NPC, a ship kill with its pilot, an incap,
+// a death to another player and a fall.
+func sampleLogLines(start time.Time) []string {
+	events := []struct {
+		after time.Duration
+		text  string
+	}{
+		{0, "[Notice] <Actor Death> CActor::Kill: 'Sample_Outlaw' [201] in zone 'Stanton2_Orison' killed by '%[1]s' [200] using 'behr_rifle_ballistic_01_4501' [Class behr_rifle_ballistic_01] with damage type 'Bullet' from direction x: 0, y: 0, z: 0 [Team_ActorTech][Actor]"},
+		{20 * time.Second, "[Notice] <Actor Death> CActor::Kill: 'PU_Human_Enemy_GroundCombat_NPC_Pirate_Sniper_4502' [202] in zone 'Stanton2_Orison' killed by '%[1]s' [200] using 'behr_rifle_ballistic_01_4501' [Class behr_rifle_ballistic_01] with damage type 'Bullet' from direction x: 0, y: 0, z: 0 [Team_ActorTech][Actor]"},
+		{50 * time.Second, "[Notice] <Vehicle Destruction> CVehicle::OnAdvanceDestroyLevel: Vehicle 'ANVL_Hornet_F7C_4503' [203] in zone 'Stanton2' [pos x: 0, y: 0, z: 0 vel x: 0, y: 0, z: 0] driven by 'Sample_Pilot' [204] advanced from destroy level 0 to 2 caused by '%[1]s' [200] with 'Combat' [Team_VehicleFeatures][Vehicle]"},
+		{51 * time.Second, "[Notice] <Actor Death> CActor::Kill: 'Sample_Pilot' [204] in zone 'ANVL_Hornet_F7C_4503' killed by '%[1]s' [200] using 'AEGS_Gladius_S3_Cannon_4505' [Class AEGS_Gladius_S3_Cannon] with damage type 'VehicleDestruction' from direction x: 0, y: 0, z: 0 [Team_ActorTech][Actor]"},
+		{80 * time.Second, "[Notice] <Logged an incap> nickname: Sample_Medic incapacitated by %[1]s [Team_ActorTech][Actor]"},
+		{110 * time.Second, "[Notice] <Actor Death> CActor::Kill: '%[1]s' [200] in zone 'Stanton2_Orison' killed by 'Sample_Outlaw' [201] using 'klwe_pistol_energy_01_4506' [Class klwe_pistol_energy_01] with damage type 'Energy' from direction x: 0, y: 0, z: 0 [Team_ActorTech][Actor]"},
+		{150 * time.Second, "[Notice] <Actor Death> CActor::Kill: '%[1]s' [200] in zone 'Stanton2_Orison' killed by '%[1]s' [200] using 'unknown' [Class unknown] with damage type 'Fall' from direction x: 0, y: 0, z: 0 [Team_ActorTech][Actor]"},
+	}
+	lines := make([]string, 0, len(events))
+	for _, e := range events {
+		ts := start.Add(e.after).UTC().Format("2006-01-02T15:04:05.000Z")
+		lines = append(lines, "<"+ts+"> "+fmt.Sprintf(e.text, samplePlayer))
+	}
+	return lines
+}
+
+// playSample runs the sample log, timed to end about now, through a processor
+// that publishes its counts as samplePlayer's session but never saves them.
+// Each feed line is passed to emit with its log time.
+func playSample(emit func(line string, at time.Time)) {
+	proc := processor.New(nil, nil)
+	proc.PlayerName = samplePlayer
+	proc.SessionOnly = true
+	proc.AppendOutput = func(line string, logTime ...time.Time) {
+		if line == "" || strings.HasPrefix(line, "Player appeared:") {
+			return
+		}
+		at := time.Now()
+		if len(logTime) > 0 && !logTime[0].IsZero() {
+			at = logTime[0]
+		}
+		emit(line, at)
+	}
+	for _, line := range sampleLogLines(time.Now().Add(-3 * time.Minute)) {
+		proc.ProcessLogLine(line)
+	}
+	proc.FlushPending()
+}
+
+// playSample shows the sample log in the feed, each line tagged with
+// sampleTag. The lines are kept out of saved feeds, so they never reach a
+// real player's feed file or the stats recomputed from it.
+func (a *logHandlerAdapter) playSample() {
+	playSample(func(line string, at time.Time) {
+		a.appendFeedLine(processor.FormatTimestamp(at)+" "+sampleTag+" "+line, "", true)
+	})
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+
+	"game-monitor/pkg/processor"
+	"game-monitor/pkg/stats"
+)
+
+func TestSampleLinesNeverSaved(t *testing.T) {
+	test.NewTempApp(t)
+	stats.SetDir(t.TempDir())
+	defer stats.SetDir("")
+
+	h := newFeedAdapter(defaultFeedLineLimit)
+	h.proc = processor.New(nil, nil)
+	h.AppendOutput("10:00:00 You killed: Pilot_One using Gallant")
+	saved := h.stored
+	h.playSample()
+	h.AppendOutput("10:05:00 You were killed by: Pilot_Two using Gallant")
+
+	sampleLines := 0
+	for _, entry := range h.allSegments {
+		if entry.sample {
+			sampleLines++
+			if !strings.Contains(entry.plainText(), sampleTag) {
+				t.Errorf("sample line %q isn't tagged", entry.plainText())
+			}
+		}
+	}
+	if sampleLines == 0 {
+		t.Fatal("the sample added no lines to the feed")
+	}
+
+	tests := []struct {
+		name  string
+		save  func(path string) error
+		lines int
+	}{
+		{"whole feed", func(path string) error { return writeFeedFile(path, h.savedLines()) }, 2},
+		{"appended lines", func(path string) error { return appendFeedFile(path, h.linesSince(saved)) }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Pilot_2025-01-02"+feedExt)
+			if err := tt.save(path); err != nil {
+				t.Fatal(err)
+			}
+			lines, err := loadFeedFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lines) != tt.lines {
+				t.Errorf("feed file has %d lines, want %d", len(lines), tt.lines)
+			}
+			for _, line := range lines {
+				text := feedLineText(line)
+				if strings.Contains(text, sampleTag) || strings.Contains(text, "Sample_") {
+					t.Errorf("sample line %q reached the feed file", text)
+				}
+			}
+			// Nothing in the file can count towards a player's stats
+			recomputed := newFeedTally(0)
+			for _, line := range lines {
+				recomputed.add(line)
+			}
+			for name := range recomputed.stats.Kills {
+				if strings.HasPrefix(name, "Sample_") {
+					t.Errorf("recomputing the feed counts a kill of %s", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// shortcutHelp lists the keyboard shortcuts shown by showShortcutHelp.
+// Ctrl is Cmd on macOS.
+const shortcutHelp = `| Keys | Action |
+|---|---|
+| Ctrl+1 … Ctrl+4 | Feed, Statistics, Config, History tab |
+| Ctrl+F | Search saved logs (History tab) |
+| Ctrl+L | Toggle raw log lines |
+| F1 | This help |`
+
+// addShortcut registers a Ctrl (Cmd on macOS) + key shortcut on the window.
+func addShortcut(w fyne.Window, key fyne.KeyName, action func()) {
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { action() })
+}
+
+// showShortcutHelp shows the keyboard shortcut table.
+func showShortcutHelp(w fyne.Window) {
+	dialog.ShowCustom("Keyboard Shortcuts", "Close",
+		container.NewPadded(widget.NewRichTextFromMarkdown(shortcutHelp)), w)
+}
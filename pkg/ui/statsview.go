@@ -1,6 +1,14 @@
 package ui
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"game-monitor/pkg/processor"
+	"game-monitor/pkg/stats"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
@@ -22,3 +30,117 @@ func NewStatsView() *StatsView {
 func (s *StatsView) Update(data string) {
 	// Implementation for updating stats view
 }
+
+// formatDamageBreakdown renders damage-type counts as "Type count (pct%)", most frequent first.
+func formatDamageBreakdown(damageTypes map[string]int) string {
+	type entry struct {
+		name  string
+		count int
+	}
+	var entries []entry
+	total := 0
+	for name, count := range damageTypes {
+		if count <= 0 {
+			continue
+		}
+		entries = append(entries, entry{name, count})
+		total += count
+	}
+	if total == 0 {
+		return "No deaths recorded yet"
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].name < entries[j].name
+	})
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		parts = append(parts, fmt.Sprintf("%s %d (%d%%)", e.name, e.count, e.count*100/total))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// formatDuration renders a duration as "Xh Ym".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// formatTeamKills summarizes kills of friends: the total and the most-killed friend.
+func formatTeamKills(friendlyKills map[string]int) string {
+	total, top, topCount := 0, "", 0
+	for name, count := range friendlyKills {
+		total += count
+		if count > topCount || (count == topCount && name < top) {
+			top, topCount = name, count
+		}
+	}
+	if total == 0 {
+		return "No team kills"
+	}
+	return fmt.Sprintf("%d team kills (most: %s, %d)", total, top, topCount)
+}
+
+// formatTabBadge renders the session totals appended to tab titles, " (K:12 D:3)".
+func formatTabBadge(kills, deaths int) string {
+	return fmt.Sprintf(" (K:%d D:%d)", kills, deaths)
+}
+
+// formatKillRate renders "X kills over Yh Zm (N/hr)".
+func formatKillRate(kills int, d time.Duration) string {
+	return fmt.Sprintf("%d kills over %s (%.1f/hr)", kills, formatDuration(d), stats.KillsPerHour(kills, d))
+}
+
+// formatSessionRecord renders a past session as a single list line.
+func formatSessionRecord(s stats.Session) string {
+	kd := float64(s.Kills)
+	if s.Deaths > 0 {
+		kd = float64(s.Kills) / float64(s.Deaths)
+	}
+	return fmt.Sprintf("%s • %s • %s • K %d / D %d (K/D %.2f)",
+		processor.FormatTimestamp(s.Start), s.Player, formatDuration(s.Duration()), s.Kills, s.Deaths, kd)
+}
+
+// leaderboardCounts returns the counts to rank in a kill or death list. With
+// includeEnvironment off, the environmental bucket and any collision, crash or
+// fall recorded under its own name before it existed are left out.
+func leaderboardCounts(counts map[string]int, includeEnvironment bool) map[string]int {
+	if includeEnvironment {
+		return counts
+	}
+	filtered := make(map[string]int, len(counts))
+	for name, count := range counts {
+		if !strings.EqualFold(name, processor.EnvironmentKiller) && !processor.EnvironmentalCause(name) {
+			filtered[name] = count
+		}
+	}
+	return filtered
+}
+
+// Sort modes offered for the kill and death lists on the Statistics tab.
+const (
+	statsSortCount    = "By count"
+	statsSortNameAsc  = "Name A–Z"
+	statsSortNameDesc = "Name Z–A"
+)
+
+var statsSortModes = []string{statsSortCount, statsSortNameAsc, statsSortNameDesc}
+
+// sortStatEntries orders a stats list in place by the given sort mode.
+// Unknown modes fall back to count descending.
+func sortStatEntries(entries []stats.NameCount, mode string) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		switch mode {
+		case statsSortNameAsc:
+			return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+		case statsSortNameDesc:
+			return strings.ToLower(entries[i].Name) > strings.ToLower(entries[j].Name)
+		}
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
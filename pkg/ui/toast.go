@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// toastDuration is how long a banner stays up before it hides itself.
+const toastDuration = 4 * time.Second
+
+// toastBanner is a transient message shown over the top of the window. It
+// doesn't take focus or block input outside its own area.
+type toastBanner struct {
+	overlay *fyne.Container // stack this over the window content
+	banner  *fyne.Container
+	label   *widget.Label
+	shown   int // counts Show calls so an older timer doesn't hide a newer message
+}
+
+// newToastBanner creates a hidden banner.
+func newToastBanner() *toastBanner {
+	t := &toastBanner{label: widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})}
+	background := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+	background.CornerRadius = theme.InputRadiusSize()
+	background.StrokeColor = color.Transparent
+	t.banner = container.NewStack(background, container.NewPadded(t.label))
+	t.banner.Hide()
+	t.overlay = container.NewVBox(container.NewCenter(t.banner))
+	return t
+}
+
+// Show displays msg and hides it again after toastDuration. Must be called on the UI thread.
+func (t *toastBanner) Show(msg string) {
+	t.shown++
+	id := t.shown
+	t.label.SetText(msg)
+	t.banner.Show()
+	time.AfterFunc(toastDuration, func() {
+		fyne.Do(func() {
+			if t.shown == id {
+				t.banner.Hide()
+			}
+		})
+	})
+}
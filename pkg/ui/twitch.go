@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"strings"
+
+	"game-monitor/pkg/processor"
+)
+
+// defaultTwitchKillMessage is the Twitch chat message posted for a kill.
+const defaultTwitchKillMessage = "{player} killed {victim} with {weapon}"
+
+// twitchKillMessage fills in a Twitch kill message template, replacing
+// {player}, {victim} and {weapon}. A kill the log names no weapon for reads
+// "unknown weapon".
+func twitchKillMessage(template string, event processor.KillEvent) string {
+	weapon := "unknown weapon"
+	if event.Weapon != "" {
+		weapon = processor.FriendlyWeaponName(event.Weapon)
+	}
+	return strings.NewReplacer(
+		"{player}", event.Killer,
+		"{victim}", event.Victim,
+		"{weapon}", weapon,
+	).Replace(template)
+}
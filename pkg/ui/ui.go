@@ -1,1503 +1,3396 @@
-package ui
-
-import (
-	"encoding/json"
-	"fmt"
-	"net/url"
-	"os"
-	"path/filepath"
-	"regexp"
-	"sort"
-	"strings"
-	"time"
-
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/widget"
-
-	"game-monitor/pkg/processor"
-	"game-monitor/pkg/stats"
-	"game-monitor/pkg/watcher"
-)
-
-// Add global variable to control raw log display
-var ShowRawLogLines = false
-
-// Run sets up and runs the UI with Feed, Statistics, and Config tabs.
-func Run() {
-	a := app.NewWithID("io.yourname.gamemonitor")
-	window := a.NewWindow("Citizen Killstalker")
-	iconBytes, err := os.ReadFile("icon.png")
-	if err == nil {
-		window.SetIcon(fyne.NewStaticResource("icon.png", iconBytes))
-	}
-
-	prefs := a.Preferences()
-	saved := prefs.String("logPath")
-
-	// Helper to get feed save directory
-	getFeedDir := func() string {
-		dir := filepath.Join(os.Getenv("APPDATA"), "citizenmon", "feeds")
-		os.MkdirAll(dir, 0755)
-		return dir
-	}
-	// UI components
-	playerLabel := widget.NewLabel("<none>")
-	outputRich := widget.NewRichText()
-	// Remove truncation to prevent text from being cut off
-	// outputRich.Truncation = fyne.TextTruncateClip
-	// Enable text wrapping for outputRich to prevent long lines from breaking
-	outputRich.Wrapping = fyne.TextWrapWord
-
-	// Separate RichText for history
-	historyRich := widget.NewRichText()
-	historyRich.Wrapping = fyne.TextWrapWord
-	// Placeholders for all-time stats lists
-	allTimeKills := []struct {
-		Name  string
-		Count int
-	}{}
-	allTimeDeaths := []struct {
-		Name  string
-		Count int
-	}{}
-	
-	// Placeholders for current session stats lists
-	sessionKills := []struct {
-		Name  string
-		Count int
-	}{}
-	sessionDeaths := []struct {
-		Name  string
-		Count int
-	}{}	// All-time stats lists with enhanced styling
-	allTimeKillList := widget.NewList(
-		func() int { return len(allTimeKills) },
-		func() fyne.CanvasObject {
-			return widget.NewHyperlink("", nil)
-		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i < len(allTimeKills) {
-				e := allTimeKills[i]
-				
-				// Add medal emoji for top ranks
-				medal := ""
-				switch i {
-				case 0: medal = "🥇 "
-				case 1: medal = "🥈 "
-				case 2: medal = "🥉 "
-				default: medal = "🎯 "
-				}
-				
-				url := fmt.Sprintf("https://robertsspaceindustries.com/en/citizens/%s", e.Name)
-				o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d kills)", medal, i+1, e.Name, e.Count))
-				o.(*widget.Hyperlink).SetURLFromString(url)
-			}		},
-	)
-	allTimeDeathList := widget.NewList(
-		func() int { return len(allTimeDeaths) },
-		func() fyne.CanvasObject {
-			return widget.NewHyperlink("", nil)
-		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i < len(allTimeDeaths) {
-				e := allTimeDeaths[i]
-				
-				// Add skull emoji for top killers
-				skull := ""
-				switch i {
-				case 0: skull = "💀 "
-				case 1: skull = "☠️ "
-				case 2: skull = "⚰️ "
-				default: skull = "🔴 "
-				}
-				
-				if e.Name == "Suicide" {
-					o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", skull, i+1, e.Name, e.Count))
-					o.(*widget.Hyperlink).SetURL(nil)
-				} else {
-					url := fmt.Sprintf("https://robertsspaceindustries.com/en/citizens/%s", e.Name)
-					o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", skull, i+1, e.Name, e.Count))
-					o.(*widget.Hyperlink).SetURLFromString(url)
-				}
-			}
-		},
-	)
-	// Session stats lists with enhanced styling
-	sessionKillList := widget.NewList(
-		func() int { return len(sessionKills) },
-		func() fyne.CanvasObject {
-			return widget.NewHyperlink("", nil)
-		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i < len(sessionKills) {
-				e := sessionKills[i]
-				
-				// Add lightning emoji for session stats
-				lightning := ""
-				switch i {
-				case 0: lightning = "⚡ "
-				case 1: lightning = "🔥 "
-				case 2: lightning = "💥 "
-				default: lightning = "🎯 "
-				}
-				
-				url := fmt.Sprintf("https://robertsspaceindustries.com/en/citizens/%s", e.Name)
-				o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d kills)", lightning, i+1, e.Name, e.Count))
-				o.(*widget.Hyperlink).SetURLFromString(url)
-			}		},
-	)
-	sessionDeathList := widget.NewList(
-		func() int { return len(sessionDeaths) },
-		func() fyne.CanvasObject {
-			return widget.NewHyperlink("", nil)
-		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i < len(sessionDeaths) {
-				e := sessionDeaths[i]
-				
-				// Add warning emoji for session deaths
-				warning := ""
-				switch i {
-				case 0: warning = "⚠️ "
-				case 1: warning = "🚨 "
-				case 2: warning = "💀 "
-				default: warning = "🔴 "
-				}
-				
-				if e.Name == "Suicide" {
-					o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", warning, i+1, e.Name, e.Count))
-					o.(*widget.Hyperlink).SetURL(nil)
-				} else {
-					url := fmt.Sprintf("https://robertsspaceindustries.com/en/citizens/%s", e.Name)
-					o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", warning, i+1, e.Name, e.Count))
-					o.(*widget.Hyperlink).SetURLFromString(url)
-				}
-			}
-		},
-	)
-	updateStats := func(playerName string) {
-		fyne.Do(func() {
-			// Load all-time stats
-			allTimeStatsData := stats.Load(playerName)
-			allTimeKills = allTimeKills[:0]
-			for n, c := range allTimeStatsData.Kills {
-				allTimeKills = append(allTimeKills, struct {
-					Name  string
-					Count int
-				}{n, c})
-			}
-			sort.Slice(allTimeKills, func(i, j int) bool { return allTimeKills[i].Count > allTimeKills[j].Count })
-			if len(allTimeKills) > 10 {
-				allTimeKills = allTimeKills[:10]
-			}
-			allTimeKillList.Refresh()
-			
-			allTimeDeaths = allTimeDeaths[:0]
-			for n, c := range allTimeStatsData.Deaths {
-				allTimeDeaths = append(allTimeDeaths, struct {
-					Name  string
-					Count int
-				}{n, c})
-			}
-			sort.Slice(allTimeDeaths, func(i, j int) bool { return allTimeDeaths[i].Count > allTimeDeaths[j].Count })
-			if len(allTimeDeaths) > 10 {
-				allTimeDeaths = allTimeDeaths[:10]
-			}
-			allTimeDeathList.Refresh()
-			
-			// Load current session stats
-			sessionStatsData := stats.GetCurrentSession(playerName)
-			sessionKills = sessionKills[:0]
-			for n, c := range sessionStatsData.Kills {
-				sessionKills = append(sessionKills, struct {
-					Name  string
-					Count int
-				}{n, c})
-			}
-			sort.Slice(sessionKills, func(i, j int) bool { return sessionKills[i].Count > sessionKills[j].Count })
-			if len(sessionKills) > 10 {
-				sessionKills = sessionKills[:10]
-			}
-			sessionKillList.Refresh()
-			
-			sessionDeaths = sessionDeaths[:0]
-			for n, c := range sessionStatsData.Deaths {
-				sessionDeaths = append(sessionDeaths, struct {
-					Name  string
-					Count int
-				}{n, c})
-			}
-			sort.Slice(sessionDeaths, func(i, j int) bool { return sessionDeaths[i].Count > sessionDeaths[j].Count })
-			if len(sessionDeaths) > 10 {
-				sessionDeaths = sessionDeaths[:10]
-			}
-			sessionDeathList.Refresh()
-		})
-	}// core and adapter
-	core := processor.New(nil, playerLabel)
-	h := &logHandlerAdapter{proc: core, outputRich: outputRich, window: window, allSegments: make([]struct {
-		segments   []widget.RichTextSegment
-		rawLogLine string
-	}, 0)}
-	h.onStatsUpdate = updateStats
-	core.AppendOutput = func(line string, logTime ...time.Time) {
-		// Update player label when player name is detected
-		if core.PlayerName != "" && playerLabel != nil {
-			fyne.Do(func() {
-				playerLabel.SetText(core.PlayerName)
-			})
-		}
-
-		// Prepend the local timestamp to the log line (convert UTC to local)
-		if len(logTime) > 0 {
-			localTime := logTime[0].Local()
-			line = localTime.Format("2006-01-02 15:04:05") + " " + line
-		}
-		h.AppendOutputWithRaw(line, core.LastRawLogLine)
-	}
-
-	// Config tab
-	logEntry := widget.NewEntry()
-	logEntry.SetPlaceHolder(`Path to your \\Roberts Space Industries\\StarCitizen\\LIVE\\game.log file`)
-	if saved != "" {
-		logEntry.SetText(saved)
-	}
-	browseBtn := widget.NewButton("Browse…", func() {
-		dialog.ShowFileOpen(func(uri fyne.URIReadCloser, err error) {
-			if uri != nil && err == nil {
-				logEntry.SetText(uri.URI().Path())
-			}
-		}, window)
-	})
-	startBtn := widget.NewButton("Start Monitor", func() {
-		path := logEntry.Text
-		if _, err := os.Stat(path); err != nil {
-			dialog.ShowError(fmt.Errorf("log file not found: %s", path), window)
-			return
-		}
-		prefs.SetString("logPath", path)
-		core.AppendOutput("Monitoring: " + path)
-		go watcher.WatchLogFile(path, h)
-	})
-
-	clearLogsBtn := widget.NewButton("Clear All Old Logs", func() {
-		dialog.ShowConfirm("Delete All Logs?", "Are you sure you want to delete all saved logs and statistics? This cannot be undone.", func(confirm bool) {
-			if !confirm {
-				return
-			}
-			feedDir := getFeedDir()
-			entries, err := os.ReadDir(feedDir)
-			if err == nil {
-				for _, entry := range entries {
-					if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".json") || strings.HasSuffix(entry.Name(), ".txt")) {
-						os.Remove(filepath.Join(feedDir, entry.Name()))
-					}
-				}
-			}
-			// Also clear all stats files in the same directory
-			statEntries, err := os.ReadDir(feedDir)
-			if err == nil {
-				for _, entry := range statEntries {
-					if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_stats.json") {
-						os.Remove(filepath.Join(feedDir, entry.Name()))
-					}
-				}
-			}
-			dialog.ShowInformation("Logs Cleared", "All logs and statistics have been deleted.", window)
-		}, window)
-	})
-
-	configTab := container.NewTabItem("Config", container.NewVBox(
-		widget.NewLabel("Log File Path:"),
-		container.NewBorder(nil, nil, nil, browseBtn, logEntry),
-		startBtn,
-		clearLogsBtn)) // Feed tab
-	// Single toggle button for raw logs
-	var rawToggleBtn *widget.Button
-	updateRawToggleBtn := func() {
-		if ShowRawLogLines {
-			rawToggleBtn.SetText("Disable Raw Logs")
-		} else {
-			rawToggleBtn.SetText("Enable Raw Logs")
-		}
-	}
-
-	rawToggleBtn = widget.NewButton("Enable Raw Logs", func() {
-		ShowRawLogLines = !ShowRawLogLines
-		updateRawToggleBtn()
-		h.refreshFeedDisplay()
-	})
-	scroll := container.NewScroll(outputRich)
-	scroll.SetMinSize(fyne.NewSize(0, 400)) // Ensure scroll area is visible
-	feedTab := container.NewTabItem("Feed", container.NewBorder(
-		container.NewVBox(
-			widget.NewLabelWithStyle("Current Player:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-			playerLabel,
-			widget.NewLabel("Feed:"),
-			rawToggleBtn,
-		), nil, nil, nil, scroll))
-	// Statistics tab with All-time and Current sections
-	allTimeKillScroll := container.NewScroll(allTimeKillList)
-	allTimeDeathScroll := container.NewScroll(allTimeDeathList)
-	allTimeKillScroll.SetMinSize(fyne.NewSize(0, 350))
-	allTimeDeathScroll.SetMinSize(fyne.NewSize(0, 350))
-
-	sessionKillScroll := container.NewScroll(sessionKillList)
-	sessionDeathScroll := container.NewScroll(sessionDeathList)
-	sessionKillScroll.SetMinSize(fyne.NewSize(0, 350))
-	sessionDeathScroll.SetMinSize(fyne.NewSize(0, 350))	// Reset button for all-time stats
-	resetButton := widget.NewButtonWithIcon("Reset All-time Stats", nil, func() {
-		if playerLabel.Text == "<none>" {
-			dialog.ShowInformation("No Player", "Please select a player first.", window)
-			return
-		}
-		
-		// Create custom confirmation dialog
-		confirmLabel := widget.NewRichTextFromMarkdown("## Reset All-time Statistics\n\nAre you sure you want to reset all-time statistics for **" + playerLabel.Text + "**?\n\n*This action cannot be undone.*")
-		
-		yesBtn := widget.NewButtonWithIcon("Yes, Reset", nil, func() {})
-		noBtn := widget.NewButtonWithIcon("No, Cancel", nil, func() {})
-		
-		yesBtn.Importance = widget.DangerImportance
-		noBtn.Importance = widget.MediumImportance
-		
-		content := container.NewVBox(
-			confirmLabel,
-			container.NewBorder(nil, nil, nil, nil, 
-				container.NewHBox(yesBtn, noBtn),
-			),
-		)
-		
-		confirmDialog := dialog.NewCustom("Confirm Reset", "Close", content, window)
-		
-		yesBtn.OnTapped = func() {
-			confirmDialog.Hide()
-			stats.ResetAllTime(playerLabel.Text)
-			updateStats(playerLabel.Text)
-			dialog.ShowInformation("Reset Complete", "All-time statistics have been reset.", window)
-		}
-		
-		noBtn.OnTapped = func() {
-			confirmDialog.Hide()
-		}
-		
-		confirmDialog.Show()
-	})
-	resetButton.Importance = widget.HighImportance
-	// All-time stats tab with enhanced styling
-	allTimeKillCard := container.NewBorder(
-		container.NewVBox(
-			widget.NewCard("", "", container.NewVBox(
-				widget.NewLabelWithStyle("🎯 Top 10 Victims (You Killed)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-				widget.NewSeparator(),
-			)),
-		), nil, nil, nil, allTimeKillScroll)
-	
-	allTimeDeathCard := container.NewBorder(
-		container.NewVBox(
-			widget.NewCard("", "", container.NewVBox(
-				widget.NewLabelWithStyle("💀 Top 10 Killers (Killed You)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-				widget.NewSeparator(),
-			)),
-		), nil, nil, nil, allTimeDeathScroll)
-
-	allTimeTab := container.NewTabItem("📊 All-time", container.NewVBox(
-		widget.NewCard("All-Time Statistics", "Persistent stats saved across sessions", 
-			container.NewGridWithColumns(2, allTimeKillCard, allTimeDeathCard)),
-		container.NewBorder(nil, nil, nil, nil,
-			container.NewHBox(
-				widget.NewSeparator(),
-				resetButton,
-				widget.NewSeparator(),
-			)),
-	))
-	// Current session stats tab with enhanced styling
-	sessionKillCard := container.NewBorder(
-		container.NewVBox(
-			widget.NewCard("", "", container.NewVBox(
-				widget.NewLabelWithStyle("🎯 Session Victims (You Killed)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-				widget.NewSeparator(),
-			)),
-		), nil, nil, nil, sessionKillScroll)
-	
-	sessionDeathCard := container.NewBorder(
-		container.NewVBox(
-			widget.NewCard("", "", container.NewVBox(
-				widget.NewLabelWithStyle("💀 Session Killers (Killed You)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
-				widget.NewSeparator(),
-			)),
-		), nil, nil, nil, sessionDeathScroll)
-
-	currentTab := container.NewTabItem("⚡ Current Session", 
-		widget.NewCard("Current Session Statistics", "Stats reset when the app restarts",
-			container.NewGridWithColumns(2, sessionKillCard, sessionDeathCard)))
-
-	// Create nested tabs for statistics
-	statsTabs := container.NewAppTabs(allTimeTab, currentTab)
-	statsTab := container.NewTabItem("Statistics", statsTabs)
-
-	// --- FEED PERSISTENCE ---
-	// Helper to get feed save directory
-	getFeedDir = func() string {
-		dir := filepath.Join(os.Getenv("APPDATA"), "citizenmon", "feeds")
-		os.MkdirAll(dir, 0755)
-		return dir
-	}
-
-	// Helper to generate feed filename
-	getFeedFilename := func(playerName string) string {
-		if playerName == "" {
-			playerName = "Unknown"
-		}
-		// Sanitize playerName for filename: replace spaces with underscores
-		playerName = strings.ReplaceAll(playerName, " ", "_")
-		date := time.Now().Format("2006-01-02")
-		base := filepath.Join(getFeedDir(), playerName+"_"+date)
-		idx := 1
-		filename := base + ".txt"
-		for {
-			if _, err := os.Stat(filename); os.IsNotExist(err) {
-				break
-			}
-			idx++
-			filename = fmt.Sprintf("%s_%d.txt", base, idx)
-		}
-		return filename
-	}
-
-	// Save feed to file (JSON, grouped by line)
-	saveFeed := func() {
-		filename := getFeedFilename(core.PlayerName)
-		jsonFile := filename[:len(filename)-4] + ".json"
-		// Ensure we do not overwrite an existing file: increment suffix if needed
-		base := jsonFile[:len(jsonFile)-5] // remove .json
-		idx := 1
-		finalFile := jsonFile
-		for {
-			if _, err := os.Stat(finalFile); os.IsNotExist(err) {
-				break
-			}
-			idx++
-			finalFile = fmt.Sprintf("%s_%d.json", base, idx)
-		}
-		f, err := os.Create(finalFile)
-		if err != nil {
-			return
-		}
-		defer f.Close()
-		var lines [][]FeedSegment
-		var currentLine []FeedSegment
-		for _, seg := range outputRich.Segments {
-			switch s := seg.(type) {
-			case *widget.TextSegment:
-				if s.Text == "\n" {
-					currentLine = append(currentLine, FeedSegment{Type: "text", Text: "\n"})
-					lines = append(lines, currentLine)
-					currentLine = nil
-				} else if strings.Contains(s.Text, "\n") {
-					parts := strings.Split(s.Text, "\n")
-					for i, part := range parts {
-						if part != "" {
-							currentLine = append(currentLine, FeedSegment{Type: "text", Text: part})
-						}
-						if i < len(parts)-1 {
-							currentLine = append(currentLine, FeedSegment{Type: "text", Text: "\n"})
-							lines = append(lines, currentLine)
-							currentLine = nil
-						}
-					}
-				} else {
-					currentLine = append(currentLine, FeedSegment{Type: "text", Text: s.Text})
-				}
-			case *widget.HyperlinkSegment:
-				currentLine = append(currentLine, FeedSegment{Type: "hyperlink", Text: s.Text, URL: s.URL.String()})
-			}
-		}
-		// Do not flush currentLine if not ended with newline (to avoid trailing partial line)
-		enc := json.NewEncoder(f)
-		enc.SetIndent("", "  ")
-		_ = enc.Encode(lines)
-	}
-
-	// Save on window close
-	window.SetCloseIntercept(func() {
-		saveFeed()
-		window.Close()
-	})
-
-	// --- FEED HISTORY TAB (DROPDOWN + EXPANDED VIEW) ---
-	getFeedFiles := func() []string {
-		dir := getFeedDir()
-		files, _ := os.ReadDir(dir)
-		var feedFiles []string
-		for _, f := range files {
-			if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") && !strings.HasSuffix(f.Name(), "_stats.json") {
-				feedFiles = append(feedFiles, f.Name())
-			}
-		}
-		// Sort newest first
-		if len(feedFiles) > 1 {
-			// Sort by file mod time descending
-			sort.Slice(feedFiles, func(i, j int) bool {
-				fi, _ := os.Stat(filepath.Join(getFeedDir(), feedFiles[i]))
-				fj, _ := os.Stat(filepath.Join(getFeedDir(), feedFiles[j]))
-				return fi.ModTime().After(fj.ModTime())
-			})
-		}
-		return feedFiles
-	}
-
-	var feedFiles []string
-	var selectedFeedPath string
-	feedSelectEntry := widget.NewSelectEntry(nil)
-	feedSelectEntry.SetPlaceHolder("Search or select log...")
-
-	refreshFeedSelectEntry := func() {
-		feedFiles = getFeedFiles()
-		feedSelectEntry.SetOptions(feedFiles)
-		if len(feedFiles) > 0 {
-			feedSelectEntry.SetText(feedFiles[0])
-		}
-	}
-
-	feedSelectEntry.OnChanged = func(selected string) {
-		// Autocomplete: filter options as user types
-		q := strings.ToLower(feedSelectEntry.Text)
-		var filtered []string
-		for _, f := range feedFiles {
-			if strings.Contains(strings.ToLower(f), q) {
-				filtered = append(filtered, f)
-			}
-		}
-		feedSelectEntry.SetOptions(filtered)
-		// Fyne workaround: no .Open(), so show a List below if filtering (simulate dropdown)
-		// (Implementation: see below for a custom popup if needed)
-
-		if selected == "" {
-			historyRich.Segments = []widget.RichTextSegment{}
-			historyRich.Refresh()
-			selectedFeedPath = ""
-			return
-		}
-		selectedFeedPath = filepath.Join(getFeedDir(), selected)
-		data, _ := os.ReadFile(selectedFeedPath)
-		var linesData [][]FeedSegment
-		_ = json.Unmarshal(data, &linesData)
-		var segments []widget.RichTextSegment
-		for _, line := range linesData {
-			var lineSegments []widget.RichTextSegment
-			var textBuffer strings.Builder
-			for _, seg := range line {
-				if seg.Type == "text" {
-					if seg.Text == "\n" {
-						if textBuffer.Len() > 0 {
-							lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: widget.RichTextStyle{Inline: true}})
-							textBuffer.Reset()
-						}
-						continue
-					} else {
-						textBuffer.WriteString(seg.Text)
-					}
-				} else if seg.Type == "hyperlink" {
-					if textBuffer.Len() > 0 {
-						lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: widget.RichTextStyle{Inline: true}})
-						textBuffer.Reset()
-					}
-					u, _ := url.Parse(seg.URL)
-					lineSegments = append(lineSegments, &widget.HyperlinkSegment{Text: seg.Text, URL: u})
-				}
-			}
-			if textBuffer.Len() > 0 {
-				lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: widget.RichTextStyle{Inline: true}})
-				textBuffer.Reset()
-			}
-			lineSegments = append(lineSegments, &widget.TextSegment{Text: "\n", Style: widget.RichTextStyle{Inline: true}})
-			segments = append(segments, lineSegments...)
-		}
-		historyRich.Segments = segments
-		historyRich.Refresh()
-	}
-
-	refreshFeedSelectEntry()
-
-	historyTab := container.NewTabItem("History", container.NewBorder(
-		container.NewVBox(
-			widget.NewButton("Open Log", func() {
-				showLogBrowser(getFeedFiles, func(filename string) {
-					selectedFeedPath = filepath.Join(getFeedDir(), filename)
-					data, _ := os.ReadFile(selectedFeedPath)
-					var linesData [][]FeedSegment
-					_ = json.Unmarshal(data, &linesData)
-					var segments []widget.RichTextSegment
-					for _, line := range linesData {
-						var lineSegments []widget.RichTextSegment
-						var textBuffer strings.Builder
-						for _, seg := range line {
-							if seg.Type == "text" {
-								if seg.Text == "\n" {
-									if textBuffer.Len() > 0 {
-										lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: widget.RichTextStyle{Inline: true}})
-										textBuffer.Reset()
-									}
-									continue
-								} else {
-									textBuffer.WriteString(seg.Text)
-								}
-							} else if seg.Type == "hyperlink" {
-								if textBuffer.Len() > 0 {
-									lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: widget.RichTextStyle{Inline: true}})
-									textBuffer.Reset()
-								}
-								u, _ := url.Parse(seg.URL)
-								lineSegments = append(lineSegments, &widget.HyperlinkSegment{Text: seg.Text, URL: u})
-							}
-						}
-						if textBuffer.Len() > 0 {
-							lineSegments = append(lineSegments, &widget.TextSegment{Text: textBuffer.String(), Style: widget.RichTextStyle{Inline: true}})
-							textBuffer.Reset()
-						}
-						lineSegments = append(lineSegments, &widget.TextSegment{Text: "\n", Style: widget.RichTextStyle{Inline: true}})
-						segments = append(segments, lineSegments...)
-					}
-					historyRich.Segments = segments
-					historyRich.Refresh()
-				})
-			}),
-			widget.NewButton("Convert Log", func() { convertLogToHistory(window) }),
-		),
-		widget.NewButton("Export as HTML", func() {
-			if selectedFeedPath == "" {
-				dialog.ShowInformation("No Feed Selected", "Please select a feed to export.", window)
-				return
-			}
-			exportFeedToHTML(selectedFeedPath, window)
-		}),
-		nil, nil,
-		container.NewVScroll(historyRich),
-	))
-
-	// assemble tabs
-	tabs := container.NewAppTabs(
-		feedTab,
-		statsTab,
-		configTab,
-		historyTab,
-	)
-	// auto-start or config
-	if saved != "" {
-		// Ensure feed initializes with the game log and displays monitoring message
-		core.AppendOutput("Monitoring: " + saved)
-		go watcher.WatchLogFile(saved, h)
-		tabs.Select(feedTab)
-	} else {
-		tabs.Select(configTab)
-	}
-
-	window.SetContent(tabs)
-	window.Resize(fyne.NewSize(800, 600))
-	window.ShowAndRun()
-}
-
-// Serializable struct for a segment (text or hyperlink)
-type FeedSegment struct {
-	Type string `json:"type"` // "text" or "hyperlink"
-	Text string `json:"text"`
-	URL  string `json:"url,omitempty"`
-}
-
-// Each log line is a slice of segments
-// The feed is a slice of lines
-
-// HTML escape function for feed export
-func htmlEscape(text string) string {
-	return strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(text, "&", "&amp;"), "<", "&lt;"), ">", "&gt;")
-}
-
-// Export feed to HTML file
-func exportFeedToHTML(feedPath string, parent fyne.Window) {
-	data, err := os.ReadFile(feedPath)
-	if err != nil {
-		dialog.ShowError(fmt.Errorf("failed to read feed: %w", err), parent)
-		return
-	}
-	html := "<html><head><meta charset='utf-8'><title>CitizenMon Feed Export</title></head><body><pre>" +
-		htmlEscape(string(data)) + "</pre></body></html>"
-	dialog.ShowFileSave(func(uc fyne.URIWriteCloser, err error) {
-		if uc == nil || err != nil {
-			return
-		}
-		defer uc.Close()
-		uc.Write([]byte(html))
-	}, parent)
-}
-
-// --- Convert Log to History ---
-func convertLogToHistory(parent fyne.Window) {
-	dialog.ShowFileOpen(func(uc fyne.URIReadCloser, err error) {
-		if uc == nil || err != nil {
-			return
-		}
-		defer uc.Close()
-		logPath := uc.URI().Path()
-		data, err := os.ReadFile(logPath)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("failed to read log: %w", err), parent)
-			return
-		}
-		lines := strings.Split(string(data), "\n")
-
-		// Extract player name and date from log or filename
-		playerName := "Unknown"
-		logDate := time.Now().Format("2006-01-02")
-		base := filepath.Base(logPath)
-		// Try to extract date from filename (YYYY-MM-DD)
-		for _, part := range strings.FieldsFunc(base, func(r rune) bool { return r == ' ' || r == '_' || r == '-' || r == '(' || r == ')' }) {
-			if len(part) == 10 && part[4] == '-' && part[7] == '-' {
-				logDate = part
-				break
-			}
-		} // Try to find player name in log lines using the same detection logic as processor
-		for _, line := range lines {
-			// Look for nickname="PlayerName" pattern first
-			if strings.Contains(line, "nickname=") {
-				nicknameRegex := regexp.MustCompile(`nickname="([^"]+)"`)
-				if matches := nicknameRegex.FindStringSubmatch(line); len(matches) > 1 {
-					playerName = matches[1]
-					break
-				}
-			}
-			// Fallback: Look for Player[PlayerName] pattern
-			if strings.Contains(line, "Player[") {
-				playerRegex := regexp.MustCompile(`Player\[([^\]]+)\]`)
-				if matches := playerRegex.FindStringSubmatch(line); len(matches) > 1 {
-					playerName = matches[1]
-					break
-				}
-			}
-			// Legacy fallback
-			if strings.Contains(line, "Player name:") {
-				playerName = strings.TrimSpace(strings.SplitN(line, ":", 2)[1])
-				break
-			}
-		}
-		// Only use filename extraction as a last resort if no player name found in log content
-		if playerName == "Unknown" {
-			// Try to get from filename (before first space or underscore)
-			if idx := strings.IndexAny(base, " _"); idx > 0 {
-				possibleName := base[:idx]
-				// Only use filename if it doesn't look like a generic word
-				if possibleName != "Game" && possibleName != "Log" && possibleName != "StarCitizen" {
-					playerName = possibleName
-				}
-			}
-		}
-		playerName = strings.ReplaceAll(playerName, " ", "_")
-		if playerName == "" {
-			playerName = "Unknown"
-		}
-		// Remove debug dialog - directly proceed with conversion
-		// Scan all lines from top to bottom for kill messages (not just via processor)
-		var feed [][]FeedSegment
-		// Temporary processor to parse the log
-		proc := processor.New(nil, nil)
-		// Set the processor's player name first
-		proc.PlayerName = playerName
-		// Updated to match the required signature with logTime parameter
-		proc.AppendOutput = func(line string, logTime ...time.Time) {
-			if line == "" || line == "PlayerName is empty, skipping stats update for line" {
-				return
-			}
-			// Remove 'Player appeared' lines for the player character (robust, trims and matches underscores)
-			if strings.HasPrefix(line, "Player appeared:") {
-				parts := strings.SplitN(line, ":", 2)
-				if len(parts) > 1 {
-					appearedName := strings.TrimSpace(parts[1])
-					if strings.EqualFold(strings.ReplaceAll(appearedName, " ", "_"), strings.ReplaceAll(playerName, " ", "_")) {
-						return
-					}
-				}
-			}
-			// Extract timestamp - use current time as fallback
-			ts := time.Now().Format("2006-01-02 15:04:05")
-			if len(logTime) > 0 && !logTime[0].IsZero() {
-				ts = logTime[0].Local().Format("2006-01-02 15:04:05")
-			}
-			// Enhanced hyperlinking for kill/death/incap/corpse lines
-			segments := CreateEnhancedSegments(line, ts, playerName)
-			feed = append(feed, segments)
-		}
-
-		// Process all lines for kills/deaths/incaps/corpse
-		for _, line := range lines {
-			// Temporarily disable stats update in processor
-			oldStats := proc.Stats
-			proc.Stats = stats.New() // blank stats so no file is written
-			proc.ProcessLogLine(line)
-			proc.Stats = oldStats
-		} // Save processed events without showing debug dialogs
-		if len(feed) == 0 {
-			feed = append(feed, []FeedSegment{
-				{Type: "text", Text: fmt.Sprintf("%s No kill/death messages found in this log for player %s.\n", time.Now().Format("2006-01-02 15:04:05"), playerName)},
-			})
-		}
-
-		// Save as .json in feeds dir, with Player_YYYY-MM-DD.json naming
-		feedsDir := filepath.Join(os.Getenv("APPDATA"), "citizenmon", "feeds")
-		os.MkdirAll(feedsDir, 0755)
-		jsonName := playerName + "_" + logDate + ".json"
-		jsonPath := filepath.Join(feedsDir, jsonName)
-		idx := 1
-		for {
-			if _, err := os.Stat(jsonPath); os.IsNotExist(err) {
-				break
-			}
-			jsonPath = filepath.Join(feedsDir, fmt.Sprintf("%s_%d.json", playerName+"_"+logDate, idx))
-			idx++
-		}
-		f, err := os.Create(jsonPath)
-		if err != nil {
-			dialog.ShowError(fmt.Errorf("failed to save history: %w", err), parent)
-			return
-		}
-		defer f.Close()
-		enc := json.NewEncoder(f)
-		enc.SetIndent("", "  ")
-		_ = enc.Encode(feed)
-		dialog.ShowInformation("Converted", "Log converted to history: "+jsonPath, parent)
-		if fyne.CurrentApp() != nil {
-			for _, w := range fyne.CurrentApp().Driver().AllWindows() {
-				if w.Title() == "Citizen Killstalker" {
-					w.Content().Refresh()
-				}
-			}
-		}
-	}, parent)
-}
-
-// CreateEnhancedSegments creates segments with enhanced hyperlinking for log conversion
-func CreateEnhancedSegments(line, timestamp, playerName string) []FeedSegment {
-	var segments []FeedSegment
-	segments = append(segments, FeedSegment{Type: "text", Text: timestamp + " "})
-
-	// First, check if this is an already-processed message from the event aggregation system
-	// These should not be re-processed through the enhanced hyperlinking system
-	if strings.HasPrefix(line, "You were killed by: ") ||
-		strings.HasPrefix(line, "You died by ") ||
-		strings.HasPrefix(line, "You turned to a corpse") ||
-		strings.HasPrefix(line, "Mission Event: ") ||
-		strings.HasPrefix(line, "Vehicle ") && strings.Contains(line, " was destroyed by ") {
-		// Handle as plain text without further processing
-		segments = append(segments, FeedSegment{Type: "text", Text: line})
-		segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
-		return segments
-	}
-
-	// Handle different types of processor output lines with specific patterns
-
-	// 1. Corpse messages: "PlayerName has turned to a corpse"
-	if strings.Contains(line, "has turned to a corpse") {
-		parts := strings.SplitN(line, " has turned to a corpse", 2)
-		if len(parts) > 0 {
-			name := strings.TrimSpace(parts[0])
-			if isNPCName(name) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(name)})
-			} else if isPetName(name) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(name)})
-			} else if shouldHyperlinkName(name) {
-				segments = append(segments, FeedSegment{Type: "hyperlink", Text: name, URL: "https://robertsspaceindustries.com/en/citizens/" + name})
-			} else {
-				segments = append(segments, FeedSegment{Type: "text", Text: name})
-			}
-			segments = append(segments, FeedSegment{Type: "text", Text: " has turned to a corpse"})
-			segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
-			return segments
-		}
-	}
-
-	// 2. Kill messages: "You killed: PlayerName using weapon" or "You were killed by: PlayerName using weapon"
-	if strings.Contains(line, "You killed:") || strings.Contains(line, "You were killed by:") || strings.Contains(line, "You incapacitated:") {
-		return createKillMessageSegments(line, segments, playerName)
-	}
-
-	// 3. Vehicle destruction: "Vehicle Name was destroyed by PlayerName using weapon"
-	if strings.Contains(line, "Vehicle") && (strings.Contains(line, "destroyed") || strings.Contains(line, "disabled")) {
-		return createVehicleMessageSegments(line, segments)
-	}
-
-	// 4. Generic fallback for other lines - apply basic hyperlinking
-	words := strings.Fields(line)
-	byIdx := -1
-	for i, w := range words {
-		if strings.ToLower(w) == "by" && i < len(words)-1 {
-			byIdx = i + 1
-		}
-	}
-
-	for i, w := range words {
-		clean := strings.Trim(w, ",.?!;:'\"[]()")
-		shouldHyperlink := false
-
-		// Hyperlink player names in specific contexts
-		if len(clean) >= 3 {
-			if i == byIdx || // After "by"
-				strings.EqualFold(strings.ReplaceAll(clean, " ", "_"), strings.ReplaceAll(playerName, " ", "_")) { // Player's own name
-				shouldHyperlink = shouldHyperlinkName(clean)
-			}
-		}
-
-		if shouldHyperlink {
-			segments = append(segments, FeedSegment{Type: "hyperlink", Text: w, URL: "https://robertsspaceindustries.com/en/citizens/" + clean})
-		} else {
-			// Apply NPC/pet formatting even for non-hyperlinked names
-			displayText := w
-			if isNPCName(clean) {
-				displayText = strings.Replace(w, clean, formatNPCName(clean), 1)
-			} else if isPetName(clean) {
-				displayText = strings.Replace(w, clean, formatPetName(clean), 1)
-			}
-			segments = append(segments, FeedSegment{Type: "text", Text: displayText})
-		}
-
-		if i < len(words)-1 {
-			segments = append(segments, FeedSegment{Type: "text", Text: " "})
-		}
-	}
-
-	segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
-	return segments
-}
-
-// createKillMessageSegments handles kill/death/incap messages
-func createKillMessageSegments(line string, baseSegments []FeedSegment, playerName string) []FeedSegment {
-	segments := baseSegments
-
-	// Parse different kill message patterns
-	if strings.HasPrefix(line, "You killed:") {
-		// "You killed: PlayerName using weapon"
-		parts := strings.SplitN(line, "You killed:", 2)
-		if len(parts) > 1 {
-			remaining := strings.TrimSpace(parts[1])
-			usingIdx := strings.Index(remaining, " using ")
-
-			segments = append(segments, FeedSegment{Type: "text", Text: "You killed: "})
-
-			if usingIdx > 0 {
-				// Has weapon info
-				victim := strings.TrimSpace(remaining[:usingIdx])
-				weapon := strings.TrimSpace(remaining[usingIdx+7:])
-
-				// Apply enhanced formatting for NPCs and pets
-				if isNPCName(victim) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(victim)})
-				} else if isPetName(victim) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(victim)})
-				} else if shouldHyperlinkName(victim) {
-					segments = append(segments, FeedSegment{Type: "hyperlink", Text: victim, URL: "https://robertsspaceindustries.com/en/citizens/" + victim})
-				} else {
-					segments = append(segments, FeedSegment{Type: "text", Text: victim})
-				}
-				segments = append(segments, FeedSegment{Type: "text", Text: " using " + weapon})
-			} else {
-				// No weapon info
-				victim := strings.TrimSpace(remaining)
-				if isNPCName(victim) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(victim)})
-				} else if isPetName(victim) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(victim)})
-				} else if shouldHyperlinkName(victim) {
-					segments = append(segments, FeedSegment{Type: "hyperlink", Text: victim, URL: "https://robertsspaceindustries.com/en/citizens/" + victim})
-				} else {
-					segments = append(segments, FeedSegment{Type: "text", Text: victim})
-				}
-			}
-		}
-	} else if strings.HasPrefix(line, "You were killed by:") {
-		// "You were killed by: PlayerName using weapon"
-		parts := strings.SplitN(line, "You were killed by:", 2)
-		if len(parts) > 1 {
-			remaining := strings.TrimSpace(parts[1])
-			usingIdx := strings.Index(remaining, " using ")
-
-			segments = append(segments, FeedSegment{Type: "text", Text: "You were killed by: "})
-
-			if usingIdx > 0 {
-				// Has weapon info
-				killer := strings.TrimSpace(remaining[:usingIdx])
-				weapon := strings.TrimSpace(remaining[usingIdx+7:])
-
-				// Apply enhanced formatting for NPCs, pets, and suicide
-				if strings.ToLower(killer) == "suicide" {
-					segments = append(segments, FeedSegment{Type: "text", Text: killer})
-				} else if isNPCName(killer) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
-				} else if isPetName(killer) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
-				} else if shouldHyperlinkName(killer) {
-					segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: "https://robertsspaceindustries.com/en/citizens/" + killer})
-				} else {
-					segments = append(segments, FeedSegment{Type: "text", Text: killer})
-				}
-				segments = append(segments, FeedSegment{Type: "text", Text: " using " + weapon})
-			} else {
-				// No weapon info
-				killer := strings.TrimSpace(remaining)
-				if strings.ToLower(killer) == "suicide" {
-					segments = append(segments, FeedSegment{Type: "text", Text: killer})
-				} else if isNPCName(killer) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
-				} else if isPetName(killer) {
-					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
-				} else if shouldHyperlinkName(killer) {
-					segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: "https://robertsspaceindustries.com/en/citizens/" + killer})
-				} else {
-					segments = append(segments, FeedSegment{Type: "text", Text: killer})
-				}
-			}
-		}
-	} else if strings.HasPrefix(line, "You incapacitated:") {
-		// "You incapacitated: PlayerName"
-		parts := strings.SplitN(line, "You incapacitated:", 2)
-		if len(parts) > 1 {
-			victim := strings.TrimSpace(parts[1])
-			segments = append(segments, FeedSegment{Type: "text", Text: "You incapacitated: "})
-
-			if isNPCName(victim) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(victim)})
-			} else if isPetName(victim) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(victim)})
-			} else if shouldHyperlinkName(victim) {
-				segments = append(segments, FeedSegment{Type: "hyperlink", Text: victim, URL: "https://robertsspaceindustries.com/en/citizens/" + victim})
-			} else {
-				segments = append(segments, FeedSegment{Type: "text", Text: victim})
-			}
-		}
-	}
-
-	segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
-	return segments
-}
-
-// createVehicleMessageSegments handles vehicle destruction messages
-func createVehicleMessageSegments(line string, baseSegments []FeedSegment) []FeedSegment {
-	segments := baseSegments
-
-	// Parse vehicle destruction: "Vehicle Name was destroyed by PlayerName using weapon"
-	byIdx := strings.Index(line, " by ")
-	usingIdx := strings.Index(line, " using ")
-
-	if byIdx > 0 {
-		beforeBy := line[:byIdx]
-		afterBy := line[byIdx+4:]
-
-		segments = append(segments, FeedSegment{Type: "text", Text: beforeBy + " by "})
-
-		if usingIdx > byIdx {
-			// Has weapon info
-			killer := strings.TrimSpace(afterBy[:usingIdx-byIdx-4])
-			weapon := strings.TrimSpace(afterBy[usingIdx-byIdx-4+7:])
-
-			// Apply enhanced formatting for NPCs, pets, and suicide
-			if strings.ToLower(killer) == "suicide" {
-				segments = append(segments, FeedSegment{Type: "text", Text: killer})
-			} else if isNPCName(killer) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
-			} else if isPetName(killer) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
-			} else if shouldHyperlinkName(killer) {
-				segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: "https://robertsspaceindustries.com/en/citizens/" + killer})
-			} else {
-				segments = append(segments, FeedSegment{Type: "text", Text: killer})
-			}
-			segments = append(segments, FeedSegment{Type: "text", Text: " using " + weapon})
-		} else {
-			// No weapon info or collision
-			killer := strings.TrimSpace(afterBy)
-			if strings.ToLower(killer) == "suicide" {
-				segments = append(segments, FeedSegment{Type: "text", Text: killer})
-			} else if isNPCName(killer) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
-			} else if isPetName(killer) {
-				segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
-			} else if shouldHyperlinkName(killer) {
-				segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: "https://robertsspaceindustries.com/en/citizens/" + killer})
-			} else {
-				segments = append(segments, FeedSegment{Type: "text", Text: killer})
-			}
-		}
-	} else {
-		// Fallback: just add as text
-		segments = append(segments, FeedSegment{Type: "text", Text: line})
-	}
-
-	segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
-	return segments
-}
-
-// --- LOG BROWSER WINDOW ---
-func showLogBrowser(getFeedFiles func() []string, onSelect func(filename string)) {
-	logs := getFeedFiles()
-	filtered := make([]string, len(logs))
-	copy(filtered, logs)
-
-	searchEntry := widget.NewEntry()
-	searchEntry.SetPlaceHolder("Search logs...")
-
-	list := widget.NewList(
-		func() int { return len(filtered) },
-		func() fyne.CanvasObject { return widget.NewLabel("") },
-		func(i int, o fyne.CanvasObject) {
-			if i < len(filtered) {
-				o.(*widget.Label).SetText(filtered[i])
-			}
-		},
-	)
-
-	var browserWin fyne.Window
-	list.OnSelected = func(id int) {
-		if id >= 0 && id < len(filtered) {
-			onSelect(filtered[id])
-			browserWin.Close()
-		}
-	}
-
-	searchEntry.OnChanged = func(s string) {
-		q := strings.ToLower(s)
-		filtered = filtered[:0]
-		for _, f := range logs {
-			if strings.Contains(strings.ToLower(f), q) {
-				filtered = append(filtered, f)
-			}
-		}
-		list.Refresh()
-	}
-
-	browserWin = fyne.CurrentApp().NewWindow("Open Log")
-	browserWin.SetContent(container.NewBorder(
-		searchEntry, nil, nil, nil,
-		container.NewVScroll(list),
-	))
-	browserWin.Resize(fyne.NewSize(400, 500))
-	browserWin.Show()
-}
-
-// Added missing methods to logHandlerAdapter to implement watcher.LogHandler
-func (a *logHandlerAdapter) AppendOutput(line string) {
-	a.AppendOutputWithRaw(line, "")
-}
-
-func (a *logHandlerAdapter) AppendOutputWithRaw(line string, rawLogLine string) {
-	fyne.Do(func() {
-		fmt.Printf("AppendOutputWithRaw called with: '%s' (raw: '%s')\n", line, rawLogLine)
-
-		// Create segments for this line with improved hyperlink logic
-		var segments []widget.RichTextSegment
-		// Enhanced player name detection for hyperlinks
-		words := strings.Fields(line)
-		
-		// Find "by" index for context-aware hyperlinking
-		byIdx := -1
-		for i, w := range words {
-			if strings.ToLower(w) == "by" && i < len(words)-1 {
-				byIdx = i + 1
-			}
-		}
-				for i, word := range words {
-			clean := strings.Trim(word, ",.?!;:'\"[]()")
-			shouldCreateHyperlink := false
-			displayText := word
-
-			// Enhanced hyperlinking logic - handle timestamped messages properly
-			if len(clean) >= 3 {
-				// Check various contexts where player names appear
-				// For kill messages, look for position after "killed:" word
-				killedIdx := -1
-				incapIdx := -1
-				for j, w := range words {
-					if strings.Contains(w, "killed:") {
-						killedIdx = j + 1
-					}
-					if strings.Contains(w, "incapacitated:") {
-						incapIdx = j + 1
-					}
-				}
-
-				if i == byIdx || // After "by"
-					i == killedIdx || // After "killed:"
-					i == incapIdx || // After "incapacitated:"
-					(strings.Contains(line, "corpse") && !strings.HasPrefix(line, "You")) || // In corpse messages (but not "You" messages)
-					(strings.Contains(line, "died") && i > 0 && strings.ToLower(words[i-1]) == "by") { // Deaths by player
-					shouldCreateHyperlink = shouldHyperlinkName(clean)
-				}
-			}
-
-			// Apply NPC/pet formatting even for non-hyperlinked names
-			if isNPCName(clean) {
-				displayText = strings.Replace(word, clean, formatNPCName(clean), 1)
-			} else if isPetName(clean) {
-				displayText = strings.Replace(word, clean, formatPetName(clean), 1)
-			}
-
-			if shouldCreateHyperlink {
-				segments = append(segments, &widget.HyperlinkSegment{
-					Text: displayText,
-					URL:  parseURL("https://robertsspaceindustries.com/en/citizens/" + clean),
-				})
-			} else {
-				segments = append(segments, &widget.TextSegment{
-					Text:  displayText,
-					Style: widget.RichTextStyle{Inline: true},
-				})
-			}
-
-			if i < len(words)-1 {
-				segments = append(segments, &widget.TextSegment{
-					Text:  " ",
-					Style: widget.RichTextStyle{Inline: true},
-				})
-			}
-		}
-
-		// Add newline
-		segments = append(segments, &widget.TextSegment{
-			Text:  "\n",
-			Style: widget.RichTextStyle{Inline: true},
-		}) // Store in allSegments with raw log line
-		a.allSegments = append(a.allSegments, struct {
-			segments   []widget.RichTextSegment
-			rawLogLine string
-		}{segments, rawLogLine})
-
-		fmt.Printf("Stored message in allSegments. Total count now: %d\n", len(a.allSegments))
-
-		// Directly append to RichText widget instead of calling refreshFeedDisplay
-		// This avoids performance issues and UI conflicts
-		a.outputRich.Segments = append(a.outputRich.Segments, segments...)
-
-		// If raw logs are enabled, add the raw log line
-		if ShowRawLogLines && rawLogLine != "" {
-			rawSegment := &widget.TextSegment{
-				Text:  "↳ Raw: " + rawLogLine + "\n",
-				Style: widget.RichTextStyle{Inline: true},
-			}
-			a.outputRich.Segments = append(a.outputRich.Segments, rawSegment)
-		}
-
-		// Refresh the widget to show new content
-		a.outputRich.Refresh()
-		fmt.Printf("Directly appended segments to outputRich. Total segments now: %d\n", len(a.outputRich.Segments))
-
-		// Trigger stats update if we have a player name
-		if a.proc.PlayerName != "" && a.onStatsUpdate != nil {
-			a.onStatsUpdate(a.proc.PlayerName)
-		}
-	})
-}
-
-// DetectPlayerName method for logHandlerAdapter
-func (a *logHandlerAdapter) DetectPlayerName(line string) {
-	a.proc.DetectPlayerName(line)
-}
-
-// ProcessLogLine method for logHandlerAdapter
-func (a *logHandlerAdapter) ProcessLogLine(line string) {
-	a.proc.ProcessLogLine(line)
-}
-
-// Helper function to parse URL safely
-func parseURL(urlStr string) *url.URL {
-	u, err := url.Parse(urlStr)
-	if err != nil {
-		return nil
-	}
-	return u
-}
-
-// Helper function to check if a string looks like a valid player name
-func isValidPlayerName(name string) bool {
-	// Player names are typically alphanumeric with underscores, 3+ characters
-	if len(name) < 3 || len(name) > 30 {
-		return false
-	}
-
-	// Check for valid player name characters (letters, numbers, underscores)
-	for _, r := range name {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
-			(r >= '0' && r <= '9') || r == '_') {
-			return false
-		}
-	}
-
-	// Avoid common non-player words and common English words
-	lowerName := strings.ToLower(name)
-	commonWords := []string{
-		"system", "server", "admin", "you", "killed", "using", "with", "the", "and",
-		"or", "by", "from", "to", "at", "in", "on", "for", "was", "were", "has",
-		"have", "had", "been", "being", "are", "is", "am", "will", "would", "could",
-		"should", "may", "might", "can", "cannot", "turned", "corpse", "incapacitated",
-	}
-
-	for _, word := range commonWords {
-		if lowerName == word {
-			return false
-		}
-	}
-	// Avoid common non-player words with contains check
-	if strings.Contains(lowerName, "system") ||
-		strings.Contains(lowerName, "server") ||
-		strings.Contains(lowerName, "admin") {
-		return false
-	}
-
-	// Don't consider NPCs as valid player names
-	if isNPCName(name) {
-		return false
-	}
-
-	// Don't consider pets as valid player names
-	if isPetName(name) {
-		return false
-	}
-
-	// Don't consider system names as valid player names
-	if isSystemName(name) {
-		return false
-	}
-
-	return true
-}
-
-// IsValidPlayerName - exported version for testing
-func IsValidPlayerName(name string) bool {
-	return isValidPlayerName(name)
-}
-
-// Helper function to detect and format NPC names
-func isNPCName(name string) bool {
-	return strings.Contains(name, "PU_Human_Enemy_GroundCombat_NPC") ||
-		strings.Contains(name, "_NPC_") ||
-		strings.Contains(name, "NPC_")
-}
-
-// IsNPCName - exported version for testing
-func IsNPCName(name string) bool {
-	return isNPCName(name)
-}
-
-// Helper function to detect and format pet names
-func isPetName(name string) bool {
-	return strings.Contains(strings.ToLower(name), "_pet_") ||
-		strings.HasPrefix(name, "Pet_")
-}
-
-// IsPetName - exported version for testing
-func IsPetName(name string) bool {
-	return isPetName(name)
-}
-
-// Helper function to format NPC names (shorten to "NPC")
-func formatNPCName(name string) string {
-	if isNPCName(name) {
-		return "NPC"
-	}
-	return name
-}
-
-// FormatNPCName - exported version for testing
-func FormatNPCName(name string) string {
-	return formatNPCName(name)
-}
-
-// Helper function to format pet names (extract first part before underscore)
-func formatPetName(name string) string {
-	if isPetName(name) {
-		// Handle Pet_ prefix format
-		if strings.HasPrefix(name, "Pet_") {
-			parts := strings.Split(name, "_")
-			if len(parts) >= 2 {
-				return "NPC " + parts[1] // Get the part after Pet_
-			}
-		}
-		// Handle _pet_ format (e.g., Kopion_pet_123)
-		if strings.Contains(strings.ToLower(name), "_pet_") {
-			parts := strings.Split(name, "_")
-			if len(parts) > 0 {
-				return "NPC " + parts[0] // Get the first part
-			}
-		}
-	}
-	return name
-}
-
-// FormatPetName - exported version for testing
-func FormatPetName(name string) string {
-	return formatPetName(name)
-}
-
-// Helper function to check if a name should be hyperlinked
-func shouldHyperlinkName(name string) bool {
-	// Don't hyperlink suicide
-	if strings.ToLower(name) == "suicide" {
-		return false
-	}
-
-	// Don't hyperlink "unknown"
-	if strings.ToLower(name) == "unknown" {
-		return false
-	}
-
-	// Don't hyperlink if it's "SELF"
-	if strings.ToUpper(name) == "SELF" {
-		return false // SELF should not be hyperlinked for suicide cases
-	}
-
-	// Don't hyperlink NPC names
-	if isNPCName(name) {
-		return false
-	}
-
-	// Don't hyperlink pet names
-	if isPetName(name) {
-		return false // Pets should not be hyperlinked
-	}
-
-	// Only hyperlink if it's a valid player name
-	return isValidPlayerName(name)
-}
-
-// ShouldHyperlinkName - exported version for testing
-func ShouldHyperlinkName(name string) bool {
-	return shouldHyperlinkName(name)
-}
-
-// Helper function to check if a name is a system/weapon/vehicle name
-func isSystemName(name string) bool {
-	systemNames := []string{
-		"collision", "fall", "suicide", "system", "server", "admin",
-		"ballistic", "energy", "missile", "torpedo", "cannon", "rifle",
-		"pistol", "shotgun", "sniper", "launcher", "turret", "shield",
-		"armor", "helmet", "suit", "vehicle", "ship", "quantum", "jump",
-		"unknown", // Add unknown as a system name too
-	}
-
-	lowerName := strings.ToLower(name)
-	for _, sys := range systemNames {
-		if strings.Contains(lowerName, sys) {
-			return true
-		}
-	}
-
-	// Check for NPC names
-	if isNPCName(name) {
-		return true
-	}
-
-	// Check for pet names
-	if isPetName(name) {
-		return true
-	}
-
-	return false
-}
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"game-monitor/pkg/convert"
+	"game-monitor/pkg/fsutil"
+	"game-monitor/pkg/metrics"
+	"game-monitor/pkg/notify"
+	"game-monitor/pkg/processor"
+	"game-monitor/pkg/stats"
+	"game-monitor/pkg/store"
+	"game-monitor/pkg/store/sqlite"
+	"game-monitor/pkg/watcher"
+)
+
+// Add global variable to control raw log display
+var ShowRawLogLines = false
+
+// Run sets up and runs the UI with Feed, Statistics, and Config tabs.
+func Run() {
+	a := app.NewWithID("io.yourname.gamemonitor")
+	window := a.NewWindow("Citizen Killstalker")
+	iconBytes, err := os.ReadFile("icon.png")
+	if err == nil {
+		window.SetIcon(fyne.NewStaticResource("icon.png", iconBytes))
+	}
+
+	prefs := a.Preferences()
+	saved := prefs.String("logPath")
+
+	// Data directory: the platform default, or next to the program in portable
+	// mode, unless overridden in Config
+	var dataDirErr error
+	if stats.PortableRequested(os.Args[1:]) {
+		if err := stats.EnablePortable(); err != nil {
+			dataDirErr = err
+		}
+	}
+	stats.SetDir(prefs.String("dataDir"))
+	// A portable copy must not pull in the installed copy's data from %APPDATA%
+	if prefs.String("dataDir") == "" && dataDirErr == nil && !stats.Portable() {
+		// Older versions wrote to %APPDATA%, which outside Windows was a relative path
+		if _, err := stats.MigrateLegacyDir(); err != nil {
+			dataDirErr = fmt.Errorf("failed to move files from the old data folder: %w", err)
+		}
+	}
+
+	// Helper to get feed save directory
+	getFeedDir := func() string {
+		return stats.Dir()
+	}
+	// refreshHistory reloads the History tab's feed list; set once that tab is built
+	refreshHistory := func() {}
+	// UI components
+	playerLabel := widget.NewLabel("<none>")
+	outputRich := widget.NewRichText()
+	// Remove truncation to prevent text from being cut off
+	// outputRich.Truncation = fyne.TextTruncateClip
+	// Enable text wrapping for outputRich to prevent long lines from breaking
+	outputRich.Wrapping = fyne.TextWrapWord
+
+	// Separate RichText for history
+	historyRich := widget.NewRichText()
+	historyRich.Wrapping = fyne.TextWrapWord
+	// Placeholders for all-time stats lists
+	allTimeKills := []stats.NameCount{}
+	allTimeDeaths := []stats.NameCount{}
+	
+	// Placeholders for current session stats lists
+	sessionKills := []stats.NameCount{}
+	sessionDeaths := []stats.NameCount{}
+	// openEncounters shows every recorded encounter between the active player and name
+	openEncounters := func(name string) {
+		if playerLabel.Text == "<none>" {
+			dialog.ShowInformation("No Player", "Please select a player first.", window)
+			return
+		}
+		showEncounters(window, getFeedDir(), playerLabel.Text, name)
+	}
+	// All-time stats lists with enhanced styling
+	allTimeKillList := widget.NewList(
+		func() int { return len(allTimeKills) },
+		newEncounterRow,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i < len(allTimeKills) {
+				e := allTimeKills[i]
+				link, details := encounterRowParts(o)
+				details.OnTapped = func() { openEncounters(e.Name) }
+				
+				// Add medal emoji for top ranks
+				medal := ""
+				switch i {
+				case 0: medal = "🥇 "
+				case 1: medal = "🥈 "
+				case 2: medal = "🥉 "
+				default: medal = "🎯 "
+				}
+				
+				url := citizenURL(e.Name)
+				link.SetText(fmt.Sprintf("%s#%d • %s (%d kills)", medal, i+1, e.Name, e.Count))
+				link.SetURLFromString(url)
+			}		},
+	)
+	allTimeDeathList := widget.NewList(
+		func() int { return len(allTimeDeaths) },
+		newEncounterRow,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i < len(allTimeDeaths) {
+				e := allTimeDeaths[i]
+				link, details := encounterRowParts(o)
+				details.OnTapped = func() { openEncounters(e.Name) }
+				
+				// Add skull emoji for top killers
+				skull := ""
+				switch i {
+				case 0: skull = "💀 "
+				case 1: skull = "☠️ "
+				case 2: skull = "⚰️ "
+				default: skull = "🔴 "
+				}
+				
+				if processor.IsSelfDeathKiller(e.Name) {
+					link.SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", skull, i+1, e.Name, e.Count))
+					link.SetURL(nil)
+				} else {
+					url := citizenURL(e.Name)
+					link.SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", skull, i+1, e.Name, e.Count))
+					link.SetURLFromString(url)
+				}
+			}
+		},
+	)
+	// Session stats lists with enhanced styling
+	sessionKillList := widget.NewList(
+		func() int { return len(sessionKills) },
+		func() fyne.CanvasObject {
+			return widget.NewHyperlink("", nil)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i < len(sessionKills) {
+				e := sessionKills[i]
+				
+				// Add lightning emoji for session stats
+				lightning := ""
+				switch i {
+				case 0: lightning = "⚡ "
+				case 1: lightning = "🔥 "
+				case 2: lightning = "💥 "
+				default: lightning = "🎯 "
+				}
+				
+				url := citizenURL(e.Name)
+				o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d kills)", lightning, i+1, e.Name, e.Count))
+				o.(*widget.Hyperlink).SetURLFromString(url)
+			}		},
+	)
+	sessionDeathList := widget.NewList(
+		func() int { return len(sessionDeaths) },
+		func() fyne.CanvasObject {
+			return widget.NewHyperlink("", nil)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i < len(sessionDeaths) {
+				e := sessionDeaths[i]
+				
+				// Add warning emoji for session deaths
+				warning := ""
+				switch i {
+				case 0: warning = "⚠️ "
+				case 1: warning = "🚨 "
+				case 2: warning = "💀 "
+				default: warning = "🔴 "
+				}
+				
+				if processor.IsSelfDeathKiller(e.Name) {
+					o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", warning, i+1, e.Name, e.Count))
+					o.(*widget.Hyperlink).SetURL(nil)
+				} else {
+					url := citizenURL(e.Name)
+					o.(*widget.Hyperlink).SetText(fmt.Sprintf("%s#%d • %s (%d deaths)", warning, i+1, e.Name, e.Count))
+					o.(*widget.Hyperlink).SetURLFromString(url)
+				}
+			}
+		},
+	)
+	// Incapacitation and sighting lists share one row layout
+	allTimeIncaps := []stats.NameCount{}
+	sessionIncaps := []stats.NameCount{}
+	allTimeAppearances := []stats.NameCount{}
+	newCountList := func(entries *[]stats.NameCount, icon, unit string) *widget.List {
+		return widget.NewList(
+			func() int { return len(*entries) },
+			func() fyne.CanvasObject {
+				return widget.NewHyperlink("", nil)
+			},
+			func(i widget.ListItemID, o fyne.CanvasObject) {
+				if i < len(*entries) {
+					e := (*entries)[i]
+					link := o.(*widget.Hyperlink)
+					link.SetText(fmt.Sprintf("%s #%d • %s (%d %s)", icon, i+1, e.Name, e.Count, unit))
+					if shouldHyperlinkName(e.Name) {
+						link.SetURLFromString(citizenURL(e.Name))
+					} else {
+						link.SetURL(nil)
+					}
+				}
+			},
+		)
+	}
+	allTimeIncapList := newCountList(&allTimeIncaps, "🩹", "incaps")
+	sessionIncapList := newCountList(&sessionIncaps, "🩹", "incaps")
+	mostSeenList := newCountList(&allTimeAppearances, "👀", "sightings")
+	mostSeenEmpty := widget.NewLabelWithStyle("No players seen yet", fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	allTimeIncapEmpty := widget.NewLabelWithStyle("No incapacitations yet", fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	sessionIncapEmpty := widget.NewLabelWithStyle("No incapacitations yet", fyne.TextAlignCenter, fyne.TextStyle{Italic: true})
+	// Optional Prometheus endpoint mirroring the all-time totals (seeded on every stats refresh)
+	metricsServer := metrics.New()
+
+	// Headline rivalry cards for the all-time tab
+	nemesisLabel := widget.NewLabelWithStyle("No nemesis yet", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	victimLabel := widget.NewLabelWithStyle("No victims yet", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	teamKillsLabel := widget.NewLabelWithStyle("No team kills", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	// Session log state, shared by the playtime labels and the Sessions tab
+	pastSessions := stats.LoadSessions()
+	var activeSession *stats.Session
+	var sessionBasePlayer string
+	var sessionBaseKills, sessionBaseDeaths int
+
+	// Playtime and kill rate, from the session log plus the running session
+	allTimePlaytimeLabel := widget.NewLabelWithStyle("No sessions recorded yet", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	sessionPlaytimeLabel := widget.NewLabelWithStyle("Not monitoring", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	// Damage type breakdown for the current session
+	sessionDamageLabel := widget.NewLabel("No deaths recorded yet")
+	sessionDamageLabel.Wrapping = fyne.TextWrapWord
+	// How the player dies, grouped into broad causes
+	allTimeCauseChart := newBarChart("No deaths recorded yet")
+	// When the player gets their kills, by hour in the display time zone
+	killHourChart := newHourChart()
+	sessionCauseChart := newBarChart("No deaths recorded yet")
+
+	// Main tabs, built further down; updateStats puts the session totals in their titles
+	var tabs *container.AppTabs
+	var feedTab, statsTab *container.TabItem
+	updateStats := func(playerName string) {
+		fyne.Do(func() {
+			// Load all-time stats
+			allTimeStatsData := stats.Load(playerName)
+			metricsServer.Update(playerName, allTimeStatsData.TotalKills(), allTimeStatsData.TotalDeaths())
+			// Keep the top 10 by count, ordered by the chosen mode
+			includeEnvironment := prefs.BoolWithFallback("leaderboardEnvironment", true)
+			allTimeKills = stats.TopCounts(leaderboardCounts(allTimeStatsData.Kills, includeEnvironment), 10)
+			sortStatEntries(allTimeKills, prefs.StringWithFallback("sortAllTimeKills", statsSortCount))
+			allTimeKillList.Refresh()
+			
+			// Keep the top 10 by count, ordered by the chosen mode
+			allTimeDeaths = stats.TopCounts(leaderboardCounts(allTimeStatsData.Deaths, includeEnvironment), 10)
+			sortStatEntries(allTimeDeaths, prefs.StringWithFallback("sortAllTimeDeaths", statsSortCount))
+			allTimeDeathList.Refresh()
+
+			allTimeIncaps = stats.TopCounts(allTimeStatsData.Incaps, 10)
+			allTimeIncapList.Refresh()
+			allTimeIncapEmpty.Hidden = len(allTimeIncaps) > 0
+			allTimeIncapEmpty.Refresh()
+
+			allTimeAppearances = stats.TopCounts(allTimeStatsData.Appearances, 25)
+			mostSeenList.Refresh()
+			mostSeenEmpty.Hidden = len(allTimeAppearances) > 0
+			mostSeenEmpty.Refresh()
+
+			if name, count := allTimeStatsData.Nemesis(); name != "" {
+				nemesisLabel.SetText(fmt.Sprintf("%s (%d kills on you)", name, count))
+			} else {
+				nemesisLabel.SetText("No nemesis yet")
+			}
+			if name, count := allTimeStatsData.FavoriteVictim(); name != "" {
+				victimLabel.SetText(fmt.Sprintf("%s (%d kills)", name, count))
+			} else {
+				victimLabel.SetText("No victims yet")
+			}
+			teamKillsLabel.SetText(formatTeamKills(allTimeStatsData.FriendlyKills))
+			allTimeCauseChart.set(groupDeathCauses(allTimeStatsData.DamageTypes))
+			killHourChart.set(allTimeStatsData.KillsByLocalHour(processor.TimestampLocation()))
+			
+			// Load current session stats
+			sessionStatsData := stats.GetCurrentSession(playerName)
+			// Keep the top 10 by count, ordered by the chosen mode
+			sessionKills = stats.TopCounts(leaderboardCounts(sessionStatsData.Kills, includeEnvironment), 10)
+			sortStatEntries(sessionKills, prefs.StringWithFallback("sortSessionKills", statsSortCount))
+			sessionKillList.Refresh()
+			
+			// Keep the top 10 by count, ordered by the chosen mode
+			sessionDeaths = stats.TopCounts(leaderboardCounts(sessionStatsData.Deaths, includeEnvironment), 10)
+			sortStatEntries(sessionDeaths, prefs.StringWithFallback("sortSessionDeaths", statsSortCount))
+			sessionDeathList.Refresh()
+
+			sessionIncaps = stats.TopCounts(sessionStatsData.Incaps, 10)
+			sessionIncapList.Refresh()
+			sessionIncapEmpty.Hidden = len(sessionIncaps) > 0
+			sessionIncapEmpty.Refresh()
+			sessionDamageLabel.SetText(formatDamageBreakdown(sessionStatsData.DamageTypes))
+			sessionCauseChart.set(groupDeathCauses(sessionStatsData.DamageTypes))
+			if tabs != nil {
+				badge := ""
+				if playerName != "" && playerName != "<none>" {
+					badge = formatTabBadge(sessionStatsData.TotalKills(), sessionStatsData.TotalDeaths())
+				}
+				feedTab.Text = "Feed" + badge
+				statsTab.Text = "Statistics" + badge
+				tabs.Refresh()
+			}
+
+			playtime, playtimeKills := stats.Playtime(pastSessions, playerName)
+			if activeSession != nil {
+				running := time.Since(activeSession.Start)
+				runningKills := sessionStatsData.TotalKills()
+				if sessionBasePlayer == playerName {
+					runningKills -= sessionBaseKills
+				}
+				sessionPlaytimeLabel.SetText(formatKillRate(runningKills, running))
+				playtime += running
+				playtimeKills += runningKills
+			} else {
+				sessionPlaytimeLabel.SetText("Not monitoring")
+			}
+			if playtime > 0 {
+				allTimePlaytimeLabel.SetText(formatKillRate(playtimeKills, playtime))
+			} else {
+				allTimePlaytimeLabel.SetText("No sessions recorded yet")
+			}
+		})
+	}// core and adapter
+	core := processor.New(nil, playerLabel)
+	h := &logHandlerAdapter{proc: core, outputRich: outputRich, window: window, allSegments: make([]feedEntry, 0)}
+	h.onStatsUpdate = updateStats
+	h.lineLimit = prefs.IntWithFallback("feedLineLimit", defaultFeedLineLimit)
+	processor.SetTimestampFormat(prefs.String("timestampFormat"))
+	debugLogErr := configureDebugLog(prefs.Bool("debug"), prefs.Bool("debugToFile"))
+	debugLog.Info("data folder", "dir", stats.Dir(), "portable", stats.Portable(), "override", prefs.String("dataDir") != "")
+	// Saved values were validated on entry; a bad one just keeps the default
+	_ = setRSIBaseURL(prefs.String("rsiBaseURL"))
+	// Warn about a bad saved zone once per run; later edits report their own errors
+	timeZoneErr := processor.SetTimestampLocation(prefs.String("timeZone"))
+	// A broken namerules.json override falls back to the built-in rules
+	nameRulesErr := ReloadNameRules()
+	// Likewise a broken weaponnames.json or vehiclenames.json keeps the built-in
+	// names; missing ones are written out first so there's a file to edit
+	mappingsErr := errors.Join(processor.SeedMappingFiles(), processor.ReloadMappings())
+	sounds := notify.NewSoundPlayer()
+	deathNotifyThrottle := notify.NewThrottle(30 * time.Second)
+
+	// Optional SQLite database recording every event and holding the all-time
+	// stats in place of the JSON files, which stay the default
+	var eventStore store.EventStore
+	setEventStoreEnabled := func(enabled bool) {
+		if eventStore != nil {
+			stats.SetBackend(nil)
+			eventStore.Close()
+			eventStore = nil
+		}
+		if !enabled {
+			return
+		}
+		db, err := sqlite.Open(filepath.Join(stats.Dir(), "events.db"))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to open event database: %w", err), window)
+			return
+		}
+		// Players only known from the JSON files keep their stats
+		if _, err := stats.CopyMissing(db); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to copy stats to the event database: %w", err), window)
+		}
+		stats.SetBackend(db)
+		eventStore = db
+	}
+	setEventStoreEnabled(prefs.Bool("useSQLite"))
+	// Optional JSON Lines event log for other tools, written as events happen
+	var eventLog *store.EventLog
+	setEventLogEnabled := func(enabled bool) {
+		if eventLog != nil {
+			eventLog.Close()
+			eventLog = nil
+		}
+		if enabled {
+			eventLog = store.NewEventLog(stats.Dir())
+		}
+	}
+	setEventLogEnabled(prefs.Bool("eventLog"))
+	recordEvent := func(r store.LogRecord) {
+		if eventLog == nil || h.backfilling {
+			return
+		}
+		if err := eventLog.Record(r); err != nil {
+			debugLog.Debug("failed to write event log", "err", err)
+		}
+	}
+	applyMetrics := func() {
+		if !prefs.Bool("metricsEnabled") {
+			metricsServer.Stop()
+			return
+		}
+		if err := metricsServer.Start(prefs.StringWithFallback("metricsHost", metrics.DefaultHost), prefs.IntWithFallback("metricsPort", 9813)); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to start metrics server: %w", err), window)
+		}
+	}
+	applyMetrics()
+	// Twitch chat bot posting kills; connection trouble only shows in the status line
+	twitchBot := notify.NewTwitchBot()
+	twitchStatus := widget.NewLabel("Twitch: Disconnected")
+	twitchBot.OnStatus = func(status string) {
+		fyne.Do(func() {
+			twitchStatus.SetText("Twitch: " + status)
+		})
+	}
+	applyTwitch := func() {
+		if !prefs.Bool("twitchEnabled") {
+			twitchBot.Stop()
+			return
+		}
+		err := twitchBot.Start(notify.TwitchConfig{
+			Channel:  prefs.String("twitchChannel"),
+			Username: prefs.String("twitchUsername"),
+			Token:    prefs.String("twitchToken"),
+		})
+		if err != nil {
+			twitchStatus.SetText("Twitch: " + err.Error())
+		}
+	}
+	applyTwitch()
+	// Milestone banners; a saved list that no longer parses falls back to the defaults
+	toast := newToastBanner()
+	killMilestones, err := stats.ParseMilestones(prefs.StringWithFallback("killMilestones", stats.DefaultKillMilestones))
+	if err != nil {
+		killMilestones, _ = stats.ParseMilestones(stats.DefaultKillMilestones)
+	}
+	core.OnKill = func(event processor.KillEvent) {
+		metricsServer.Update(core.PlayerName, core.Stats.TotalKills(), core.Stats.TotalDeaths())
+		if eventStore != nil {
+			eventStore.Record(store.Event{Player: event.Killer, Target: event.Victim, Weapon: event.Weapon, DamageType: event.DamageType, Timestamp: event.Timestamp, IsKill: true})
+		}
+		logType := store.LogKill
+		if event.Friendly {
+			logType = store.LogTeamKill
+		}
+		recordEvent(store.LogRecord{Type: logType, Actor: event.Killer, Target: event.Victim, Weapon: event.Weapon, DamageType: event.DamageType, Timestamp: event.Timestamp})
+		if !event.Friendly && !h.backfilling && prefs.Bool("twitchEnabled") {
+			twitchBot.Post(twitchKillMessage(prefs.StringWithFallback("twitchMessage", defaultTwitchKillMessage), event))
+		}
+		if event.Friendly || h.backfilling || !prefs.BoolWithFallback("milestoneBanners", true) {
+			return
+		}
+		total := core.Stats.TotalKills()
+		if m, ok := stats.CrossedMilestone(total-1, total, killMilestones); ok {
+			toast.Show(fmt.Sprintf("🏆 %d total kills!", m))
+		} else if event.NewBest && event.Streak >= prefs.IntWithFallback("minBestStreak", 5) {
+			toast.Show(fmt.Sprintf("🔥 New longest streak: %d kills!", event.Streak))
+		}
+	}
+	core.OnIncap = func(event processor.IncapEvent) {
+		recordEvent(store.LogRecord{Type: store.LogIncap, Actor: event.Player, Target: event.Target, Timestamp: event.Timestamp})
+	}
+	core.OnDeath = func(event processor.DeathEvent) {
+		metricsServer.Update(core.PlayerName, core.Stats.TotalKills(), core.Stats.TotalDeaths())
+		if eventStore != nil {
+			eventStore.Record(store.Event{Player: event.Player, Target: event.Killer, Weapon: event.Weapon, DamageType: event.DamageType, Timestamp: event.Timestamp})
+		}
+		recordEvent(store.LogRecord{Type: store.LogDeath, Actor: event.Killer, Target: event.Player, Weapon: event.Weapon, DamageType: event.DamageType, Timestamp: event.Timestamp})
+		if !prefs.Bool("notifyOnDeath") || h.backfilling || (prefs.Bool("quietFriends") && processor.IsFriend(event.Killer)) {
+			return
+		}
+		ok, skipped := deathNotifyThrottle.Allow(time.Now())
+		if !ok {
+			return
+		}
+		content := "You were killed by: " + event.Killer
+		if skipped > 0 {
+			content += fmt.Sprintf(" (+%d more deaths)", skipped)
+		}
+		a.SendNotification(fyne.NewNotification("Citizen Killstalker", content))
+	}
+	// Pop-out window mirroring the latest kills and deaths
+	mini := &miniFeed{limit: prefs.IntWithFallback("miniFeedLines", defaultMiniFeedLines)}
+	core.AppendOutput = func(line string, logTime ...time.Time) {
+		// Audio cues are keyed off the message prefix, before the timestamp is added.
+		// Lines replayed by a backfill stay silent.
+		// Team kills and deaths to friends stay silent when "quietFriends" is on
+		if !h.backfilling {
+			quiet := prefs.Bool("quietFriends")
+			teamKill := strings.HasPrefix(line, processor.TeamKillPrefix)
+			if (strings.HasPrefix(line, "You killed:") || (teamKill && !quiet)) && prefs.Bool("killSound") {
+				sounds.Play(notify.SoundKill)
+			} else if (strings.HasPrefix(line, "You were killed by:") || strings.HasPrefix(line, "You died")) && prefs.Bool("deathSound") &&
+				!(quiet && processor.IsFriend(deathLineKiller(line))) {
+				sounds.Play(notify.SoundDeath)
+			}
+		}
+
+		// Update player label when player name is detected
+		if core.PlayerName != "" && playerLabel != nil {
+			fyne.Do(func() {
+				playerLabel.SetText(core.PlayerName)
+			})
+		}
+
+		// A zero time means the log line had none; it happened just now
+		at := time.Now()
+		if len(logTime) > 0 && !logTime[0].IsZero() {
+			at = logTime[0]
+		}
+		fyne.Do(func() {
+			mini.add(line, at.In(processor.TimestampLocation()))
+		})
+
+		// Prepend the local timestamp to the log line (convert UTC to local)
+		if len(logTime) > 0 {
+			line = processor.FormatTimestamp(at) + " " + line
+		}
+		h.AppendOutputWithRaw(line, core.LastRawLogLine)
+	}
+
+	// --- SESSION LOG ---
+	// Each monitoring run is recorded to sessions.json when it is finalized
+	sessionsList := widget.NewList(
+		func() int { return len(pastSessions) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i < len(pastSessions) {
+				// Newest first
+				o.(*widget.Label).SetText(formatSessionRecord(pastSessions[len(pastSessions)-1-i]))
+			}
+		},
+	)
+	// finalizeSessionAs records the running session for player
+	finalizeSessionAs := func(player string) {
+		if activeSession == nil {
+			return
+		}
+		session := *activeSession
+		activeSession = nil
+		if player == "" {
+			return
+		}
+		current := stats.GetCurrentSession(player)
+		session.Player = player
+		session.End = time.Now()
+		session.Kills = current.TotalKills()
+		session.Deaths = current.TotalDeaths()
+		// Session stats accumulate per app run, so subtract what was there when this session began
+		if sessionBasePlayer == player {
+			session.Kills -= sessionBaseKills
+			session.Deaths -= sessionBaseDeaths
+		}
+		if err := stats.AppendSession(session); err == nil {
+			pastSessions = stats.LoadSessions()
+			sessionsList.Refresh()
+		}
+	}
+	finalizeSession := func() {
+		finalizeSessionAs(core.PlayerName)
+	}
+	startSession := func(logPath string) {
+		finalizeSession()
+		activeSession = &stats.Session{LogFile: logPath, Start: time.Now()}
+		sessionBasePlayer = core.PlayerName
+		current := stats.GetCurrentSession(core.PlayerName)
+		sessionBaseKills = current.TotalKills()
+		sessionBaseDeaths = current.TotalDeaths()
+	}
+	// startNewFeedFile saves the feed so far under oldName and sends later
+	// lines to a new file; set with the feed persistence below
+	startNewFeedFile := func(oldName string) {}
+	// A character switch in the log closes the old player's session and starts
+	// one for the new player, with a feed file of their own
+	core.FollowPlayerChanges = prefs.Bool("followPlayerChanges")
+	core.OnPlayerChange = func(oldName, newName string) {
+		startNewFeedFile(oldName)
+		if activeSession == nil {
+			return
+		}
+		logPath := activeSession.LogFile
+		finalizeSessionAs(oldName)
+		startSession(logPath)
+	}
+
+	// Config tab
+	logEntry := widget.NewEntry()
+	logEntry.SetPlaceHolder(watcher.LogPathPlaceholder())
+	if saved != "" {
+		logEntry.SetText(saved)
+	} else {
+		// First run: prefill the usual install location if the game is there
+		logEntry.SetText(watcher.DetectDefaultLogPath())
+	}
+	browseBtn := widget.NewButton("Browse…", func() {
+		dialog.ShowFileOpen(func(uri fyne.URIReadCloser, err error) {
+			if uri != nil && err == nil {
+				logEntry.SetText(uri.URI().Path())
+			}
+		}, window)
+	})
+	// startWatching tails the log, first replaying the existing lines when
+	// "Process entire log" is on. While it is on the read offset is remembered
+	// per log (identified by its first line) so a backfill of a log that was
+	// already watched only picks up where the previous run stopped instead of
+	// counting kills twice.
+	// stopWatching cancels the running watcher, if any, and watchingPath is
+	// the absolute path of the log it tails
+	var stopWatching context.CancelFunc
+	var watchingPath string
+	// watcherDone is closed once the running watcher has returned
+	var watcherDone chan struct{}
+	// drainWatcher stops the running watcher and waits for it to return. Lines
+	// it already handed to the UI thread are still queued, so work that must
+	// come after them has to be queued with fyne.Do too.
+	drainWatcher := func() {
+		if stopWatching == nil {
+			return
+		}
+		stopWatching()
+		<-watcherDone
+		stopWatching = nil
+	}
+	startWatching := func(path string) {
+		if abs, err := filepath.Abs(path); err == nil {
+			watchingPath = abs
+		} else {
+			watchingPath = path
+		}
+		drainWatcher()
+		ctx, cancel := context.WithCancel(context.Background())
+		stopWatching = cancel
+		logID := watcher.LogID(path)
+		opts := watcher.Options{
+			Backfill: prefs.Bool("backfillLog"),
+			OnBackfillDone: func() {
+				h.backfilling = false
+			},
+			Context: ctx,
+		}
+		// The offset only matters to the next backfill, so it is remembered
+		// only with backfill on, at most every offsetSaveInterval and once
+		// more when the watcher stops
+		var saveOffset func()
+		if opts.Backfill {
+			var savedAt time.Time
+			var lastID string
+			lastOffset := int64(-1)
+			saveOffset = func() {
+				if lastOffset < 0 {
+					return
+				}
+				prefs.SetString("watchedLogID", lastID)
+				prefs.SetInt("watchedLogOffset", int(lastOffset))
+				savedAt = time.Now()
+			}
+			opts.OnOffset = func(logID string, offset int64) {
+				lastID, lastOffset = logID, offset
+				if time.Since(savedAt) >= offsetSaveInterval {
+					saveOffset()
+				}
+			}
+		}
+		if logID != "" && logID == prefs.String("watchedLogID") {
+			opts.SkipTo = int64(prefs.Int("watchedLogOffset"))
+		}
+		h.backfilling = opts.Backfill
+		done := make(chan struct{})
+		watcherDone = done
+		go func() {
+			defer close(done)
+			watcher.WatchLogFileWithOptions(path, h, opts)
+			if saveOffset != nil {
+				saveOffset()
+			}
+		}()
+	}
+	// clearFeed empties the live feed once everything in it is saved; set with
+	// the feed persistence below
+	clearFeed := func() {}
+	// Start switches straight over when a log is already being watched: the
+	// old watcher stops, the feed is cleared if asked, and a different log
+	// detects its player afresh so stats go to whoever is playing in it
+	startBtn := widget.NewButton("Start Monitor", func() {
+		path := logEntry.Text
+		if _, err := os.Stat(path); err != nil {
+			dialog.ShowError(fmt.Errorf("log file not found: %s", path), window)
+			return
+		}
+		prefs.SetString("logPath", path)
+		switching := false
+		if watchingPath != "" {
+			abs, err := filepath.Abs(path)
+			switching = err != nil || abs != watchingPath
+		}
+		drainWatcher()
+		// Queued behind the old log's last lines, so none of them is taken for the new log's player
+		fyne.Do(func() {
+			// Events from the previous log would otherwise wait for a line from the new one
+			core.FlushPending()
+			// The flushed lines are appended by queued calls too; switch once
+			// they're in the old log's feed
+			fyne.Do(func() {
+				if switching {
+					core.ResetPlayerDetection()
+					if prefs.Bool("clearFeedOnSwitch") {
+						clearFeed()
+					}
+				}
+				core.AppendOutput(monitoringPrefix + path)
+				startSession(path)
+				startWatching(path)
+			})
+		})
+	})
+
+	clearLogsBtn := widget.NewButton("Clear All Old Logs", func() {
+		dialog.ShowConfirm("Delete All Logs?", "Are you sure you want to delete all saved logs and statistics? This cannot be undone.", func(confirm bool) {
+			if !confirm {
+				return
+			}
+			if err := clearLogsAndStats(getFeedDir()); err != nil {
+				dialog.ShowError(fmt.Errorf("some files couldn't be deleted: %w", err), window)
+				return
+			}
+			dialog.ShowInformation("Logs Cleared", "All logs and statistics have been deleted.", window)
+		}, window)
+	})
+
+	// Profile selector: pin a specific player instead of auto-detecting from the log
+	const autoDetectProfile = "Auto-detect"
+	applyProfile := func(name string) {
+		if name == "" {
+			core.Pinned = false
+			core.PlayerName = ""
+			playerLabel.SetText("<none>")
+			return
+		}
+		core.Pinned = true
+		core.SetPlayer(name)
+		playerLabel.SetText(name)
+		updateStats(name)
+	}
+	profileSelect := widget.NewSelect(nil, nil)
+	refreshProfiles := func() {
+		options := append([]string{autoDetectProfile}, stats.ListPlayers()...)
+		pinned := prefs.String("pinnedProfile")
+		if pinned != "" && !slices.Contains(options, pinned) {
+			options = append(options, pinned)
+		}
+		profileSelect.SetOptions(options)
+	}
+	refreshProfiles()
+	if pinned := prefs.String("pinnedProfile"); pinned != "" {
+		profileSelect.SetSelected(pinned)
+		applyProfile(pinned)
+	} else {
+		profileSelect.SetSelected(autoDetectProfile)
+	}
+	profileSelect.OnChanged = func(choice string) {
+		if choice == autoDetectProfile {
+			choice = ""
+		}
+		prefs.SetString("pinnedProfile", choice)
+		applyProfile(choice)
+	}
+	refreshProfilesBtn := widget.NewButton("Refresh", refreshProfiles)
+	followPlayerCheck := widget.NewCheck("Switch player when the log shows a different character", func(on bool) {
+		prefs.SetBool("followPlayerChanges", on)
+		core.FollowPlayerChanges = on
+	})
+	followPlayerCheck.SetChecked(prefs.Bool("followPlayerChanges"))
+	// Friends list: kills of these players count as team kills
+	processor.SetFriends(processor.ParseFriends(prefs.String("friends")))
+	friendsEntry := widget.NewMultiLineEntry()
+	friendsEntry.SetPlaceHolder("Friendly player handles, one per line or comma-separated")
+	friendsEntry.SetMinRowsVisible(3)
+	friendsEntry.SetText(prefs.String("friends"))
+	friendsEntry.OnChanged = afterTyping(func(text string) {
+		prefs.SetString("friends", text)
+		processor.SetFriends(processor.ParseFriends(text))
+	})
+	quietFriendsCheck := widget.NewCheck("No sounds or notifications for team kills or deaths to friends", func(on bool) {
+		prefs.SetBool("quietFriends", on)
+	})
+	quietFriendsCheck.SetChecked(prefs.Bool("quietFriends"))
+	// Collisions, crashes and falls are counted as Environment; this only decides whether the lists show them
+	environmentCheck := widget.NewCheck("Include environmental deaths (collisions, crashes, falls) in the kill and death lists", func(on bool) {
+		prefs.SetBool("leaderboardEnvironment", on)
+		if playerLabel.Text != "<none>" {
+			updateStats(playerLabel.Text)
+		}
+	})
+	environmentCheck.SetChecked(prefs.BoolWithFallback("leaderboardEnvironment", true))
+	// Ignore list: matching names are dropped from the feed and stats
+	ignoreErrLabel := widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	ignoreErrLabel.Importance = widget.DangerImportance
+	applyIgnoreList := func(text string) {
+		prefs.SetString("ignoreList", text)
+		if err := processor.SetIgnoreList(text); err != nil {
+			ignoreErrLabel.SetText(err.Error())
+		} else {
+			ignoreErrLabel.SetText("")
+		}
+	}
+	ignoreEntry := widget.NewMultiLineEntry()
+	ignoreEntry.SetPlaceHolder("One name per line; matches any name containing it. /regex/ for regular expressions")
+	ignoreEntry.SetMinRowsVisible(3)
+	ignoreEntry.SetText(prefs.String("ignoreList"))
+	applyIgnoreList(ignoreEntry.Text)
+	ignoreEntry.OnChanged = afterTyping(applyIgnoreList)
+
+	// Sound toggles (both off by default)
+	killSoundCheck := widget.NewCheck("Play sound on kill", func(on bool) {
+		prefs.SetBool("killSound", on)
+	})
+	killSoundCheck.SetChecked(prefs.Bool("killSound"))
+	deathSoundCheck := widget.NewCheck("Play sound on death", func(on bool) {
+		prefs.SetBool("deathSound", on)
+	})
+	deathSoundCheck.SetChecked(prefs.Bool("deathSound"))
+	notifyDeathCheck := widget.NewCheck("Desktop notification on death", func(on bool) {
+		prefs.SetBool("notifyOnDeath", on)
+	})
+	notifyDeathCheck.SetChecked(prefs.Bool("notifyOnDeath"))
+	milestoneCheck := widget.NewCheck("Show a banner for kill milestones and new longest streaks", func(on bool) {
+		prefs.SetBool("milestoneBanners", on)
+	})
+	milestoneCheck.SetChecked(prefs.BoolWithFallback("milestoneBanners", true))
+	// Twitch settings; the token is kept in the app preferences like the rest
+	twitchCheck := widget.NewCheck("Post kills to Twitch chat", func(on bool) {
+		prefs.SetBool("twitchEnabled", on)
+		applyTwitch()
+	})
+	twitchCheck.Checked = prefs.Bool("twitchEnabled")
+	newTwitchEntry := func(entry *widget.Entry, key, placeholder string) *widget.Entry {
+		entry.SetPlaceHolder(placeholder)
+		entry.SetText(prefs.String(key))
+		entry.OnSubmitted = func(text string) {
+			prefs.SetString(key, strings.TrimSpace(text))
+			applyTwitch()
+		}
+		return entry
+	}
+	twitchChannelEntry := newTwitchEntry(widget.NewEntry(), "twitchChannel", "Channel to post in")
+	twitchUserEntry := newTwitchEntry(widget.NewEntry(), "twitchUsername", "Account posting the messages")
+	twitchTokenEntry := newTwitchEntry(widget.NewPasswordEntry(), "twitchToken", "oauth:…")
+	twitchMessageEntry := widget.NewEntry()
+	twitchMessageEntry.SetText(prefs.StringWithFallback("twitchMessage", defaultTwitchKillMessage))
+	twitchMessageEntry.OnSubmitted = func(text string) {
+		if strings.TrimSpace(text) == "" {
+			text = defaultTwitchKillMessage
+			twitchMessageEntry.SetText(text)
+		}
+		prefs.SetString("twitchMessage", text)
+	}
+	milestonesEntry := widget.NewEntry()
+	milestonesEntry.SetText(prefs.StringWithFallback("killMilestones", stats.DefaultKillMilestones))
+	milestonesEntry.OnSubmitted = func(text string) {
+		parsed, err := stats.ParseMilestones(text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		killMilestones = parsed
+		prefs.SetString("killMilestones", text)
+	}
+	minStreakEntry := widget.NewEntry()
+	minStreakEntry.SetText(strconv.Itoa(prefs.IntWithFallback("minBestStreak", 5)))
+	minStreakEntry.OnSubmitted = func(text string) {
+		n, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || n < 1 {
+			dialog.ShowError(fmt.Errorf("invalid streak length: %s", text), window)
+			return
+		}
+		prefs.SetInt("minBestStreak", n)
+	}
+	// Duplicate line window in milliseconds, 0 to keep every line
+	core.DedupWindow = time.Duration(prefs.IntWithFallback("dedupWindowMs", int(processor.DefaultDedupWindow/time.Millisecond))) * time.Millisecond
+	dedupEntry := widget.NewEntry()
+	dedupEntry.SetText(strconv.Itoa(int(core.DedupWindow / time.Millisecond)))
+	dedupEntry.OnSubmitted = func(text string) {
+		ms, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || ms < 0 || ms > 60000 {
+			dialog.ShowError(fmt.Errorf("invalid duplicate window: %s (0–60000 ms)", text), window)
+			return
+		}
+		prefs.SetInt("dedupWindowMs", ms)
+		core.DedupWindow = time.Duration(ms) * time.Millisecond
+	}
+	// Incap-then-kill window in seconds, 0 to count the incap and the kill separately
+	core.IncapKillWindow = time.Duration(prefs.IntWithFallback("incapKillWindowSec", int(processor.DefaultIncapKillWindow/time.Second))) * time.Second
+	incapWindowEntry := widget.NewEntry()
+	incapWindowEntry.SetText(strconv.Itoa(int(core.IncapKillWindow / time.Second)))
+	incapWindowEntry.OnSubmitted = func(text string) {
+		sec, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || sec < 0 || sec > 600 {
+			dialog.ShowError(fmt.Errorf("invalid incap window: %s (0–600 seconds)", text), window)
+			return
+		}
+		prefs.SetInt("incapKillWindowSec", sec)
+		core.IncapKillWindow = time.Duration(sec) * time.Second
+	}
+	backfillCheck := widget.NewCheck("Process entire log on start (backfill feed and stats)", func(on bool) {
+		prefs.SetBool("backfillLog", on)
+	})
+	backfillCheck.SetChecked(prefs.Bool("backfillLog"))
+	clearOnSwitchCheck := widget.NewCheck("Clear the feed when Start switches to another log", func(on bool) {
+		prefs.SetBool("clearFeedOnSwitch", on)
+	})
+	clearOnSwitchCheck.SetChecked(prefs.Bool("clearFeedOnSwitch"))
+	feedLimitEntry := widget.NewEntry()
+	feedLimitEntry.SetText(strconv.Itoa(h.displayLimit()))
+	feedLimitEntry.OnSubmitted = func(text string) {
+		limit, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || limit < minFeedLineLimit || limit > maxFeedLineLimit {
+			dialog.ShowError(fmt.Errorf("feed line limit must be between %d and %d", minFeedLineLimit, maxFeedLineLimit), window)
+			feedLimitEntry.SetText(strconv.Itoa(h.displayLimit()))
+			return
+		}
+		prefs.SetInt("feedLineLimit", limit)
+		h.setLineLimit(limit)
+	}
+	// Timestamp format: presets plus a custom Go layout
+	timestampPresetNames := map[string]string{
+		"24-hour":         processor.TimestampFormat24h,
+		"12-hour (AM/PM)": processor.TimestampFormat12h,
+		"Date only":       processor.TimestampFormatDateOnly,
+	}
+	customTimestampEntry := widget.NewEntry()
+	customTimestampEntry.SetPlaceHolder("Go layout, e.g. 02.01.2006 15:04")
+	customTimestampEntry.OnSubmitted = func(layout string) {
+		layout = strings.TrimSpace(layout)
+		if !processor.ValidTimestampFormat(layout) {
+			dialog.ShowError(fmt.Errorf("invalid timestamp format: %q", layout), window)
+			return
+		}
+		prefs.SetString("timestampFormat", layout)
+		processor.SetTimestampFormat(layout)
+	}
+	timestampSelect := widget.NewSelect([]string{"24-hour", "12-hour (AM/PM)", "Date only", "Custom"}, func(name string) {
+		if layout, ok := timestampPresetNames[name]; ok {
+			customTimestampEntry.Disable()
+			prefs.SetString("timestampFormat", layout)
+			processor.SetTimestampFormat(layout)
+			return
+		}
+		customTimestampEntry.Enable()
+	})
+	currentTimestampFormat := processor.TimestampFormat()
+	timestampSelect.SetSelected("Custom")
+	customTimestampEntry.SetText(currentTimestampFormat)
+	for name, layout := range timestampPresetNames {
+		if layout == currentTimestampFormat {
+			timestampSelect.SetSelected(name)
+		}
+	}
+	timeZoneEntry := widget.NewEntry()
+	timeZoneEntry.SetPlaceHolder("Local (or e.g. Europe/Berlin)")
+	timeZoneEntry.SetText(prefs.String("timeZone"))
+	timeZoneEntry.OnSubmitted = func(name string) {
+		name = strings.TrimSpace(name)
+		if err := processor.SetTimestampLocation(name); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		prefs.SetString("timeZone", name)
+		// The kills-by-hour chart follows the display zone
+		updateStats(playerLabel.Text)
+	}
+	rsiBaseEntry := widget.NewEntry()
+	rsiBaseEntry.SetPlaceHolder(defaultRSIBaseURL)
+	rsiBaseEntry.SetText(prefs.String("rsiBaseURL"))
+	rsiBaseEntry.OnSubmitted = func(text string) {
+		if err := setRSIBaseURL(text); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		prefs.SetString("rsiBaseURL", strings.TrimSpace(text))
+		updateStats(playerLabel.Text)
+	}
+	// Link behaviour, for players who don't want a browser popping up mid-fight
+	if mode := prefs.String("linkMode"); mode == linkModeCopy || mode == linkModeOff {
+		linkMode = mode
+	}
+	// redrawHistory re-renders the feed open in History; set once that tab is built
+	redrawHistory := func() {}
+	onLinkCopied = func(link string) {
+		toast.Show("Copied " + link)
+	}
+	linkModeSelect := widget.NewSelect([]string{linkModeOpen, linkModeCopy, linkModeOff}, func(mode string) {
+		if mode == linkMode {
+			return
+		}
+		linkMode = mode
+		prefs.SetString("linkMode", mode)
+		h.refreshFeedDisplay()
+		redrawHistory()
+	})
+	linkModeSelect.SetSelected(linkMode)
+	// Informational lines are still stored and saved, only left off screen
+	h.hideInfo = prefs.Bool("hideInfoLines")
+	hideInfoCheck := widget.NewCheck("Hide informational lines (detected player, monitored log)", func(on bool) {
+		prefs.SetBool("hideInfoLines", on)
+		h.setHideInfo(on)
+	})
+	hideInfoCheck.SetChecked(h.hideInfo)
+	debugFileCheck := widget.NewCheck("Also write debug output to debug.log in the app data folder", func(on bool) {
+		prefs.SetBool("debugToFile", on)
+		if err := configureDebugLog(prefs.Bool("debug"), on); err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+	debugFileCheck.Checked = prefs.Bool("debugToFile")
+	debugCheck := widget.NewCheck("Verbose debug logging", func(on bool) {
+		prefs.SetBool("debug", on)
+		if on {
+			debugFileCheck.Enable()
+		} else {
+			debugFileCheck.Disable()
+		}
+		if err := configureDebugLog(on, prefs.Bool("debugToFile")); err != nil {
+			dialog.ShowError(err, window)
+		}
+	})
+	debugCheck.Checked = prefs.Bool("debug")
+	if !debugCheck.Checked {
+		debugFileCheck.Disable()
+	}
+	// Reload namerules.json after editing it, without restarting
+	reloadNameRulesBtn := widget.NewButton("Reload Name Rules", func() {
+		if err := ReloadNameRules(); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Name Rules", "Player name rules reloaded from "+nameRulesPath()+" (built-in rules if the file doesn't exist).", window)
+	})
+	// Reload weaponnames.json and vehiclenames.json after a patch renames things
+	reloadMappingsBtn := widget.NewButton("Reload Mappings", func() {
+		if err := processor.ReloadMappings(); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Mappings", "Weapon and vehicle names reloaded from "+processor.WeaponNamesPath()+" and "+processor.VehicleNamesPath()+".", window)
+	})
+	// Sample data, to see the feed and session stats working before playing.
+	// It's played as its own made-up player and never saved as all-time stats
+	loadSampleBtn := widget.NewButton("Load Sample", func() {
+		h.playSample()
+		updateStats(samplePlayer)
+	})
+	// Stats backup and import, for moving a record to another PC
+	backupBtn := widget.NewButtonWithIcon("Backup Stats", theme.DocumentSaveIcon(), func() {
+		includeSessions := widget.NewCheck("Include session history", nil)
+		includeSessions.SetChecked(true)
+		dialog.ShowCustomConfirm("Backup Stats", "Choose File", "Cancel", includeSessions, func(ok bool) {
+			if !ok {
+				return
+			}
+			backup := stats.CreateBackup(includeSessions.Checked)
+			save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+				if err != nil || w == nil {
+					return
+				}
+				defer w.Close()
+				if err := stats.WriteBackup(w, backup); err != nil {
+					dialog.ShowError(fmt.Errorf("failed to write backup: %w", err), window)
+					return
+				}
+				dialog.ShowInformation("Backup Complete", fmt.Sprintf("Saved stats for %d players.", len(backup.Players)), window)
+			}, window)
+			save.SetFileName("citizenmon-stats-" + time.Now().Format("2006-01-02_150405") + ".json")
+			save.Show()
+		}, window)
+	})
+	// Show the data folder in the file manager; without one, at least tell the user where it is
+	openFeedsFolder := func() {
+		dir := getFeedDir()
+		if err := fsutil.OpenFolder(dir); err != nil {
+			debugLog.Debug("failed to open feeds folder", "dir", dir, "err", err)
+			pathEntry := widget.NewEntry()
+			pathEntry.SetText(dir)
+			dialog.ShowCustom("Feeds Folder", "Close", container.NewVBox(
+				widget.NewLabel("Couldn't open a file manager. Feeds and stats are saved in:"),
+				pathEntry,
+			), window)
+		}
+	}
+	openFolderBtn := widget.NewButtonWithIcon("Open Feeds Folder", theme.FolderOpenIcon(), openFeedsFolder)
+	// Feeds saved before JSON Lines still open; this converts them for good
+	migrateFeedsBtn := widget.NewButton("Migrate Old Feeds", func() {
+		migrated, err := migrateFeeds(stats.Dir())
+		if err != nil {
+			dialog.ShowError(err, window)
+		} else {
+			dialog.ShowInformation("Migrate Old Feeds", fmt.Sprintf("Converted %d feeds to JSON Lines.", migrated), window)
+		}
+		refreshHistory()
+	})
+	// Combined ranking of every player with saved stats, e.g. for an org
+	leaderboardBtn := widget.NewButtonWithIcon("Export Leaderboard", theme.DocumentSaveIcon(), func() {
+		showLeaderboardExport(window)
+	})
+	importBtn := widget.NewButtonWithIcon("Import Stats", theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			backup, err := stats.ReadBackup(r)
+			r.Close()
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			modeRadio := widget.NewRadioGroup([]string{"Merge (add counts)", "Replace local stats"}, nil)
+			modeRadio.SetSelected("Merge (add counts)")
+			modeRadio.Required = true
+			summary := widget.NewLabel(fmt.Sprintf("Backup from %s with %d players and %d sessions.",
+				processor.FormatTimestamp(backup.Created), len(backup.Players), len(backup.Sessions)))
+			dialog.ShowCustomConfirm("Import Stats", "Import", "Cancel", container.NewVBox(summary, modeRadio), func(ok bool) {
+				if !ok {
+					return
+				}
+				mode := stats.ImportMerge
+				if modeRadio.Selected == "Replace local stats" {
+					mode = stats.ImportReplace
+				}
+				if err := stats.ImportBackup(backup, mode); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				// Reload the in-memory copy so the next kill doesn't overwrite the import
+				if core.PlayerName != "" {
+					core.Stats = stats.Load(core.PlayerName)
+				}
+				pastSessions = stats.LoadSessions()
+				sessionsList.Refresh()
+				refreshProfiles()
+				updateStats(playerLabel.Text)
+				dialog.ShowInformation("Import Complete", fmt.Sprintf("Imported stats for %d players.", len(backup.Players)), window)
+			}, window)
+		}, window)
+	})
+	// Data folder override; files can be moved along when it changes
+	dataDirEntry := widget.NewEntry()
+	dataDirEntry.SetPlaceHolder(stats.DefaultDir())
+	dataDirEntry.SetText(prefs.String("dataDir"))
+	applyDataDir := func(dir string) {
+		dir = strings.TrimSpace(dir)
+		oldDir, newDir := stats.Dir(), dir
+		if newDir == "" {
+			newDir = stats.DefaultDir()
+		}
+		if filepath.Clean(oldDir) == filepath.Clean(newDir) {
+			prefs.SetString("dataDir", dir)
+			return
+		}
+		if err := os.MkdirAll(newDir, 0755); err != nil {
+			dialog.ShowError(fmt.Errorf("can't use data folder: %w", err), window)
+			dataDirEntry.SetText(prefs.String("dataDir"))
+			return
+		}
+		dialog.ShowConfirm("Change Data Folder",
+			"Move existing stats, feeds and sessions from\n"+oldDir+"\nto\n"+newDir+"?\n\nChoose No to start with the files already in the new folder.",
+			func(move bool) {
+				// Release open files so they can be moved
+				setEventStoreEnabled(false)
+				setEventLogEnabled(false)
+				configureDebugLog(false, false)
+				var moveErr error
+				if move {
+					_, moveErr = stats.MoveFiles(oldDir, newDir)
+				}
+				prefs.SetString("dataDir", dir)
+				stats.SetDir(dir)
+				setEventStoreEnabled(prefs.Bool("useSQLite"))
+				setEventLogEnabled(prefs.Bool("eventLog"))
+				if err := configureDebugLog(prefs.Bool("debug"), prefs.Bool("debugToFile")); err != nil && moveErr == nil {
+					moveErr = err
+				}
+				_ = ReloadNameRules()
+				_ = processor.SeedMappingFiles()
+				_ = processor.ReloadMappings()
+				if core.PlayerName != "" {
+					core.Stats = stats.Load(core.PlayerName)
+				}
+				pastSessions = stats.LoadSessions()
+				sessionsList.Refresh()
+				refreshProfiles()
+				refreshHistory()
+				updateStats(playerLabel.Text)
+				if moveErr != nil {
+					dialog.ShowError(moveErr, window)
+				}
+			}, window)
+	}
+	dataDirEntry.OnSubmitted = applyDataDir
+	dataDirBrowseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			dataDirEntry.SetText(uri.Path())
+			applyDataDir(uri.Path())
+		}, window)
+	})
+	metricsPortEntry := widget.NewEntry()
+	metricsPortEntry.SetText(strconv.Itoa(prefs.IntWithFallback("metricsPort", 9813)))
+	metricsPortEntry.OnSubmitted = func(text string) {
+		port, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || port < 1 || port > 65535 {
+			dialog.ShowError(fmt.Errorf("invalid port: %s", text), window)
+			return
+		}
+		prefs.SetInt("metricsPort", port)
+		applyMetrics()
+	}
+	metricsCheck := widget.NewCheck("Serve Prometheus metrics on port (press Enter to apply):", func(on bool) {
+		prefs.SetBool("metricsEnabled", on)
+		applyMetrics()
+	})
+	metricsCheck.Checked = prefs.Bool("metricsEnabled")
+	// Old feeds beyond this many are deleted at startup
+	keepFeedsEntry := widget.NewEntry()
+	keepFeedsEntry.SetText(strconv.Itoa(prefs.Int("keepFeeds")))
+	keepFeedsEntry.OnSubmitted = func(text string) {
+		keep, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || keep < 0 {
+			dialog.ShowError(fmt.Errorf("the number of feeds to keep must be 0 or more"), window)
+			keepFeedsEntry.SetText(strconv.Itoa(prefs.Int("keepFeeds")))
+			return
+		}
+		prefs.SetInt("keepFeeds", keep)
+	}
+	eventLogCheck := widget.NewCheck("Write kills, deaths and incaps to events-YYYY-MM-DD.jsonl for other tools", func(on bool) {
+		prefs.SetBool("eventLog", on)
+		setEventLogEnabled(on)
+	})
+	eventLogCheck.Checked = prefs.Bool("eventLog")
+	sqliteCheck := widget.NewCheck("Keep stats and record kills/deaths in SQLite (events.db) instead of JSON files", nil)
+	sqliteCheck.SetChecked(prefs.Bool("useSQLite"))
+	sqliteCheck.OnChanged = func(on bool) {
+		// The active player's stats are saved to the old backend and read back from the new one
+		if core.PlayerName != "" {
+			stats.Save(core.PlayerName, core.Stats)
+		}
+		prefs.SetBool("useSQLite", on)
+		setEventStoreEnabled(on)
+		if core.PlayerName != "" {
+			core.Stats = stats.Load(core.PlayerName)
+		}
+		refreshProfiles()
+		updateStats(playerLabel.Text)
+	}
+
+	configTab := container.NewTabItem("Config", container.NewVBox(
+		widget.NewLabel("Log File Path (or drop Game.log onto the window):"),
+		container.NewBorder(nil, nil, nil, browseBtn, logEntry),
+		startBtn,
+		clearLogsBtn,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Profile", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, refreshProfilesBtn, profileSelect),
+		followPlayerCheck,
+		widget.NewLabel("Friends (marked ★ in the feed; kills of these players are counted as team kills):"),
+		friendsEntry,
+		quietFriendsCheck,
+		environmentCheck,
+		widget.NewLabel("Ignore list (kills, deaths and incaps involving these names are dropped, even NPCs):"),
+		ignoreEntry,
+		ignoreErrLabel,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Feed", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, widget.NewLabel("Max feed lines (100–50000, press Enter to apply):"), nil, feedLimitEntry),
+		backfillCheck,
+		clearOnSwitchCheck,
+		container.NewBorder(nil, nil, widget.NewLabel("Skip identical repeated log lines within ms (0 = off, press Enter to apply):"), nil, dedupEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Count an incap finished off within seconds as just the kill (0 = count both, press Enter to apply):"), nil, incapWindowEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Timestamp format:"), nil,
+			container.NewGridWithColumns(2, timestampSelect, customTimestampEntry)),
+		container.NewBorder(nil, nil, widget.NewLabel("Time zone (press Enter to apply):"), nil, timeZoneEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("RSI site URL for player links (press Enter to apply):"), nil, rsiBaseEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Clicking a player or org link:"), nil, linkModeSelect),
+		hideInfoCheck,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Notifications", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		killSoundCheck,
+		deathSoundCheck,
+		notifyDeathCheck,
+		milestoneCheck,
+		container.NewBorder(nil, nil, nil, twitchStatus, twitchCheck),
+		container.NewGridWithColumns(3,
+			container.NewBorder(nil, nil, widget.NewLabel("Channel:"), nil, twitchChannelEntry),
+			container.NewBorder(nil, nil, widget.NewLabel("Username:"), nil, twitchUserEntry),
+			container.NewBorder(nil, nil, widget.NewLabel("OAuth token:"), nil, twitchTokenEntry)),
+		container.NewBorder(nil, nil, widget.NewLabel("Kill message, {player} {victim} {weapon} (press Enter to apply):"), nil, twitchMessageEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Kill milestones (comma-separated, press Enter to apply):"), nil, milestonesEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Only announce streak records of at least (press Enter to apply):"), nil, minStreakEntry),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Storage", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, widget.NewLabel("Data folder (blank for default, press Enter to apply):"), dataDirBrowseBtn, dataDirEntry),
+		sqliteCheck,
+		eventLogCheck,
+		container.NewBorder(nil, nil, widget.NewLabel("Keep the newest feeds, deleting older ones at startup (0 = keep all, press Enter to apply):"), nil, keepFeedsEntry),
+		container.NewHBox(backupBtn, importBtn, leaderboardBtn, migrateFeedsBtn, openFolderBtn),
+		container.NewBorder(nil, nil, metricsCheck, nil, metricsPortEntry),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Diagnostics", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		debugCheck,
+		debugFileCheck,
+		container.NewHBox(reloadNameRulesBtn, reloadMappingsBtn, loadSampleBtn))) // Feed tab
+	// Single toggle button for raw logs
+	var rawToggleBtn *widget.Button
+	updateRawToggleBtn := func() {
+		if ShowRawLogLines {
+			rawToggleBtn.SetText("Disable Raw Logs")
+		} else {
+			rawToggleBtn.SetText("Enable Raw Logs")
+		}
+	}
+
+	rawToggleBtn = widget.NewButton("Enable Raw Logs", func() {
+		ShowRawLogLines = !ShowRawLogLines
+		updateRawToggleBtn()
+		h.refreshFeedDisplay()
+	})
+	// Pause button: stop rendering new lines while reading, without losing them
+	var pauseBtn *widget.Button
+	pauseBtn = widget.NewButton("Pause Feed", func() {
+		h.setPaused(!h.paused)
+		if h.paused {
+			pauseBtn.SetText("Resume Feed")
+		} else {
+			pauseBtn.SetText("Pause Feed")
+		}
+	})
+	h.onBuffered = func(pending int) {
+		pauseBtn.SetText(fmt.Sprintf("Resume Feed (%d new)", pending))
+	}
+
+	// Filter bar: one checkbox per event category, all shown by default
+	newFilterCheck := func(label string, category feedCategory) *widget.Check {
+		check := widget.NewCheck(label, nil)
+		check.Checked = true
+		check.OnChanged = func(show bool) {
+			h.setFeedFilter(category, show)
+		}
+		return check
+	}
+	// saveFeedNow saves the feed straight away and names the file; also set below
+	saveFeedNow := func() {}
+	saveFeedBtn := widget.NewButtonWithIcon("Save Feed Now", theme.DocumentSaveIcon(), func() { saveFeedNow() })
+	clearFeedBtn := widget.NewButtonWithIcon("Clear Feed", theme.ContentClearIcon(), func() {
+		dialog.ShowConfirm("Clear Feed?", "Clear the lines on screen? Stats aren't affected and the lines stay in the saved feed file.", func(ok bool) {
+			if ok {
+				clearFeed()
+			}
+		}, window)
+	})
+	copyAllBtn := widget.NewButtonWithIcon("Copy All", theme.ContentCopyIcon(), func() {
+		a.Clipboard().SetContent(h.visibleText())
+	})
+	copyLastKillBtn := widget.NewButtonWithIcon("Copy Last Kill", theme.ContentCopyIcon(), func() {
+		text := h.lastKillText()
+		if text == "" {
+			dialog.ShowInformation("No Kills Yet", "There is no kill in the feed to copy.", window)
+			return
+		}
+		a.Clipboard().SetContent(text)
+	})
+	// Quick way to add someone from the recent kill/death lines to the ignore list
+	ignoreNameBtn := widget.NewButtonWithIcon("Ignore Name…", theme.VisibilityOffIcon(), func() {
+		names := h.recentKillNames(20)
+		if len(names) == 0 {
+			dialog.ShowInformation("Ignore Name", "There are no recent kills or deaths to pick a name from.", window)
+			return
+		}
+		nameSelect := widget.NewSelect(names, nil)
+		nameSelect.SetSelected(names[0])
+		dialog.ShowCustomConfirm("Ignore Name", "Ignore", "Cancel", container.NewVBox(
+			widget.NewLabel("Drop this name's kills and deaths from now on:"),
+			nameSelect,
+		), func(ok bool) {
+			if !ok || nameSelect.Selected == "" {
+				return
+			}
+			text := strings.TrimRight(ignoreEntry.Text, "\n")
+			if text != "" {
+				text += "\n"
+			}
+			ignoreEntry.SetText(text + nameSelect.Selected)
+			applyIgnoreList(ignoreEntry.Text)
+		}, window)
+	})
+	popOutBtn := widget.NewButtonWithIcon("Pop Out Feed", theme.ViewFullScreenIcon(), func() {
+		mini.open(a)
+	})
+	// Shard the player is on, and a filter that appears once lines from more than one shard exist
+	const allShards = "All shards"
+	shardLabel := widget.NewLabel("Shard: –")
+	var seenShards []string
+	shardSelect := widget.NewSelect([]string{allShards}, func(choice string) {
+		h.shardFilter = ""
+		if choice != allShards {
+			h.shardFilter = choice
+		}
+		h.refreshFeedDisplay()
+	})
+	shardSelect.SetSelected(allShards)
+	shardSelect.Hide()
+	core.OnShardChange = func(shard, mode string) {
+		switch {
+		case shard != "":
+			shardLabel.SetText(fmt.Sprintf("Shard: %s (%s)", shard, mode))
+		case mode != "":
+			shardLabel.SetText("Mode: " + mode)
+		default:
+			shardLabel.SetText("Shard: –")
+		}
+		if shard == "" || slices.Contains(seenShards, shard) {
+			return
+		}
+		seenShards = append(seenShards, shard)
+		shardSelect.SetOptions(append([]string{allShards}, seenShards...))
+		if len(seenShards) > 1 {
+			shardSelect.Show()
+		}
+	}
+	filterBar := container.NewHBox(
+		widget.NewLabel("Show:"),
+		newFilterCheck("Kills", feedCategoryKill),
+		newFilterCheck("Deaths", feedCategoryDeath),
+		newFilterCheck("Vehicles", feedCategoryVehicle),
+		newFilterCheck("Other", feedCategoryOther),
+		shardSelect,
+	)
+	scroll := container.NewScroll(outputRich)
+	scroll.SetMinSize(fyne.NewSize(0, 400)) // Ensure scroll area is visible
+	// Only follow new lines while already at the bottom; otherwise offer a jump button
+	jumpBtn := widget.NewButtonWithIcon("Jump to latest", theme.MoveDownIcon(), h.jumpToLatest)
+	jumpBtn.Importance = widget.HighImportance
+	jumpBtn.Hide()
+	// Grouped view folds lines within the aggregation window into expandable blocks
+	groupBox := container.NewVBox()
+	groupScroll := container.NewVScroll(groupBox)
+	groupScroll.Hide()
+	h.scroll = scroll
+	h.groupScroll = groupScroll
+	h.groupBox = groupBox
+	h.jumpBtn = jumpBtn
+	hideJumpAtBottom := func(fyne.Position) {
+		if h.atBottom() {
+			jumpBtn.Hide()
+		}
+	}
+	scroll.OnScrolled = hideJumpAtBottom
+	groupScroll.OnScrolled = hideJumpAtBottom
+	feedArea := container.NewStack(scroll, groupScroll, container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), jumpBtn), nil, nil))
+	groupCheck := widget.NewCheck("Group related events", func(on bool) {
+		prefs.SetBool("groupFeed", on)
+		h.setGrouped(on)
+	})
+	groupCheck.SetChecked(prefs.Bool("groupFeed"))
+	// The last few kills stay in view beside the feed, however busy it gets
+	h.recent = newRecentKills()
+	// Status line showing how far the watcher has read, for telling a quiet
+	// game from a stuck watcher
+	watchStatus := widget.NewLabel("Not monitoring")
+	watchStatus.Importance = widget.LowImportance
+	watchStatus.Truncation = fyne.TextTruncateEllipsis
+	h.onProgress = func(p watcher.Progress) {
+		if text := progressText(p, time.Now()); watchStatus.Text != text {
+			watchStatus.SetText(text)
+		}
+	}
+	feedTab = container.NewTabItem("Feed", container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Current Player:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			container.NewHBox(playerLabel, layout.NewSpacer(), shardLabel),
+			widget.NewLabel("Feed:"),
+			container.NewHBox(rawToggleBtn, pauseBtn, clearFeedBtn, saveFeedBtn, copyAllBtn, copyLastKillBtn, ignoreNameBtn, popOutBtn, groupCheck),
+			filterBar,
+		), watchStatus, nil, h.recent.object(), feedArea))
+	// Statistics tab with All-time and Current sections
+	allTimeKillScroll := container.NewScroll(allTimeKillList)
+	allTimeDeathScroll := container.NewScroll(allTimeDeathList)
+	allTimeKillScroll.SetMinSize(fyne.NewSize(0, 350))
+	allTimeDeathScroll.SetMinSize(fyne.NewSize(0, 350))
+
+	sessionKillScroll := container.NewScroll(sessionKillList)
+	sessionDeathScroll := container.NewScroll(sessionDeathList)
+	sessionKillScroll.SetMinSize(fyne.NewSize(0, 350))
+	sessionDeathScroll.SetMinSize(fyne.NewSize(0, 350))	// Reset button for all-time stats
+	// Undo for the last all-time reset this session, from the backup ResetAllTime keeps
+	undoResetPlayer := ""
+	var undoResetButton *widget.Button
+	undoResetButton = widget.NewButtonWithIcon("Undo Reset", theme.ContentUndoIcon(), func() {
+		player := undoResetPlayer
+		restored, err := stats.UndoReset(player)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to restore stats: %w", err), window)
+			return
+		}
+		// The processor keeps its own copy for the active player
+		if core.PlayerName == player {
+			core.Stats = restored
+		}
+		undoResetPlayer = ""
+		undoResetButton.SetText("Undo Reset")
+		undoResetButton.Disable()
+		updateStats(playerLabel.Text)
+		dialog.ShowInformation("Reset Undone", "All-time statistics for "+player+" have been restored.", window)
+	})
+	undoResetButton.Disable()
+	resetButton := widget.NewButtonWithIcon("Reset All-time Stats", nil, func() {
+		if playerLabel.Text == "<none>" {
+			dialog.ShowInformation("No Player", "Please select a player first.", window)
+			return
+		}
+		
+		// Create custom confirmation dialog
+		confirmLabel := widget.NewRichTextFromMarkdown("## Reset All-time Statistics\n\nAre you sure you want to reset all-time statistics for **" + playerLabel.Text + "**?\n\n*You can undo this with Undo Reset until you close the app.*")
+		
+		yesBtn := widget.NewButtonWithIcon("Yes, Reset", nil, func() {})
+		noBtn := widget.NewButtonWithIcon("No, Cancel", nil, func() {})
+		
+		yesBtn.Importance = widget.DangerImportance
+		noBtn.Importance = widget.MediumImportance
+		
+		content := container.NewVBox(
+			confirmLabel,
+			container.NewBorder(nil, nil, nil, nil, 
+				container.NewHBox(yesBtn, noBtn),
+			),
+		)
+		
+		confirmDialog := dialog.NewCustom("Confirm Reset", "Close", content, window)
+		
+		yesBtn.OnTapped = func() {
+			confirmDialog.Hide()
+			player := playerLabel.Text
+			if err := stats.ResetAllTime(player); err != nil {
+				dialog.ShowError(fmt.Errorf("failed to reset stats: %w", err), window)
+				return
+			}
+			if core.PlayerName == player {
+				core.Stats = stats.New()
+			}
+			undoResetPlayer = player
+			undoResetButton.SetText("Undo Reset (" + player + ")")
+			undoResetButton.Enable()
+			updateStats(player)
+			dialog.ShowInformation("Reset Complete", "All-time statistics have been reset.", window)
+		}
+		
+		noBtn.OnTapped = func() {
+			confirmDialog.Hide()
+		}
+		
+		confirmDialog.Show()
+	})
+	resetButton.Importance = widget.HighImportance
+	// Rebuild the all-time stats from the player's saved feeds, e.g. after the
+	// stats file was lost or damaged
+	recomputeButton := widget.NewButtonWithIcon("Recompute Stats from History", theme.ViewRefreshIcon(), func() {
+		player := playerLabel.Text
+		if player == "<none>" {
+			dialog.ShowInformation("No Player", "Please select a player first.", window)
+			return
+		}
+		paths, err := playerFeedFiles(getFeedDir(), player)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if len(paths) == 0 {
+			dialog.ShowInformation("No Saved Feeds", "There are no saved feeds for "+player+" to recompute from.", window)
+			return
+		}
+		dialog.ShowConfirm("Recompute Stats from History",
+			fmt.Sprintf("Replace the all-time statistics for %s with counts from %d saved feeds?\n\n"+
+				"Kills, deaths, incaps and team kills are recounted from the feed text. Damage types and\n"+
+				"players seen aren't in the feeds and are kept as they are. NPCs are counted under the\n"+
+				"names the feed showed, and kills missing from the saved feeds are lost.", player, len(paths)),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				recomputed, read, err := recomputeStats(getFeedDir(), player, stats.Load(player), core.IncapKillWindow)
+				if read == 0 && err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				if saveErr := stats.Save(player, recomputed); saveErr != nil {
+					dialog.ShowError(fmt.Errorf("failed to save stats: %w", saveErr), window)
+					return
+				}
+				// The processor keeps its own copy for the active player
+				if core.PlayerName == player {
+					core.Stats = recomputed
+				}
+				updateStats(player)
+				msg := fmt.Sprintf("Recounted %d kills and %d deaths from %d feeds.", recomputed.TotalKills(), recomputed.TotalDeaths(), read)
+				if err != nil {
+					msg += "\n\n" + err.Error()
+				}
+				dialog.ShowInformation("Stats Recomputed", msg, window)
+			}, window)
+	})
+	// clearSession empties a player's current session, in the processor's copy too
+	clearSession := func(player string) {
+		stats.ResetPlayerSession(player)
+		// The processor keeps its own copy for the active player
+		if core.PlayerName == player {
+			core.SessionStats = stats.New()
+		}
+		// Counting restarts from zero, so the running session's baseline does too
+		if sessionBasePlayer == player {
+			sessionBaseKills, sessionBaseDeaths = 0, 0
+		}
+		h.recent.clear()
+	}
+	// Reset button for the selected player's current session; other profiles keep theirs
+	resetSessionButton := widget.NewButtonWithIcon("Reset Session Stats", nil, func() {
+		player := playerLabel.Text
+		if player == "<none>" {
+			dialog.ShowInformation("No Player", "Please select a player first.", window)
+			return
+		}
+		dialog.ShowConfirm("Reset Session Statistics",
+			"Clear the current session statistics for "+player+"?\nAll-time statistics are not affected.",
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				clearSession(player)
+				updateStats(player)
+			}, window)
+	})
+	resetSessionButton.Importance = widget.HighImportance
+	// Add the session onto all-time, for when all-time is missing it, e.g. it
+	// was reset or restored mid-session. The session is cleared afterwards so a
+	// second commit can't count it twice.
+	commitSessionButton := widget.NewButtonWithIcon("Commit Session to All-time", theme.UploadIcon(), func() {
+		player := playerLabel.Text
+		if player == "<none>" {
+			dialog.ShowInformation("No Player", "Please select a player first.", window)
+			return
+		}
+		session := stats.GetCurrentSession(player)
+		dialog.ShowConfirm("Commit Session to All-time",
+			fmt.Sprintf("Add this session's %d kills and %d deaths for %s to the all-time statistics and clear the session?\n\n"+
+				"Kills are normally counted in both already. Only commit when all-time is missing them,\n"+
+				"e.g. after it was reset or restored during this session.", session.TotalKills(), session.TotalDeaths(), player),
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				allTime, err := stats.CommitSession(player)
+				if err != nil {
+					dialog.ShowError(fmt.Errorf("failed to save stats: %w", err), window)
+					return
+				}
+				if core.PlayerName == player {
+					core.Stats = allTime
+				}
+				clearSession(player)
+				updateStats(player)
+			}, window)
+	})
+	// Per-list sort selector, persisted under its own preference key
+	newSortSelect := func(prefKey string) *widget.Select {
+		sel := widget.NewSelect(statsSortModes, nil)
+		sel.SetSelected(prefs.StringWithFallback(prefKey, statsSortCount))
+		sel.OnChanged = func(mode string) {
+			prefs.SetString(prefKey, mode)
+			updateStats(playerLabel.Text)
+		}
+		return sel
+	}
+	// All-time stats tab with enhanced styling
+	allTimeKillCard := container.NewBorder(
+		container.NewVBox(
+			widget.NewCard("", "", container.NewVBox(
+				widget.NewLabelWithStyle("🎯 Top 10 Victims (You Killed)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+				newSortSelect("sortAllTimeKills"),
+				widget.NewSeparator(),
+			)),
+		), nil, nil, nil, allTimeKillScroll)
+	
+	allTimeDeathCard := container.NewBorder(
+		container.NewVBox(
+			widget.NewCard("", "", container.NewVBox(
+				widget.NewLabelWithStyle("💀 Top 10 Killers (Killed You)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+				newSortSelect("sortAllTimeDeaths"),
+				widget.NewSeparator(),
+			)),
+		), nil, nil, nil, allTimeDeathScroll)
+
+	allTimeTab := container.NewTabItem("📊 All-time", container.NewVBox(
+		container.NewGridWithColumns(2,
+			widget.NewCard("", "😈 Nemesis", nemesisLabel),
+			widget.NewCard("", "🎯 Favorite Victim", victimLabel),
+		),
+		widget.NewCard("", "🤝 Team Kills", teamKillsLabel),
+		widget.NewCard("", "⏱️ Playtime", allTimePlaytimeLabel),
+		widget.NewCard("", "💥 How You Die", allTimeCauseChart.rows),
+		widget.NewCard("", "🕒 Kills by Hour", killHourChart.object()),
+		widget.NewCard("All-Time Statistics", "Persistent stats saved across sessions", 
+			container.NewGridWithColumns(2, allTimeKillCard, allTimeDeathCard)),
+		container.NewBorder(nil, nil, nil, nil,
+			container.NewHBox(
+				widget.NewSeparator(),
+				resetButton,
+				undoResetButton,
+				recomputeButton,
+				widget.NewSeparator(),
+			)),
+	))
+	// Current session stats tab with enhanced styling
+	sessionKillCard := container.NewBorder(
+		container.NewVBox(
+			widget.NewCard("", "", container.NewVBox(
+				widget.NewLabelWithStyle("🎯 Session Victims (You Killed)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+				newSortSelect("sortSessionKills"),
+				widget.NewSeparator(),
+			)),
+		), nil, nil, nil, sessionKillScroll)
+	
+	sessionDeathCard := container.NewBorder(
+		container.NewVBox(
+			widget.NewCard("", "", container.NewVBox(
+				widget.NewLabelWithStyle("💀 Session Killers (Killed You)", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+				newSortSelect("sortSessionDeaths"),
+				widget.NewSeparator(),
+			)),
+		), nil, nil, nil, sessionDeathScroll)
+
+	currentTab := container.NewTabItem("⚡ Current Session", container.NewVBox(
+		widget.NewCard("Current Session Statistics", "Stats reset when the app restarts",
+			container.NewGridWithColumns(2, sessionKillCard, sessionDeathCard)),
+		widget.NewCard("", "⏱️ Playtime", sessionPlaytimeLabel),
+		widget.NewCard("", "💥 Deaths by Damage Type", container.NewVBox(sessionCauseChart.rows, sessionDamageLabel)),
+		container.NewBorder(nil, nil, nil, nil,
+			container.NewHBox(
+				widget.NewSeparator(),
+				resetSessionButton,
+				commitSessionButton,
+				widget.NewSeparator(),
+			)),
+	))
+
+	// Incapacitation tab: all-time and session side by side
+	allTimeIncapScroll := container.NewScroll(allTimeIncapList)
+	sessionIncapScroll := container.NewScroll(sessionIncapList)
+	allTimeIncapScroll.SetMinSize(fyne.NewSize(0, 350))
+	sessionIncapScroll.SetMinSize(fyne.NewSize(0, 350))
+	incapsTab := container.NewTabItem("🩹 Incaps", widget.NewCard("Incapacitations", "Players you downed without killing",
+		container.NewGridWithColumns(2,
+			container.NewBorder(
+				widget.NewLabelWithStyle("📊 All-time", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), nil, nil, nil,
+				container.NewStack(allTimeIncapScroll, allTimeIncapEmpty)),
+			container.NewBorder(
+				widget.NewLabelWithStyle("⚡ Session", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}), nil, nil, nil,
+				container.NewStack(sessionIncapScroll, sessionIncapEmpty)),
+		)))
+
+	mostSeenScroll := container.NewScroll(mostSeenList)
+	mostSeenScroll.SetMinSize(fyne.NewSize(0, 350))
+	mostSeenTab := container.NewTabItem("👀 Most Seen", widget.NewCard("Most Seen Players", "Players who spawned near you, whether or not anyone died",
+		container.NewStack(mostSeenScroll, mostSeenEmpty)))
+
+	// Create nested tabs for statistics
+	sessionsScroll := container.NewScroll(sessionsList)
+	sessionsScroll.SetMinSize(fyne.NewSize(0, 350))
+	sessionsTab := container.NewTabItem("🕒 Sessions", widget.NewCard("Past Sessions", "Recorded when monitoring restarts or the app closes",
+		sessionsScroll))
+	statsTabs := container.NewAppTabs(allTimeTab, currentTab, incapsTab, mostSeenTab, sessionsTab)
+	statsTab = container.NewTabItem("Statistics", statsTabs)
+
+	// --- FEED PERSISTENCE ---
+	// Helper to get feed save directory
+	getFeedDir = func() string {
+		return stats.Dir()
+	}
+
+	// Helper to generate a new, unused feed filename
+	getFeedFilename := func(playerName string) string {
+		if playerName == "" {
+			playerName = "Unknown"
+		}
+		// Sanitize playerName for filename: spaces and characters invalid on Windows become underscores
+		playerName = fsutil.SanitizeFilename(playerName)
+		date := time.Now().Format("2006-01-02")
+		return fsutil.UniquePath(getFeedDir(), playerName+"_"+date, feedExt)
+	}
+
+	// Save feed to file (JSON Lines, one line per feed line). The file is
+	// picked on the first save; later saves only append the new lines.
+	// A resumed legacy .json feed is rewritten whole instead.
+	var feedSavePath string
+	feedSavedLines := 0
+	saveFeed := func() error {
+		var err error
+		switch {
+		case feedSavePath == "":
+			feedSavePath = getFeedFilename(core.PlayerName)
+			err = writeFeedFile(feedSavePath, h.savedLines())
+		case strings.HasSuffix(feedSavePath, feedExt):
+			err = appendFeedFile(feedSavePath, h.linesSince(feedSavedLines))
+		default:
+			err = writeFeedFile(feedSavePath, h.savedLines())
+		}
+		if err != nil {
+			debugLog.Debug("failed to save feed", "path", feedSavePath, "err", err)
+			return err
+		}
+		feedSavedLines = h.stored
+		return nil
+	}
+	// Saves only append what's new, so a line must reach the file before it's
+	// dropped from the buffer; save early if the oldest buffered line is unsaved
+	h.beforeDrop = func() {
+		if h.stored-feedSavedLines >= len(h.allSegments) {
+			saveFeed()
+		}
+	}
+	saveFeedNow = func() {
+		if err := saveFeed(); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save feed: %w", err), window)
+			return
+		}
+		dialog.ShowInformation("Feed Saved", "The feed so far is saved in "+filepath.Base(feedSavePath)+
+			". Later lines are added to the same file.", window)
+	}
+	startNewFeedFile = func(oldName string) {
+		if feedSavePath == "" && h.stored != feedSavedLines {
+			feedSavePath = getFeedFilename(oldName)
+		}
+		if err := saveFeed(); err != nil {
+			// Keep appending to the old file rather than lose the unsaved lines
+			return
+		}
+		feedSavePath = ""
+		feedSavedLines = h.stored
+	}
+	clearFeed = func() {
+		// Lines that couldn't be saved would be lost for good
+		if err := saveFeed(); err != nil {
+			dialog.ShowError(fmt.Errorf("feed not cleared, saving it failed: %w", err), window)
+			return
+		}
+		// A legacy .json feed is rewritten whole on every save, which would now
+		// lose the cleared lines, so the rest of the session goes to a new file
+		if !strings.HasSuffix(feedSavePath, feedExt) {
+			feedSavePath = ""
+			feedSavedLines = h.stored
+		}
+		h.clearLines()
+	}
+	// Autosave while lines keep arriving so a crash doesn't lose the session's feed
+	go func() {
+		ticker := time.NewTicker(feedAutosaveInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(func() {
+				if h.stored != feedSavedLines {
+					saveFeed()
+				}
+			})
+		}
+	}()
+	// Once the log goes quiet (the game closed, say) nothing else would push the
+	// last events out of the aggregation window, so flush them after a pause
+	go func() {
+		ticker := time.NewTicker(core.EventAggregator.TimeWindow)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(func() {
+				core.FlushIdle(2 * core.EventAggregator.TimeWindow)
+			})
+		}
+	}()
+	// offerFeedResume asks whether to continue today's feed file for the
+	// expected player instead of starting a new one
+	offerFeedResume := func() {
+		player := prefs.String("pinnedProfile")
+		if player == "" && len(pastSessions) > 0 {
+			player = pastSessions[len(pastSessions)-1].Player
+		}
+		if player == "" {
+			return
+		}
+		path := latestFeedFile(getFeedDir(), fsutil.SanitizeFilename(player), time.Now().Format("2006-01-02"))
+		if path == "" {
+			return
+		}
+		dialog.ShowConfirm("Resume Today's Feed",
+			fmt.Sprintf("Continue %s from earlier today? New lines are added to it.\nChoose No to start a new feed file.", filepath.Base(path)),
+			func(resume bool) {
+				if !resume {
+					return
+				}
+				lines, err := loadFeedFile(path)
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				// Lines from this run go after the restored ones, even if an autosave
+				// already put them in a new file
+				live := h.stored
+				h.restoreLines(lines)
+				if feedSavePath != "" && feedSavePath != path {
+					os.Remove(feedSavePath)
+				}
+				feedSavePath = path
+				feedSavedLines = h.stored - live
+			}, window)
+	}
+
+	// shutdown stops monitoring and writes everything out before the window
+	// closes. Safe to call more than once; only the first call does the work.
+	var shutdownOnce sync.Once
+	shutdown := func() {
+		shutdownOnce.Do(func() {
+			// Waits for the watcher so its last read offset is saved
+			drainWatcher()
+			// Queued behind the watcher's last lines, so their events are flushed too
+			fyne.Do(func() {
+				// Events still inside the aggregation window would otherwise never reach the feed
+				core.FlushPending()
+				// The flushed lines and those of the watcher's last lines are
+				// appended by queued calls as well; save once they're stored
+				fyne.Do(func() {
+					if core.PlayerName != "" {
+						stats.Save(core.PlayerName, core.Stats)
+					}
+					finalizeSession()
+					saveFeed()
+					setEventStoreEnabled(false)
+					setEventLogEnabled(false)
+					metricsServer.Stop()
+					twitchBot.Stop()
+					mini.close()
+					window.Close()
+				})
+			})
+		})
+	}
+	window.SetCloseIntercept(shutdown)
+
+	// --- FEED HISTORY TAB (DROPDOWN + EXPANDED VIEW) ---
+	// Newest first
+	getFeedFiles := func() []string {
+		return listFeedFiles(getFeedDir())
+	}
+
+	var feedFiles []string
+	var selectedFeedPath string
+	var historyLines [][]FeedSegment // lines of the feed shown in historyRich
+	historyScroll := container.NewVScroll(historyRich)
+	// Notes for the shown feed, kept in a .notes.txt beside it and saved as they're typed
+	notesEntry := widget.NewMultiLineEntry()
+	notesEntry.SetPlaceHolder("Notes for this log, e.g. \"good dogfight around 21:40\" (saved as you type)")
+	notesEntry.Wrapping = fyne.TextWrapWord
+	notesEntry.SetMinRowsVisible(3)
+	notesEntry.Disable()
+	loadingNotes := false // set while the entry is filled from a file, so that isn't saved back
+	notesEntry.OnChanged = func(text string) {
+		if loadingNotes || selectedFeedPath == "" {
+			return
+		}
+		if err := saveFeedNotes(selectedFeedPath, text); err != nil {
+			debugLog.Debug("failed to save notes", "feed", selectedFeedPath, "err", err)
+		}
+	}
+	showNotes := func(path string) {
+		loadingNotes = true
+		defer func() { loadingNotes = false }()
+		if path == "" {
+			notesEntry.SetText("")
+			notesEntry.Disable()
+			return
+		}
+		notesEntry.SetText(loadFeedNotes(path))
+		notesEntry.Enable()
+	}
+	// Find box: matches in the shown feed are highlighted, with the current
+	// one stepped through by next/prev
+	findEntry := widget.NewEntry()
+	findEntry.SetPlaceHolder("Find in this log")
+	findCountLabel := widget.NewLabel("")
+	var findLines []int // line of each match, see highlightMatches
+	findCurrent := 0
+	redrawHistory = func() {
+		segments, matches := highlightMatches(linkSegments(renderFeedLines(historyLines)), strings.TrimSpace(findEntry.Text), findCurrent)
+		findLines = matches
+		historyRich.Segments = segments
+		historyRich.Refresh()
+		switch {
+		case strings.TrimSpace(findEntry.Text) == "":
+			findCountLabel.SetText("")
+		case len(matches) == 0:
+			findCountLabel.SetText("No matches")
+		default:
+			findCountLabel.SetText(fmt.Sprintf("%d of %d", findCurrent+1, len(matches)))
+		}
+	}
+	// showFindMatch makes match i current and scrolls to it, estimating the
+	// offset from its line like the time jump does
+	showFindMatch := func(i int) {
+		if len(findLines) == 0 {
+			return
+		}
+		findCurrent = (i + len(findLines)) % len(findLines)
+		redrawHistory()
+		offset := historyRich.MinSize().Height * float32(findLines[findCurrent]) / float32(max(len(historyLines), 1))
+		historyScroll.ScrollToOffset(fyne.NewPos(0, offset))
+	}
+	findEntry.OnChanged = func(string) {
+		findCurrent = 0
+		redrawHistory()
+		showFindMatch(0)
+	}
+	findEntry.OnSubmitted = func(string) { showFindMatch(findCurrent + 1) }
+	findPrevBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { showFindMatch(findCurrent - 1) })
+	findNextBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { showFindMatch(findCurrent + 1) })
+	// showHistoryFile loads a saved feed into the history view. Notes are only
+	// kept for feeds in the feeds folder, not beside files opened from elsewhere.
+	showHistoryFile := func(path string) {
+		lines, err := loadFeedFile(path)
+		if err != nil {
+			selectedFeedPath = ""
+			historyLines = nil
+			redrawHistory()
+			showNotes("")
+			dialog.ShowError(fmt.Errorf("failed to open feed: %w", err), window)
+			return
+		}
+		selectedFeedPath = path
+		historyLines = lines
+		findCurrent = 0
+		redrawHistory()
+		if filepath.Dir(path) == filepath.Clean(getFeedDir()) {
+			showNotes(path)
+		} else {
+			showNotes("")
+		}
+	}
+	// Jump to the first line at or after a time. RichText doesn't expose line
+	// positions, so the offset is estimated from the line's place in the feed.
+	jumpEntry := widget.NewEntry()
+	jumpEntry.SetPlaceHolder("Jump to time, e.g. 14:30 or 2024-05-01 14:30 (press Enter)")
+	jumpEntry.OnSubmitted = func(text string) {
+		if len(historyLines) == 0 {
+			return
+		}
+		ref, hasTimes := firstFeedTime(historyLines)
+		if !hasTimes {
+			dialog.ShowInformation("Jump to Time", "This history has no timestamps to jump by.", window)
+			return
+		}
+		target, ok := parseJumpTime(text, ref)
+		if !ok {
+			dialog.ShowError(fmt.Errorf("unrecognised time %q; use HH:MM, HH:MM:SS or YYYY-MM-DD HH:MM", text), window)
+			return
+		}
+		idx, found := firstLineAtOrAfter(historyLines, target)
+		if !found {
+			dialog.ShowInformation("Jump to Time", "No lines at or after "+processor.FormatTimestamp(target)+".", window)
+			return
+		}
+		offset := historyRich.MinSize().Height * float32(idx) / float32(len(historyLines))
+		historyScroll.ScrollToOffset(fyne.NewPos(0, offset))
+	}
+	feedSelectEntry := newAutocompleteEntry(window.Canvas())
+	feedSelectEntry.SetPlaceHolder("Search or select log...")
+
+	refreshFeedSelectEntry := func() {
+		feedFiles = getFeedFiles()
+		feedSelectEntry.SetOptions(feedFiles)
+		if len(feedFiles) > 0 {
+			feedSelectEntry.SetText(feedFiles[0])
+		}
+	}
+
+	// Typing filters the popup list; a feed loads once the text names one exactly
+	feedSelectEntry.OnTextChanged = func(text string) {
+		if text == "" {
+			selectedFeedPath = ""
+			historyLines = nil
+			redrawHistory()
+			showNotes("")
+			return
+		}
+		for _, f := range feedFiles {
+			if f == text {
+				showHistoryFile(filepath.Join(getFeedDir(), f))
+				return
+			}
+		}
+	}
+
+	refreshFeedSelectEntry()
+	refreshHistory = refreshFeedSelectEntry
+
+	historyTab := container.NewTabItem("History", container.NewBorder(
+		container.NewVBox(
+			widget.NewButton("Open Log", func() {
+				showLogBrowser(getFeedDir(), getFeedFiles, func(filename string) {
+					showHistoryFile(filepath.Join(getFeedDir(), filename))
+				}, refreshFeedSelectEntry)
+			}),
+			widget.NewButton("Convert Log", func() { convertLogToHistory(window) }),
+			widget.NewButton("Convert Folder", func() { convertLogFolder(window, refreshFeedSelectEntry) }),
+			widget.NewButton("Merge Logs", func() {
+				showMergeDialog(getFeedDir(), getFeedFiles(), window, func(filename string) {
+					refreshFeedSelectEntry()
+					feedSelectEntry.SetText(filename)
+				})
+			}),
+			widget.NewButtonWithIcon("Open Feeds Folder", theme.FolderOpenIcon(), openFeedsFolder),
+		),
+		container.NewVBox(notesEntry, container.NewGridWithColumns(2,
+			widget.NewButton("Export as HTML", func() {
+				if selectedFeedPath == "" {
+					dialog.ShowInformation("No Feed Selected", "Please select a feed to export.", window)
+					return
+				}
+				exportFeedToHTML(selectedFeedPath, window)
+			}),
+			widget.NewButton("Export as Markdown", func() {
+				if selectedFeedPath == "" {
+					dialog.ShowInformation("No Feed Selected", "Please select a feed to export.", window)
+					return
+				}
+				exportFeedToMarkdown(selectedFeedPath, window)
+			}),
+		)),
+		nil, nil,
+		container.NewBorder(container.NewGridWithColumns(2, jumpEntry,
+			container.NewBorder(nil, nil, nil, container.NewHBox(findCountLabel, findPrevBtn, findNextBtn), findEntry)),
+			nil, nil, nil, historyScroll),
+	))
+
+	// assemble tabs
+	tabs = container.NewAppTabs(
+		feedTab,
+		statsTab,
+		configTab,
+		historyTab,
+	)
+	// auto-start or config
+	if saved != "" {
+		// Ensure feed initializes with the game log and displays monitoring message
+		core.AppendOutput(monitoringPrefix + saved)
+		startSession(saved)
+		startWatching(saved)
+		tabs.Select(feedTab)
+	} else {
+		tabs.Select(configTab)
+	}
+
+	// Keyboard shortcuts, listed by F1
+	for i, tab := range tabs.Items {
+		addShortcut(window, fyne.KeyName(strconv.Itoa(i+1)), func() { tabs.Select(tab) })
+	}
+	addShortcut(window, fyne.KeyF, func() {
+		tabs.Select(historyTab)
+		window.Canvas().Focus(feedSelectEntry)
+	})
+	addShortcut(window, fyne.KeyL, rawToggleBtn.OnTapped)
+
+	// Dropped files: Game.log is monitored, saved feeds open in History and
+	// other logs are converted. Fyne only reports the drop itself, so the
+	// feedback is switching to the tab that handles the file.
+	window.SetOnDropped(func(_ fyne.Position, uris []fyne.URI) {
+		for _, uri := range uris {
+			path := uri.Path()
+			switch classifyDrop(path) {
+			case dropMonitor:
+				logEntry.SetText(path)
+				startBtn.OnTapped()
+				tabs.Select(feedTab)
+			case dropHistory:
+				tabs.Select(historyTab)
+				if filepath.Dir(path) == filepath.Clean(getFeedDir()) {
+					refreshFeedSelectEntry()
+					feedSelectEntry.SetText(filepath.Base(path))
+				} else {
+					showHistoryFile(path)
+				}
+			case dropConvert:
+				tabs.Select(historyTab)
+				runLogConversion(window, path)
+			default:
+				dialog.ShowInformation("Unsupported File", filepath.Base(path)+" isn't a game log or saved feed.\n"+
+					"Drop Game.log to monitor it, a .jsonl/.json feed to view it, or a .log, .txt or .gz log to convert it.", window)
+				continue
+			}
+			// One file at a time; the rest would only replace it
+			return
+		}
+	})
+	window.Canvas().SetOnTypedKey(func(event *fyne.KeyEvent) {
+		if event.Name == fyne.KeyF1 {
+			showShortcutHelp(window)
+		}
+	})
+
+	window.SetContent(container.NewStack(tabs, toast.overlay))
+	window.Resize(fyne.NewSize(800, 600))
+	if timeZoneErr != nil {
+		dialog.ShowError(timeZoneErr, window)
+	}
+	if debugLogErr != nil {
+		dialog.ShowError(debugLogErr, window)
+	}
+	if nameRulesErr != nil {
+		dialog.ShowError(nameRulesErr, window)
+	}
+	if mappingsErr != nil {
+		dialog.ShowError(mappingsErr, window)
+	}
+	if dataDirErr != nil {
+		dialog.ShowError(dataDirErr, window)
+	}
+	// Prune old feeds before offering to resume today's, which is among the newest
+	if removed, freed, err := pruneFeeds(getFeedDir(), prefs.Int("keepFeeds")); err != nil {
+		dialog.ShowError(fmt.Errorf("failed to delete some old feeds: %w", err), window)
+	} else if removed > 0 {
+		toast.Show(fmt.Sprintf("Deleted %d old feeds, freeing %s", removed, formatBytes(freed)))
+		refreshHistory()
+	}
+	offerFeedResume()
+	window.ShowAndRun()
+}
+
+// Serializable struct for a segment (text or hyperlink)
+type FeedSegment struct {
+	Type string `json:"type"` // "text" or "hyperlink"
+	Text string `json:"text"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Each log line is a slice of segments
+// The feed is a slice of lines
+
+// HTML escape function for feed export
+func htmlEscape(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(text, "&", "&amp;"), "<", "&lt;"), ">", "&gt;")
+}
+
+// Export feed to HTML file
+func exportFeedToHTML(feedPath string, parent fyne.Window) {
+	lines, err := loadFeedFile(feedPath)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to read feed: %w", err), parent)
+		return
+	}
+	html := renderFeedHTML(lines)
+	dialog.ShowFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if uc == nil || err != nil {
+			return
+		}
+		defer uc.Close()
+		uc.Write([]byte(html))
+	}, parent)
+}
+
+// renderFeedHTML renders saved feed lines as a standalone page, one <div> per
+// line with player names as links. Lines keep their order and timestamps.
+func renderFeedHTML(lines [][]FeedSegment) string {
+	var b strings.Builder
+	b.WriteString("<html><head><meta charset='utf-8'><title>CitizenMon Feed Export</title>" +
+		"<style>body{font-family:sans-serif;background:#1e1e1e;color:#ddd}" +
+		".line{font-family:monospace;padding:2px 0;white-space:pre-wrap}a{color:#6cb6ff}</style>" +
+		"</head><body>\n")
+	for _, line := range lines {
+		var text strings.Builder
+		for _, seg := range line {
+			switch seg.Type {
+			case "hyperlink":
+				text.WriteString("<a href=\"" + htmlEscape(strings.ReplaceAll(seg.URL, `"`, "%22")) + "\">" + htmlEscape(seg.Text) + "</a>")
+			default:
+				text.WriteString(htmlEscape(strings.ReplaceAll(seg.Text, "\n", "")))
+			}
+		}
+		if text.Len() == 0 {
+			continue
+		}
+		b.WriteString("<div class='line'>" + text.String() + "</div>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+// markdownEscaper backslash-escapes characters Markdown would otherwise interpret.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "{", `\{`, "}", `\}`,
+	"[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "#", `\#`, "|", `\|`,
+	"<", `\<`, ">", `\>`, "!", `\!`,
+)
+
+// markdownEscape escapes text for use in Markdown, including link text.
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// feedFileInfo splits a saved feed name like "Player_2024-01-01_2.jsonl" into
+// the player and the date; either may be empty if the name doesn't follow the pattern.
+func feedFileInfo(filename string) (player, date string) {
+	base := feedBaseName(filepath.Base(filename))
+	parts := strings.Split(base, "_")
+	for i := len(parts) - 1; i > 0; i-- {
+		if _, err := time.Parse("2006-01-02", parts[i]); err == nil {
+			return strings.Join(parts[:i], "_"), parts[i]
+		}
+	}
+	return base, ""
+}
+
+// renderFeedMarkdown renders saved feed lines as a Markdown list with a header.
+func renderFeedMarkdown(lines [][]FeedSegment, player, date string) string {
+	var b strings.Builder
+	b.WriteString("# Citizen Killstalker Feed")
+	if player != "" {
+		b.WriteString(" – " + markdownEscape(player))
+	}
+	b.WriteString("\n\n")
+	if date != "" {
+		b.WriteString("Date: " + date + "\n\n")
+	}
+	for _, line := range lines {
+		var text strings.Builder
+		for _, seg := range line {
+			switch seg.Type {
+			case "hyperlink":
+				// Parentheses and spaces would end the link target early
+				url := strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(seg.URL)
+				text.WriteString("[" + markdownEscape(seg.Text) + "](" + url + ")")
+			default:
+				text.WriteString(markdownEscape(strings.ReplaceAll(seg.Text, "\n", "")))
+			}
+		}
+		if strings.TrimSpace(text.String()) == "" {
+			continue
+		}
+		b.WriteString("- " + text.String() + "\n")
+	}
+	return b.String()
+}
+
+// exportFeedToMarkdown saves a feed as Markdown with linked player names.
+func exportFeedToMarkdown(feedPath string, parent fyne.Window) {
+	lines, err := loadFeedFile(feedPath)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to read feed: %w", err), parent)
+		return
+	}
+	player, date := feedFileInfo(feedPath)
+	markdown := renderFeedMarkdown(lines, player, date)
+	save := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if uc == nil || err != nil {
+			return
+		}
+		defer uc.Close()
+		if _, err := uc.Write([]byte(markdown)); err != nil {
+			dialog.ShowError(fmt.Errorf("failed to write Markdown: %w", err), parent)
+		}
+	}, parent)
+	save.SetFileName(feedBaseName(filepath.Base(feedPath)) + ".md")
+	save.Show()
+}
+
+// --- Convert Log to History ---
+func convertLogToHistory(parent fyne.Window) {
+	open := dialog.NewFileOpen(func(uc fyne.URIReadCloser, err error) {
+		if uc == nil || err != nil {
+			return
+		}
+		logPath := uc.URI().Path()
+		uc.Close()
+		runLogConversion(parent, logPath)
+	}, parent)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".log", ".txt", ".gz"}))
+	open.Show()
+}
+
+// runLogConversion converts a log on a background goroutine behind a progress
+// dialog. Its Cancel button stops the conversion without writing anything.
+func runLogConversion(parent fyne.Window, logPath string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bar := widget.NewProgressBar()
+	progress := dialog.NewCustom("Converting Log", "Cancel",
+		container.NewVBox(widget.NewLabel("Converting "+filepath.Base(logPath)+"…"), bar), parent)
+	progress.SetOnClosed(cancel)
+	progress.Show()
+	go func() {
+		jsonPath, err := convertLogFile(ctx, logPath, func(fraction float64) {
+			fyne.Do(func() { bar.SetValue(fraction) })
+		})
+		fyne.Do(func() {
+			progress.Hide()
+			switch {
+			case errors.Is(err, context.Canceled):
+				return
+			case err != nil:
+				dialog.ShowError(err, parent)
+				return
+			}
+			dialog.ShowInformation("Converted", "Log converted to history: "+jsonPath, parent)
+			if fyne.CurrentApp() != nil {
+				for _, w := range fyne.CurrentApp().Driver().AllWindows() {
+					if w.Title() == "Citizen Killstalker" {
+						w.Content().Refresh()
+					}
+				}
+			}
+		})
+	}()
+}
+
+// convertLogFolder converts every .log and .txt file (gzipped or not) in a chosen folder to
+// history, then reports how many were converted and skipped. onDone runs on
+// the UI thread afterwards so the feed list can pick up the new files.
+func convertLogFolder(parent fyne.Window, onDone func()) {
+	dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+		if err != nil || uri == nil {
+			return
+		}
+		entries, err := os.ReadDir(uri.Path())
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to read folder: %w", err), parent)
+			return
+		}
+		var logs []string
+		for _, entry := range entries {
+			if !entry.IsDir() && convert.IsConvertibleLog(entry.Name()) {
+				logs = append(logs, filepath.Join(uri.Path(), entry.Name()))
+			}
+		}
+		if len(logs) == 0 {
+			dialog.ShowInformation("Convert Folder", "No .log, .txt or .gz logs found in "+uri.Path(), parent)
+			return
+		}
+		runFolderConversion(parent, logs, onDone)
+	}, parent)
+}
+
+// runFolderConversion converts logs one after another on a background
+// goroutine behind a progress dialog. Cancel stops before the next file.
+func runFolderConversion(parent fyne.Window, logs []string, onDone func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bar := widget.NewProgressBar()
+	status := widget.NewLabel("")
+	progress := dialog.NewCustom("Converting Folder", "Cancel", container.NewVBox(status, bar), parent)
+	progress.SetOnClosed(cancel)
+	progress.Show()
+	go func() {
+		converted := 0
+		var skipped []string
+		for i, logPath := range logs {
+			if ctx.Err() != nil {
+				break
+			}
+			name := filepath.Base(logPath)
+			fyne.Do(func() { status.SetText(fmt.Sprintf("Converting %s (%d of %d)…", name, i+1, len(logs))) })
+			_, err := convertLogFile(ctx, logPath, func(fraction float64) {
+				fyne.Do(func() { bar.SetValue((float64(i) + fraction) / float64(len(logs))) })
+			})
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				skipped = append(skipped, name+": "+err.Error())
+			default:
+				converted++
+			}
+		}
+		cancelled := ctx.Err() != nil
+		fyne.Do(func() {
+			progress.Hide()
+			summary := fmt.Sprintf("%d converted, %d skipped", converted, len(skipped))
+			if cancelled {
+				summary += " (cancelled)"
+			}
+			if len(skipped) > 0 {
+				summary += "\n\n" + strings.Join(skipped, "\n")
+			}
+			dialog.ShowInformation("Convert Folder", summary, parent)
+			if onDone != nil {
+				onDone()
+			}
+		})
+	}()
+}
+
+// convertLogFile streams a game log into a saved history feed and returns its
+// path. onProgress receives the fraction of the file processed so far. The
+// conversion stops with ctx.Err() when ctx is cancelled.
+func convertLogFile(ctx context.Context, logPath string, onProgress func(fraction float64)) (string, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	total := max(info.Size(), 1)
+
+	// Archived logs are gzipped; the name underneath is used for player/date detection
+	base := filepath.Base(logPath)
+	gzipped := strings.EqualFold(filepath.Ext(base), ".gz")
+	if gzipped {
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	logDate := convert.LogDateFromName(base)
+	reader, _, err := convert.OpenLogReader(file, gzipped)
+	if err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	playerName := convert.DetectLogPlayer(reader, base)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	// Progress follows the bytes read from disk, which for a gzipped log are compressed
+	reader, counter, err := convert.OpenLogReader(file, gzipped)
+	if err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	lines, err := convert.ParseLog(ctx, reader, playerName, func(int64) {
+		onProgress(float64(counter.Count()) / float64(total))
+	})
+	if errors.Is(err, context.Canceled) {
+		return "", err
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read log: %w", err)
+	}
+	onProgress(1)
+	feed := make([][]FeedSegment, 0, len(lines))
+	for _, line := range lines {
+		// Only lines before the log's first timestamp have no time of their own
+		at := line.Time
+		if at.IsZero() {
+			at = time.Now()
+		}
+		feed = append(feed, CreateEnhancedSegments(line.Text, processor.FormatTimestamp(at), playerName))
+	}
+	if len(feed) == 0 {
+		feed = append(feed, []FeedSegment{
+			{Type: "text", Text: fmt.Sprintf("%s No kill/death messages found in this log for player %s.\n", processor.FormatTimestamp(time.Now()), playerName)},
+		})
+	}
+
+	// Save in the feeds dir, with Player_YYYY-MM-DD.jsonl naming
+	feedsDir := stats.Dir()
+	fileBase := fsutil.SanitizeFilename(playerName) + "_" + logDate
+	jsonPath := fsutil.UniquePath(feedsDir, fileBase, feedExt)
+	if err := writeFeedFile(jsonPath, feed); err != nil {
+		os.Remove(jsonPath)
+		return "", fmt.Errorf("failed to save history: %w", err)
+	}
+	return jsonPath, nil
+}
+
+// CreateEnhancedSegments creates segments with enhanced hyperlinking for log conversion
+func CreateEnhancedSegments(line, timestamp, playerName string) []FeedSegment {
+	var segments []FeedSegment
+	segments = append(segments, FeedSegment{Type: "text", Text: timestamp + " "})
+
+	// First, check if this is an already-processed message from the event aggregation system
+	// These should not be re-processed through the enhanced hyperlinking system
+	if strings.HasPrefix(line, "You were killed by: ") ||
+		strings.HasPrefix(line, "You died by ") ||
+		strings.HasPrefix(line, "You turned to a corpse") ||
+		strings.HasPrefix(line, "You were incapacitated") ||
+		strings.HasPrefix(line, "You bled out") ||
+		strings.HasPrefix(line, "You suffered a hard death") ||
+		strings.HasPrefix(line, "Mission Event: ") ||
+		strings.HasPrefix(line, "Vehicle ") && (strings.Contains(line, " was destroyed by ") || strings.Contains(line, " was disabled by ")) {
+		// Handle as plain text without further processing
+		segments = append(segments, FeedSegment{Type: "text", Text: line})
+		segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
+		return segments
+	}
+
+	// Handle different types of processor output lines with specific patterns
+
+	// 1. Corpse messages: "PlayerName has turned to a corpse"
+	if strings.Contains(line, "has turned to a corpse") {
+		parts := strings.SplitN(line, " has turned to a corpse", 2)
+		if len(parts) > 0 {
+			name := strings.TrimSpace(parts[0])
+			if isNPCName(name) {
+				segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(name)})
+			} else if isPetName(name) {
+				segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(name)})
+			} else if shouldHyperlinkName(name) {
+				segments = append(segments, FeedSegment{Type: "hyperlink", Text: name, URL: citizenURL(name)})
+			} else {
+				segments = append(segments, FeedSegment{Type: "text", Text: name})
+			}
+			segments = append(segments, FeedSegment{Type: "text", Text: " has turned to a corpse"})
+			segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
+			return segments
+		}
+	}
+
+	// 2. Kill messages: "You killed: PlayerName using weapon" or "You were killed by: PlayerName using weapon"
+	if strings.Contains(line, "You killed:") || strings.Contains(line, "You were killed by:") || strings.Contains(line, "You incapacitated:") {
+		return createKillMessageSegments(line, segments, playerName)
+	}
+
+	// 3. Vehicle destruction: "Vehicle Name was destroyed (or disabled) by PlayerName using weapon"
+	if strings.Contains(line, "Vehicle") && (strings.Contains(line, "destroyed") || strings.Contains(line, "disabled")) {
+		return createVehicleMessageSegments(line, segments)
+	}
+
+	// 4. Generic fallback for other lines - apply basic hyperlinking
+	words := strings.Fields(line)
+	byIdx := -1
+	for i, w := range words {
+		if strings.ToLower(w) == "by" && i < len(words)-1 {
+			byIdx = i + 1
+		}
+	}
+
+	for i, w := range words {
+		clean := strings.Trim(w, ",.?!;:'\"[]()")
+		shouldHyperlink := false
+
+		// Hyperlink player names in specific contexts
+		if len(clean) >= 3 {
+			if i == byIdx || // After "by"
+				strings.EqualFold(strings.ReplaceAll(clean, " ", "_"), strings.ReplaceAll(playerName, " ", "_")) { // Player's own name
+				shouldHyperlink = shouldHyperlinkName(clean)
+			}
+		}
+
+		if shouldHyperlink {
+			segments = append(segments, FeedSegment{Type: "hyperlink", Text: w, URL: citizenURL(clean)})
+		} else {
+			// Apply NPC/pet formatting even for non-hyperlinked names
+			displayText := w
+			if isNPCName(clean) {
+				displayText = strings.Replace(w, clean, formatNPCName(clean), 1)
+			} else if isPetName(clean) {
+				displayText = strings.Replace(w, clean, formatPetName(clean), 1)
+			}
+			segments = append(segments, FeedSegment{Type: "text", Text: displayText})
+		}
+
+		if i < len(words)-1 {
+			segments = append(segments, FeedSegment{Type: "text", Text: " "})
+		}
+	}
+
+	segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
+	return segments
+}
+
+// createKillMessageSegments handles kill/death/incap messages
+func createKillMessageSegments(line string, baseSegments []FeedSegment, playerName string) []FeedSegment {
+	segments := baseSegments
+
+	// Team kills keep their marker in front of the usual kill line
+	if rest, ok := strings.CutPrefix(line, processor.TeamKillPrefix); ok {
+		segments = append(segments, FeedSegment{Type: "text", Text: processor.TeamKillPrefix})
+		line = rest
+	}
+
+	// Parse different kill message patterns
+	if strings.HasPrefix(line, "You killed:") {
+		// "You killed: PlayerName using weapon"
+		parts := strings.SplitN(line, "You killed:", 2)
+		if len(parts) > 1 {
+			remaining := strings.TrimSpace(parts[1])
+			usingIdx := strings.Index(remaining, " using ")
+
+			segments = append(segments, FeedSegment{Type: "text", Text: "You killed: "})
+
+			if usingIdx > 0 {
+				// Has weapon info
+				victim, org := processor.StripOrgTag(remaining[:usingIdx])
+				weapon := strings.TrimSpace(remaining[usingIdx+7:])
+
+				// Apply enhanced formatting for NPCs and pets
+				if isNPCName(victim) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(victim)})
+				} else if isPetName(victim) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(victim)})
+				} else if shouldHyperlinkName(victim) {
+					segments = append(segments, FeedSegment{Type: "hyperlink", Text: victim, URL: citizenURL(victim)})
+				} else {
+					segments = append(segments, FeedSegment{Type: "text", Text: victim})
+				}
+				segments = appendOrgSegments(segments, org)
+				segments = append(segments, FeedSegment{Type: "text", Text: " using " + weapon})
+			} else {
+				// No weapon info
+				victim, org := processor.StripOrgTag(remaining)
+				if isNPCName(victim) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(victim)})
+				} else if isPetName(victim) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(victim)})
+				} else if shouldHyperlinkName(victim) {
+					segments = append(segments, FeedSegment{Type: "hyperlink", Text: victim, URL: citizenURL(victim)})
+				} else {
+					segments = append(segments, FeedSegment{Type: "text", Text: victim})
+				}
+				segments = appendOrgSegments(segments, org)
+			}
+		}
+	} else if strings.HasPrefix(line, "You were killed by:") {
+		// "You were killed by: PlayerName using weapon"
+		parts := strings.SplitN(line, "You were killed by:", 2)
+		if len(parts) > 1 {
+			remaining := strings.TrimSpace(parts[1])
+			usingIdx := strings.Index(remaining, " using ")
+
+			segments = append(segments, FeedSegment{Type: "text", Text: "You were killed by: "})
+
+			if usingIdx > 0 {
+				// Has weapon info
+				killer, org := processor.StripOrgTag(remaining[:usingIdx])
+				weapon := strings.TrimSpace(remaining[usingIdx+7:])
+
+				// Apply enhanced formatting for NPCs, pets, and suicide
+				if processor.IsSelfDeathKiller(killer) {
+					segments = append(segments, FeedSegment{Type: "text", Text: killer})
+				} else if isNPCName(killer) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
+				} else if isPetName(killer) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
+				} else if shouldHyperlinkName(killer) {
+					segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: citizenURL(killer)})
+				} else {
+					segments = append(segments, FeedSegment{Type: "text", Text: killer})
+				}
+				segments = appendOrgSegments(segments, org)
+				segments = append(segments, FeedSegment{Type: "text", Text: " using " + weapon})
+			} else {
+				// No weapon info
+				killer, org := processor.StripOrgTag(remaining)
+				if processor.IsSelfDeathKiller(killer) {
+					segments = append(segments, FeedSegment{Type: "text", Text: killer})
+				} else if isNPCName(killer) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
+				} else if isPetName(killer) {
+					segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
+				} else if shouldHyperlinkName(killer) {
+					segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: citizenURL(killer)})
+				} else {
+					segments = append(segments, FeedSegment{Type: "text", Text: killer})
+				}
+				segments = appendOrgSegments(segments, org)
+			}
+		}
+	} else if strings.HasPrefix(line, "You incapacitated:") {
+		// "You incapacitated: PlayerName"
+		parts := strings.SplitN(line, "You incapacitated:", 2)
+		if len(parts) > 1 {
+			victim := strings.TrimSpace(parts[1])
+			segments = append(segments, FeedSegment{Type: "text", Text: "You incapacitated: "})
+
+			if isNPCName(victim) {
+				segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(victim)})
+			} else if isPetName(victim) {
+				segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(victim)})
+			} else if shouldHyperlinkName(victim) {
+				segments = append(segments, FeedSegment{Type: "hyperlink", Text: victim, URL: citizenURL(victim)})
+			} else {
+				segments = append(segments, FeedSegment{Type: "text", Text: victim})
+			}
+		}
+	}
+
+	segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
+	return segments
+}
+
+// createVehicleMessageSegments handles vehicle destruction messages
+func createVehicleMessageSegments(line string, baseSegments []FeedSegment) []FeedSegment {
+	segments := baseSegments
+
+	// Parse vehicle destruction: "Vehicle Name was destroyed by PlayerName using weapon"
+	prefix, killer, weapon, ok := splitVehicleMessage(line)
+
+	if ok {
+		segments = append(segments, FeedSegment{Type: "text", Text: friendlyVehiclePrefix(prefix) + " by "})
+
+		// Apply enhanced formatting for NPCs, pets, and suicide
+		if processor.IsSelfDeathKiller(killer) {
+			segments = append(segments, FeedSegment{Type: "text", Text: killer})
+		} else if isNPCName(killer) {
+			segments = append(segments, FeedSegment{Type: "text", Text: formatNPCName(killer)})
+		} else if isPetName(killer) {
+			segments = append(segments, FeedSegment{Type: "text", Text: formatPetName(killer)})
+		} else if shouldHyperlinkName(killer) {
+			segments = append(segments, FeedSegment{Type: "hyperlink", Text: killer, URL: citizenURL(killer)})
+		} else {
+			segments = append(segments, FeedSegment{Type: "text", Text: killer})
+		}
+		// No weapon info means a collision or other environmental cause
+		if weapon != "" {
+			segments = append(segments, FeedSegment{Type: "text", Text: " using " + weapon})
+		}
+	} else {
+		// Fallback: just add as text
+		segments = append(segments, FeedSegment{Type: "text", Text: line})
+	}
+
+	segments = append(segments, FeedSegment{Type: "text", Text: "\n"})
+	return segments
+}
+
+// splitVehicleMessage splits "Vehicle <name> was destroyed by <killer> using <weapon>"
+// into the part before " by ", the killer and the optional weapon. The " by "
+// searched for is the one after " was ", so vehicle names containing "by" or
+// "using" don't shift the split.
+func splitVehicleMessage(line string) (prefix, killer, weapon string, ok bool) {
+	searchFrom := 0
+	if wasIdx := strings.Index(line, " was "); wasIdx >= 0 {
+		searchFrom = wasIdx
+	}
+	byIdx := strings.Index(line[searchFrom:], " by ")
+	if byIdx < 0 {
+		return "", "", "", false
+	}
+	byIdx += searchFrom
+	if byIdx == 0 {
+		return "", "", "", false
+	}
+	prefix = line[:byIdx]
+	killer = line[byIdx+len(" by "):]
+	if usingIdx := strings.Index(killer, " using "); usingIdx >= 0 {
+		weapon = strings.TrimSpace(killer[usingIdx+len(" using "):])
+		killer = killer[:usingIdx]
+	}
+	killer = strings.TrimSpace(killer)
+	if killer == "" {
+		return "", "", "", false
+	}
+	return prefix, killer, weapon, true
+}
+
+// friendlyVehiclePrefix rewrites the vehicle name in "Vehicle <name> was destroyed"
+// to its friendly form, leaving anything it can't parse untouched.
+func friendlyVehiclePrefix(text string) string {
+	rest, ok := strings.CutPrefix(text, "Vehicle ")
+	if !ok {
+		return text
+	}
+	wasIdx := strings.LastIndex(rest, " was ")
+	if wasIdx <= 0 {
+		return text
+	}
+	return "Vehicle " + processor.FriendlyVehicleName(rest[:wasIdx]) + rest[wasIdx:]
+}
+
+// --- LOG BROWSER WINDOW ---
+func showLogBrowser(feedDir string, getFeedFiles func() []string, onSelect func(filename string), onFilesChanged func()) {
+	logs := getFeedFiles()
+	filtered := make([]string, len(logs))
+	copy(filtered, logs)
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search logs...")
+
+	var browserWin fyne.Window
+	var list *widget.List
+
+	applyFilter := func() {
+		q := strings.ToLower(searchEntry.Text)
+		filtered = filtered[:0]
+		for _, f := range logs {
+			if strings.Contains(strings.ToLower(f), q) {
+				filtered = append(filtered, f)
+			}
+		}
+		list.UnselectAll()
+		list.Refresh()
+	}
+	reload := func() {
+		logs = getFeedFiles()
+		applyFilter()
+		if onFilesChanged != nil {
+			onFilesChanged()
+		}
+	}
+
+	deleteLog := func(filename string) {
+		dialog.ShowConfirm("Delete Log?", "Delete "+filename+"? This cannot be undone.", func(confirm bool) {
+			if !confirm {
+				return
+			}
+			for _, path := range pairedFeedFiles(feedDir, filename) {
+				if err := os.Remove(path); err != nil {
+					dialog.ShowError(fmt.Errorf("failed to delete %s: %w", filepath.Base(path), err), browserWin)
+				}
+			}
+			reload()
+		}, browserWin)
+	}
+
+	renameLog := func(filename string) {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(feedBaseName(filename))
+		dialog.ShowForm("Rename Log", "Rename", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("New name", nameEntry),
+		}, func(confirm bool) {
+			if !confirm {
+				return
+			}
+			newBase := feedBaseName(strings.TrimSpace(nameEntry.Text))
+			if err := validateFeedName(newBase); err != nil {
+				dialog.ShowError(err, browserWin)
+				return
+			}
+			oldBase := feedBaseName(filename)
+			if newBase == oldBase {
+				return
+			}
+			// Check every paired file for a collision before touching anything
+			var renames [][2]string
+			for _, oldPath := range pairedFeedFiles(feedDir, filename) {
+				suffix := strings.TrimPrefix(filepath.Base(oldPath), oldBase)
+				newPath := filepath.Join(feedDir, newBase+suffix)
+				if _, err := os.Stat(newPath); err == nil {
+					dialog.ShowError(fmt.Errorf("a file named %s already exists", filepath.Base(newPath)), browserWin)
+					return
+				}
+				renames = append(renames, [2]string{oldPath, newPath})
+			}
+			for _, r := range renames {
+				if err := os.Rename(r[0], r[1]); err != nil {
+					dialog.ShowError(fmt.Errorf("failed to rename %s: %w", filepath.Base(r[0]), err), browserWin)
+					break
+				}
+			}
+			reload()
+		}, browserWin)
+	}
+
+	list = widget.NewList(
+		func() int { return len(filtered) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil),
+					widget.NewButtonWithIcon("", theme.DeleteIcon(), nil),
+				),
+				widget.NewLabel(""))
+		},
+		func(i int, o fyne.CanvasObject) {
+			if i >= len(filtered) {
+				return
+			}
+			filename := filtered[i]
+			row := o.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(filename)
+			buttons := row.Objects[1].(*fyne.Container)
+			buttons.Objects[0].(*widget.Button).OnTapped = func() { renameLog(filename) }
+			buttons.Objects[1].(*widget.Button).OnTapped = func() { deleteLog(filename) }
+		},
+	)
+
+	list.OnSelected = func(id int) {
+		if id >= 0 && id < len(filtered) {
+			onSelect(filtered[id])
+			browserWin.Close()
+		}
+	}
+
+	searchEntry.OnChanged = func(string) {
+		applyFilter()
+	}
+
+	browserWin = fyne.CurrentApp().NewWindow("Open Log")
+	browserWin.SetContent(container.NewBorder(
+		searchEntry, nil, nil, nil,
+		container.NewVScroll(list),
+	))
+	browserWin.Resize(fyne.NewSize(400, 500))
+	browserWin.Show()
+}
+
+// pairedFeedFiles returns the feed file and any existing companion files sharing its base name.
+func pairedFeedFiles(feedDir, jsonName string) []string {
+	base := feedBaseName(jsonName)
+	paths := []string{filepath.Join(feedDir, jsonName)}
+	for _, ext := range []string{".txt", notesExt} {
+		path := filepath.Join(feedDir, base+ext)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// validateFeedName checks that a user-supplied log name is usable as a file name on all platforms.
+func validateFeedName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return fmt.Errorf("please enter a name")
+	}
+	if strings.HasSuffix(name, "_stats") {
+		return fmt.Errorf("names ending in _stats are reserved for statistics files")
+	}
+	if _, _, _, ok := parseFeedBase(name); !ok {
+		return fmt.Errorf("keep a _YYYY-MM-DD date in the name, e.g. Player_2024-05-01_notes; History only lists logs named that way")
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return fmt.Errorf("the name contains an invalid character: %q", r)
+		}
+	}
+	return nil
+}
+
+// offsetSaveInterval is how often the backfilled log offset is written to the preferences
+// while new lines keep arriving.
+const offsetSaveInterval = 10 * time.Second
+
+// listApplyDelay is how long typing in a list entry must pause before the list is applied.
+const listApplyDelay = time.Second
+
+// afterTyping wraps an OnChanged handler so it runs once, with the latest text, after typing has
+// paused for listApplyDelay instead of on every keystroke. Must be called on the UI thread.
+func afterTyping(apply func(text string)) func(string) {
+	changes := 0
+	return func(text string) {
+		changes++
+		id := changes
+		time.AfterFunc(listApplyDelay, func() {
+			fyne.Do(func() {
+				if changes == id {
+					apply(text)
+				}
+			})
+		})
+	}
+}
+
+// Added missing methods to logHandlerAdapter to implement watcher.LogHandler
+func (a *logHandlerAdapter) AppendOutput(line string) {
+	a.AppendOutputWithRaw(line, "")
+}
+
+func (a *logHandlerAdapter) AppendOutputWithRaw(line string, rawLogLine string) {
+	a.appendFeedLine(line, rawLogLine, false)
+}
+
+// appendFeedLine renders a line into the feed on the UI thread. Sample lines
+// are shown like any other but never saved, see feedEntry.sample.
+func (a *logHandlerAdapter) appendFeedLine(line, rawLogLine string, sample bool) {
+	fyne.Do(func() {
+		debugLog.Debug("feed line", "line", line, "raw", rawLogLine)
+
+		// Kill and death lines are colored using theme colors so they read well in light and dark mode
+		category := classifyFeedLine(line)
+		textStyle := widget.RichTextStyle{Inline: true, ColorName: categoryColor(category)}
+
+		// Create segments for this line with improved hyperlink logic
+		var segments []widget.RichTextSegment
+		// Enhanced player name detection for hyperlinks
+		words := strings.Fields(line)
+		
+		// Find "by" index for context-aware hyperlinking
+		byIdx := -1
+		for i, w := range words {
+			if strings.ToLower(w) == "by" && i < len(words)-1 {
+				byIdx = i + 1
+			}
+		}
+				for i, word := range words {
+			clean := strings.Trim(word, ",.?!;:'\"[]()")
+			shouldCreateHyperlink := false
+			displayText := word
+
+			// Enhanced hyperlinking logic - handle timestamped messages properly
+			if len(clean) >= 3 {
+				// Check various contexts where player names appear
+				// For kill messages, look for position after "killed:" word
+				killedIdx := -1
+				incapIdx := -1
+				for j, w := range words {
+					if strings.Contains(w, "killed:") {
+						killedIdx = j + 1
+					}
+					if strings.Contains(w, "incapacitated:") {
+						incapIdx = j + 1
+					}
+				}
+
+				if i == byIdx || // After "by"
+					i == killedIdx || // After "killed:"
+					i == incapIdx || // After "incapacitated:"
+					(strings.Contains(line, "corpse") && !strings.HasPrefix(line, "You")) || // In corpse messages (but not "You" messages)
+					(strings.Contains(line, "died") && i > 0 && strings.ToLower(words[i-1]) == "by") { // Deaths by player
+					shouldCreateHyperlink = shouldHyperlinkName(clean)
+				}
+			}
+
+			// Apply NPC/pet formatting even for non-hyperlinked names
+			if isNPCName(clean) {
+				displayText = strings.Replace(word, clean, formatNPCName(clean), 1)
+			} else if isPetName(clean) {
+				displayText = strings.Replace(word, clean, formatPetName(clean), 1)
+			}
+
+			if tag, ok := orgTagWord(word); ok {
+				segments = append(segments, &widget.HyperlinkSegment{
+					Text: word,
+					URL:  parseURL(orgURL(tag)),
+				})
+			} else if shouldCreateHyperlink {
+				segments = append(segments, &widget.HyperlinkSegment{
+					Text: markFriend(clean, displayText),
+					URL:  parseURL(citizenURL(clean)),
+				})
+			} else {
+				segments = append(segments, &widget.TextSegment{
+					Text:  displayText,
+					Style: textStyle,
+				})
+			}
+
+			if i < len(words)-1 {
+				segments = append(segments, &widget.TextSegment{
+					Text:  " ",
+					Style: textStyle,
+				})
+			}
+		}
+
+		// Add newline
+		segments = append(segments, &widget.TextSegment{
+			Text:  "\n",
+			Style: widget.RichTextStyle{Inline: true},
+		}) // Store in allSegments with raw log line
+		entry := feedEntry{segments: segments, rawLogLine: rawLogLine, category: category, info: isInfoLine(line), at: time.Now(), shard: a.proc.Shard, sample: sample}
+		if t, ok := processor.ParseTimestampPrefix(line); ok {
+			entry.at = t
+		}
+		a.storeEntry(entry)
+
+		debugLog.Debug("stored feed line", "stored", len(a.allSegments))
+
+		// Filtered-out lines are kept in allSegments but not rendered; the same
+		// applies while paused, and they are flushed on resume
+		if a.paused && a.onBuffered != nil {
+			a.onBuffered(len(a.allSegments) - a.pausedAt)
+		}
+		if !a.isVisible(entry) || a.paused {
+			if a.proc.PlayerName != "" && a.onStatsUpdate != nil {
+				a.onStatsUpdate(a.proc.PlayerName)
+			}
+			return
+		}
+
+		// Append to the rolling display window instead of calling refreshFeedDisplay
+		// This avoids performance issues and UI conflicts
+		a.appendToDisplay(entry)
+		debugLog.Debug("appended feed line", "segments", len(a.outputRich.Segments))
+
+		// Trigger stats update if we have a player name
+		if a.proc.PlayerName != "" && a.onStatsUpdate != nil {
+			a.onStatsUpdate(a.proc.PlayerName)
+		}
+	})
+}
+
+// DetectPlayerName method for logHandlerAdapter
+func (a *logHandlerAdapter) DetectPlayerName(line string) {
+	a.proc.DetectPlayerName(line)
+}
+
+// ResetPlayerDetection method for logHandlerAdapter
+func (a *logHandlerAdapter) ResetPlayerDetection() {
+	a.proc.ResetPlayerDetection()
+}
+
+// ProcessLogLine method for logHandlerAdapter
+func (a *logHandlerAdapter) ProcessLogLine(line string) {
+	a.proc.ProcessLogLine(line)
+}
+
+// Helper function to parse URL safely
+func parseURL(urlStr string) *url.URL {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// Helper function to check if a string looks like a valid player name
+func isValidPlayerName(name string) bool {
+	rules := currentNameRules()
+
+	// Player names are typically alphanumeric with underscores; length bounds come from the name rules
+	if len(name) < rules.MinLength || len(name) > rules.MaxLength {
+		return false
+	}
+
+	// Check for valid player name characters (letters, numbers, underscores,
+	// plus the rules' extra characters, which can't start or end a name)
+	for i, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_' {
+			continue
+		}
+		if !rules.allowsExtra(r) || i == 0 || i == len(name)-1 {
+			return false
+		}
+	}
+
+	// Avoid common non-player words, exactly or as part of the name
+	if rules.rejects(strings.ToLower(name)) {
+		return false
+	}
+
+	// Don't consider NPCs as valid player names
+	if isNPCName(name) {
+		return false
+	}
+
+	// Don't consider pets as valid player names
+	if isPetName(name) {
+		return false
+	}
+
+	// Don't consider system names as valid player names
+	if isSystemName(name) {
+		return false
+	}
+
+	return true
+}
+
+// IsValidPlayerName - exported version for testing
+func IsValidPlayerName(name string) bool {
+	return isValidPlayerName(name)
+}
+
+// Helper function to detect and format NPC names
+func isNPCName(name string) bool {
+	return stats.IsNPCName(name)
+}
+
+// IsNPCName - exported version for testing
+func IsNPCName(name string) bool {
+	return isNPCName(name)
+}
+
+// Helper function to detect and format pet names
+func isPetName(name string) bool {
+	return stats.IsPetName(name)
+}
+
+// IsPetName - exported version for testing
+func IsPetName(name string) bool {
+	return isPetName(name)
+}
+
+// Helper function to format NPC names (shorten to "NPC")
+func formatNPCName(name string) string {
+	if isNPCName(name) {
+		return "NPC"
+	}
+	return name
+}
+
+// FormatNPCName - exported version for testing
+func FormatNPCName(name string) string {
+	return formatNPCName(name)
+}
+
+// Helper function to format pet names (extract first part before underscore)
+func formatPetName(name string) string {
+	if isPetName(name) {
+		// Handle Pet_ prefix format
+		if strings.HasPrefix(name, "Pet_") {
+			parts := strings.Split(name, "_")
+			if len(parts) >= 2 {
+				return "NPC " + parts[1] // Get the part after Pet_
+			}
+		}
+		// Handle _pet_ format (e.g., Kopion_pet_123)
+		if strings.Contains(strings.ToLower(name), "_pet_") {
+			parts := strings.Split(name, "_")
+			if len(parts) > 0 {
+				return "NPC " + parts[0] // Get the first part
+			}
+		}
+	}
+	return name
+}
+
+// FormatPetName - exported version for testing
+func FormatPetName(name string) string {
+	return formatPetName(name)
+}
+
+// Helper function to check if a name should be hyperlinked
+func shouldHyperlinkName(name string) bool {
+	// Don't hyperlink suicide or environmental deaths
+	if processor.IsSelfDeathKiller(name) {
+		return false
+	}
+
+	// Don't hyperlink "unknown"
+	if strings.ToLower(name) == "unknown" {
+		return false
+	}
+
+	// Don't hyperlink if it's "SELF"
+	if strings.ToUpper(name) == "SELF" {
+		return false // SELF should not be hyperlinked for suicide cases
+	}
+
+	// Don't hyperlink NPC names
+	if isNPCName(name) {
+		return false
+	}
+
+	// Don't hyperlink pet names
+	if isPetName(name) {
+		return false // Pets should not be hyperlinked
+	}
+
+	// Only hyperlink if it's a valid player name
+	return isValidPlayerName(name)
+}
+
+// ShouldHyperlinkName - exported version for testing
+func ShouldHyperlinkName(name string) bool {
+	return shouldHyperlinkName(name)
+}
+
+// Helper function to check if a name is a system/weapon/vehicle name
+func isSystemName(name string) bool {
+	// Same environmental causes the processor keeps out of the kill and death counts
+	if processor.EnvironmentalCause(name) {
+		return true
+	}
+	systemNames := []string{
+		"collision", "fall", "suicide", "environment", "system", "server", "admin",
+		"ballistic", "energy", "missile", "torpedo", "cannon", "rifle",
+		"pistol", "shotgun", "sniper", "launcher", "turret", "shield",
+		"armor", "helmet", "suit", "vehicle", "ship", "quantum", "jump",
+		"unknown", // Add unknown as a system name too
+	}
+
+	lowerName := strings.ToLower(name)
+	for _, sys := range systemNames {
+		if strings.Contains(lowerName, sys) {
+			return true
+		}
+	}
+
+	// Check for NPC names
+	if isNPCName(name) {
+		return true
+	}
+
+	// Check for pet names
+	if isPetName(name) {
+		return true
+	}
+
+	return false
+}
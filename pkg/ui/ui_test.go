@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"html"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSplitVehicleMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantPrefix string
+		wantKiller string
+		wantWeapon string
+		wantOK     bool
+	}{
+		{"with weapon", "Vehicle ANVL_Arrow_1 was destroyed by Pilot_1 using Combat", "Vehicle ANVL_Arrow_1 was destroyed", "Pilot_1", "Combat", true},
+		{"no weapon", "Vehicle ANVL_Arrow_1 was destroyed by Pilot_1", "Vehicle ANVL_Arrow_1 was destroyed", "Pilot_1", "", true},
+		{"collision cause", "Vehicle ANVL_Arrow_1 was destroyed by Collision", "Vehicle ANVL_Arrow_1 was destroyed", "Collision", "", true},
+		{"disabled", "Vehicle ANVL_Arrow_1 was disabled by Pilot_1 using Combat", "Vehicle ANVL_Arrow_1 was disabled", "Pilot_1", "Combat", true},
+		{"name containing by", "Vehicle Drop by Ship was destroyed by Pilot_1 using Combat", "Vehicle Drop by Ship was destroyed", "Pilot_1", "Combat", true},
+		{"name containing using", "Vehicle Ship using Gear was destroyed by Pilot_1", "Vehicle Ship using Gear was destroyed", "Pilot_1", "", true},
+		{"trailing space", "Vehicle ANVL_Arrow_1 was destroyed by Pilot_1 using Combat ", "Vehicle ANVL_Arrow_1 was destroyed", "Pilot_1", "Combat", true},
+		{"trailing space without weapon", "Vehicle ANVL_Arrow_1 was destroyed by Pilot_1 ", "Vehicle ANVL_Arrow_1 was destroyed", "Pilot_1", "", true},
+		{"no killer", "Vehicle ANVL_Arrow_1 was destroyed by ", "", "", "", false},
+		{"no by", "Vehicle ANVL_Arrow_1 was destroyed", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, killer, weapon, ok := splitVehicleMessage(tt.line)
+			if prefix != tt.wantPrefix || killer != tt.wantKiller || weapon != tt.wantWeapon || ok != tt.wantOK {
+				t.Errorf("splitVehicleMessage(%q) = %q, %q, %q, %v; want %q, %q, %q, %v", tt.line,
+					prefix, killer, weapon, ok, tt.wantPrefix, tt.wantKiller, tt.wantWeapon, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCreateVehicleMessageSegments(t *testing.T) {
+	base := []FeedSegment{{Type: "text", Text: "10:00:00 "}}
+	tests := []struct {
+		name string
+		line string
+		want []FeedSegment
+	}{
+		{
+			name: "player with weapon",
+			line: "Vehicle ANVL_Arrow_1 was destroyed by Pilot_1 using Combat",
+			want: []FeedSegment{
+				{Type: "text", Text: "10:00:00 "},
+				{Type: "text", Text: "Vehicle Anvil Arrow was destroyed by "},
+				{Type: "hyperlink", Text: "Pilot_1", URL: citizenURL("Pilot_1")},
+				{Type: "text", Text: " using Combat"},
+				{Type: "text", Text: "\n"},
+			},
+		},
+		{
+			name: "collision",
+			line: "Vehicle ANVL_Arrow_1 was destroyed by Collision",
+			want: []FeedSegment{
+				{Type: "text", Text: "10:00:00 "},
+				{Type: "text", Text: "Vehicle Anvil Arrow was destroyed by "},
+				{Type: "text", Text: "Collision"},
+				{Type: "text", Text: "\n"},
+			},
+		},
+		{
+			name: "unparseable line kept as text",
+			line: "Vehicle ANVL_Arrow_1 was destroyed",
+			want: []FeedSegment{
+				{Type: "text", Text: "10:00:00 "},
+				{Type: "text", Text: "Vehicle ANVL_Arrow_1 was destroyed"},
+				{Type: "text", Text: "\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := createVehicleMessageSegments(tt.line, append([]FeedSegment(nil), base...))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segments = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKillSegmentsOrgTag(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []FeedSegment
+	}{
+		{
+			name: "with org",
+			line: "You killed: Pilot_1 [TEST] using Gallant Rifle",
+			want: []FeedSegment{
+				{Type: "text", Text: "10:00:00 "},
+				{Type: "text", Text: "You killed: "},
+				{Type: "hyperlink", Text: "Pilot_1", URL: citizenURL("Pilot_1")},
+				{Type: "text", Text: " ["},
+				{Type: "hyperlink", Text: "TEST", URL: orgURL("TEST")},
+				{Type: "text", Text: "]"},
+				{Type: "text", Text: " using Gallant Rifle"},
+				{Type: "text", Text: "\n"},
+			},
+		},
+		{
+			name: "without org",
+			line: "You killed: Pilot_1 using Gallant Rifle",
+			want: []FeedSegment{
+				{Type: "text", Text: "10:00:00 "},
+				{Type: "text", Text: "You killed: "},
+				{Type: "hyperlink", Text: "Pilot_1", URL: citizenURL("Pilot_1")},
+				{Type: "text", Text: " using Gallant Rifle"},
+				{Type: "text", Text: "\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreateEnhancedSegments(tt.line, "10:00:00", "Me"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segments = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// htmlLinePattern and htmlPartPattern read back what renderFeedHTML writes.
+var (
+	htmlLinePattern = regexp.MustCompile(`<div class='line'>(.*)</div>`)
+	htmlPartPattern = regexp.MustCompile(`<a href="([^"]*)">([^<]*)</a>|([^<]+)`)
+)
+
+func TestRenderFeedHTMLRoundTrip(t *testing.T) {
+	lines := [][]FeedSegment{
+		{
+			{Type: "text", Text: "10:00:00 You killed: "},
+			{Type: "hyperlink", Text: "Pilot_1", URL: citizenURL("Pilot_1")},
+			{Type: "text", Text: " ["},
+			{Type: "hyperlink", Text: "TEST", URL: orgURL("TEST")},
+			{Type: "text", Text: "] using Klaus & Werner <Gallant> Rifle\n"},
+		},
+		{{Type: "text", Text: "10:00:05 Monitoring: C:\\Games\\\"StarCitizen\"\\Game.log\n"}},
+		{{Type: "text", Text: "\n"}}, // empty lines are left out
+		{
+			{Type: "text", Text: "10:00:09 You were killed by: "},
+			{Type: "hyperlink", Text: "<b>Sneaky</b>", URL: `https://example.com/?a=1&b="x"`},
+			{Type: "text", Text: "\n"},
+		},
+	}
+	type link struct{ text, url string }
+	type line struct {
+		text  string
+		links []link
+	}
+	want := []line{
+		{"10:00:00 You killed: Pilot_1 [TEST] using Klaus & Werner <Gallant> Rifle",
+			[]link{{"Pilot_1", citizenURL("Pilot_1")}, {"TEST", orgURL("TEST")}}},
+		{`10:00:05 Monitoring: C:\Games\"StarCitizen"\Game.log`, nil},
+		{"10:00:09 You were killed by: <b>Sneaky</b>",
+			[]link{{"<b>Sneaky</b>", "https://example.com/?a=1&b=%22x%22"}}},
+	}
+
+	page := renderFeedHTML(lines)
+	var got []line
+	for _, m := range htmlLinePattern.FindAllStringSubmatch(page, -1) {
+		var l line
+		for _, part := range htmlPartPattern.FindAllStringSubmatch(m[1], -1) {
+			if part[3] != "" {
+				l.text += html.UnescapeString(part[3])
+				continue
+			}
+			text := html.UnescapeString(part[2])
+			l.text += text
+			l.links = append(l.links, link{text, html.UnescapeString(part[1])})
+		}
+		got = append(got, l)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read back %+v, want %+v", got, want)
+	}
+	if strings.Contains(page, "<b>") || strings.Contains(page, "<Gallant>") {
+		t.Error("markup in feed text wasn't escaped")
+	}
+}
+
+func TestShouldHyperlinkName(t *testing.T) {
+	_ = withNameRules(t, "")
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Pilot_One", true},
+		{"Pilot-One", true},
+		{"J.Doe_77", true},
+		{"a-b.c-d", true},
+		{"-Pilot", false},
+		{"Pilot.", false},
+		{"Pilot One", false},
+		{"Pilot/One", false},
+		{"klwe_rifle_energy_01", false},
+		{"behr_pistol_ballistic_01", false},
+		{"Collision", false},
+		{"unknown", false},
+		{"SELF", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldHyperlinkName(tt.name); got != tt.want {
+				t.Errorf("shouldHyperlinkName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCitizenURL(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pilot-One", defaultRSIBaseURL + "citizens/Pilot-One"},
+		{"J.Doe_77", defaultRSIBaseURL + "citizens/J.Doe_77"},
+		{"a/b c", defaultRSIBaseURL + "citizens/a%2Fb%20c"},
+	}
+	for _, tt := range tests {
+		if got := citizenURL(tt.name); got != tt.want {
+			t.Errorf("citizenURL(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"game-monitor/pkg/watcher"
+
+	"fyne.io/fyne/v2"
+)
+
+// OnProgress passes the watcher's progress to onProgress on the UI thread.
+func (a *logHandlerAdapter) OnProgress(p watcher.Progress) {
+	if a.onProgress == nil {
+		return
+	}
+	fyne.Do(func() { a.onProgress(p) })
+}
+
+// progressText describes watcher progress for the Feed tab's status line: how
+// much of the log has been read, how many lines, and whether the watcher is
+// caught up, still reading or waiting for the file.
+func progressText(p watcher.Progress, now time.Time) string {
+	name := filepath.Base(p.Path)
+	if p.Missing {
+		return fmt.Sprintf("%s: waiting for the file to come back • %d lines read", name, p.Lines)
+	}
+	text := fmt.Sprintf("%s: read %s of %s • %d lines", name, formatBytes(p.Offset), formatBytes(p.Size), p.Lines)
+	if behind := p.Size - p.Offset; behind > 0 {
+		return text + fmt.Sprintf(" • %s behind", formatBytes(behind))
+	}
+	if p.LastRead.IsZero() {
+		return text + " • at end of file, no lines yet"
+	}
+	return text + fmt.Sprintf(" • at end of file, last new line %s ago", now.Sub(p.LastRead).Round(time.Second))
+}
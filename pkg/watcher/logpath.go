@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// scInstallDir is the game's folder inside a Windows drive or Wine prefix.
+var scInstallDir = filepath.Join("Roberts Space Industries", "StarCitizen")
+
+// gameChannels are the release channels checked, in order of preference.
+var gameChannels = []string{"LIVE", "PTU", "EPTU", "TECH-PREVIEW"}
+
+// candidateInstallDirs returns the StarCitizen folders to look in on this OS.
+// Linux players run the game through Wine (Lutris, Proton or a plain prefix)
+// and macOS players through CrossOver, so those prefixes are searched there.
+func candidateInstallDirs() []string {
+	var dirs []string
+	switch runtime.GOOS {
+	case "windows":
+		for _, base := range []string{os.Getenv("ProgramFiles"), `C:\Program Files`, `D:\Program Files`, `D:\`, `E:\`} {
+			if base != "" {
+				dirs = append(dirs, filepath.Join(base, scInstallDir))
+			}
+		}
+	default:
+		home, _ := os.UserHomeDir()
+		var prefixes []string
+		if prefix := os.Getenv("WINEPREFIX"); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+		if home != "" {
+			prefixes = append(prefixes,
+				filepath.Join(home, "Games", "star-citizen"), // Lutris installer default
+				filepath.Join(home, ".wine"),
+			)
+			bottles, _ := filepath.Glob(filepath.Join(home, "Library", "Application Support", "CrossOver", "Bottles", "*"))
+			prefixes = append(prefixes, bottles...)
+			lutris, _ := filepath.Glob(filepath.Join(home, "Games", "*"))
+			prefixes = append(prefixes, lutris...)
+		}
+		for _, prefix := range prefixes {
+			dirs = append(dirs, filepath.Join(prefix, "drive_c", "Program Files", scInstallDir))
+		}
+	}
+	return dirs
+}
+
+// DetectDefaultLogPath returns the first Game.log found in the usual install
+// locations for this OS, or "" if there is none.
+func DetectDefaultLogPath() string {
+	for _, dir := range candidateInstallDirs() {
+		for _, channel := range gameChannels {
+			for _, name := range []string{"Game.log", "game.log"} {
+				path := filepath.Join(dir, channel, name)
+				if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+					return path
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// LogPathPlaceholder describes where Game.log usually lives on this OS.
+func LogPathPlaceholder() string {
+	switch runtime.GOOS {
+	case "windows":
+		return `Path to your Roberts Space Industries\StarCitizen\LIVE\Game.log file`
+	case "darwin":
+		return "Path to Game.log in your CrossOver bottle (drive_c/Program Files/Roberts Space Industries/StarCitizen/LIVE)"
+	default:
+		return "Path to Game.log in your Wine prefix (drive_c/Program Files/Roberts Space Industries/StarCitizen/LIVE)"
+	}
+}
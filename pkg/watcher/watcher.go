@@ -1,88 +1,305 @@
-package watcher
-
-import (
-	"bufio"
-	"io"
-	"os"
-	"path/filepath"
-	"time"
-
-	"fyne.io/fyne/v2"
-)
-
-// LogHandler defines the interface the watcher uses to feed log lines.
-type LogHandler interface {
-	DetectPlayerName(line string)
-	ProcessLogLine(line string)
-	AppendOutput(line string)
-}
-
-// WatchLogFile tails the game log at the given path using polling.
-func WatchLogFile(path string, proc LogHandler) {
-	// Normalize and clean the path
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		proc.AppendOutput("failed to get absolute path: " + err.Error())
-		return
-	}
-	absPath = filepath.Clean(absPath)
-
-	// Open the log file
-	file, err := os.Open(absPath)
-	if err != nil {
-		proc.AppendOutput("failed to open log file: " + err.Error())
-		return
-	}
-	defer file.Close()
-
-	// Initial scan: detect player name only, with large buffer for long lines
-	scanner := bufio.NewScanner(file)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 10*1024*1024)
-	for scanner.Scan() {
-		proc.DetectPlayerName(scanner.Text())
-	}	// Seek to end for new data
-	offset, _ := file.Seek(0, io.SeekCurrent)
-
-	// Poll for changes every 500ms (half second)
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		// Check file stat
-		info, err := os.Stat(absPath)
-		if err != nil {
-			// File might have been moved/deleted, try to reopen
-			file.Close()
-			time.Sleep(100 * time.Millisecond)
-			
-			file, err = os.Open(absPath)
-			if err != nil {
-				continue
-			}
-			offset = 0
-			continue
-		}
-
-		// Check for truncation
-		if info.Size() < offset {
-			offset = 0
-		}
-
-		// Check if file has new content
-		if info.Size() > offset {
-			// Read new lines with large buffer
-			file.Seek(offset, io.SeekStart)
-			scanner2 := bufio.NewScanner(file)
-			scanner2.Buffer(buf, 10*1024*1024)
-			for scanner2.Scan() {
-				line := scanner2.Text()
-				fyne.Do(func() { 
-					proc.DetectPlayerName(line)
-					proc.ProcessLogLine(line) 
-				})
-			}
-			offset, _ = file.Seek(0, io.SeekCurrent)
-		}
-	}
-}
+package watcher
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// LogHandler defines the interface the watcher uses to feed log lines.
+type LogHandler interface {
+	DetectPlayerName(line string)
+	ProcessLogLine(line string)
+	AppendOutput(line string)
+}
+
+// PlayerRedetector is implemented by handlers that can detect the player
+// afresh. The watcher calls it on the UI thread when the log is replaced by a
+// new file or returns after being gone for a while, since either is likely a
+// new game session.
+type PlayerRedetector interface {
+	ResetPlayerDetection()
+}
+
+// Progress is how far the watcher has got through the log, for showing
+// whether it's keeping up or waiting at the end of the file.
+type Progress struct {
+	Path     string    // absolute path of the log
+	Size     int64     // size of the log at the last check
+	Offset   int64     // bytes read so far
+	Lines    int       // lines read since the watcher started
+	LastRead time.Time // when new lines were last read, zero if none yet
+	Missing  bool      // the log is gone and the watcher is waiting for it
+}
+
+// ProgressReporter is implemented by handlers that show the watcher's
+// progress. OnProgress is called from the watcher's goroutine after the
+// initial scan and on every poll after that.
+type ProgressReporter interface {
+	OnProgress(p Progress)
+}
+
+// Reopen backoff: after the log disappears, reopening is retried with a delay
+// doubling from minRetryDelay up to maxRetryDelay. A feed notice goes out when
+// the first retry fails and then every waitingNoticeInterval.
+const (
+	minRetryDelay         = 500 * time.Millisecond
+	maxRetryDelay         = 30 * time.Second
+	waitingNoticeInterval = time.Minute
+	// redetectAfter is how long the log has to be gone before the player is detected again
+	redetectAfter = time.Minute
+)
+
+// Options controls how WatchLogFile treats the content already in the log.
+type Options struct {
+	// Backfill runs ProcessLogLine over the existing lines before tailing,
+	// instead of only scanning them for the player name.
+	Backfill bool
+	// SkipTo is a byte offset already processed by an earlier backfill of
+	// the same log; lines before it are only used for player detection so
+	// they aren't counted twice.
+	SkipTo int64
+	// OnOffset, if set, is called with the read offset after the initial
+	// scan and after every batch of new lines, along with the LogID of the
+	// file it belongs to, which changes when the game starts a new log.
+	OnOffset func(logID string, offset int64)
+	// OnBackfillDone, if set, is called on the UI thread once the existing
+	// lines have been processed.
+	OnBackfillDone func()
+	// Context, if set, stops the watcher when it is cancelled.
+	Context context.Context
+}
+
+// WatchLogFile tails the game log at the given path using polling.
+func WatchLogFile(path string, proc LogHandler) {
+	WatchLogFileWithOptions(path, proc, Options{})
+}
+
+// WatchLogFileWithOptions tails the game log like WatchLogFile, optionally
+// backfilling the existing content first.
+func WatchLogFileWithOptions(path string, proc LogHandler, opts Options) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Normalize and clean the path
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		proc.AppendOutput("failed to get absolute path: " + err.Error())
+		return
+	}
+	absPath = filepath.Clean(absPath)
+
+	// Open the log file
+	file, err := os.Open(absPath)
+	if err != nil {
+		proc.AppendOutput("failed to open log file: " + err.Error())
+		return
+	}
+	defer func() { file.Close() }() // file is replaced when the log is reopened
+	logID := fileLogID(file)
+
+	// Oversized lines are skipped with a note in the feed instead of stopping the watcher
+	warnTooLong := func(size int) {
+		proc.AppendOutput(fmt.Sprintf("Skipped a log line of %d bytes (longer than %d bytes)", size, maxLineLength))
+	}
+
+	progress := Progress{Path: absPath}
+	reportProgress := func() {
+		if r, ok := proc.(ProgressReporter); ok {
+			r.OnProgress(progress)
+		}
+	}
+
+	// Initial scan: detect player name only.
+	// In backfill mode every line past SkipTo is processed as well.
+	offset := readLines(ctx, file, maxLineLength, func(line string, end int64) {
+		progress.Lines++
+		if opts.Backfill && end > opts.SkipTo {
+			fyne.Do(func() {
+				proc.DetectPlayerName(line)
+				proc.ProcessLogLine(line)
+			})
+			return
+		}
+		proc.DetectPlayerName(line)
+	}, warnTooLong)
+	if ctx.Err() != nil {
+		return
+	}
+	if opts.Backfill && opts.OnBackfillDone != nil {
+		fyne.Do(opts.OnBackfillDone)
+	}	// Continue from the end for new data
+	if opts.OnOffset != nil {
+		opts.OnOffset(logID, offset)
+	}
+	progress.Offset = offset
+	if info, err := file.Stat(); err == nil {
+		progress.Size = info.Size()
+	}
+	if progress.Lines > 0 {
+		progress.LastRead = time.Now()
+	}
+	reportProgress()
+
+	// Poll for changes every 500ms (half second)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	// While the log is missing: when it went, when to retry and when the last notice went out
+	var missingSince, nextRetry, lastNotice time.Time
+	retryDelay := minRetryDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Reported at the top so every way the previous poll ended is covered
+		progress.Offset, progress.Missing = offset, !missingSince.IsZero()
+		reportProgress()
+		if !missingSince.IsZero() {
+			now := time.Now()
+			if now.Before(nextRetry) {
+				continue
+			}
+			reopened, err := os.Open(absPath)
+			if err != nil {
+				retryDelay = min(retryDelay*2, maxRetryDelay)
+				nextRetry = now.Add(retryDelay)
+				if now.Sub(lastNotice) >= waitingNoticeInterval {
+					proc.AppendOutput(fmt.Sprintf("Waiting for log file %s (missing for %s)...", absPath, now.Sub(missingSince).Round(time.Second)))
+					lastNotice = now
+				}
+				continue
+			}
+			file = reopened
+			offset = 0
+			logID = fileLogID(file)
+			if now.Sub(missingSince) >= redetectAfter {
+				if r, ok := proc.(PlayerRedetector); ok {
+					fyne.Do(r.ResetPlayerDetection)
+				}
+			}
+			if !lastNotice.IsZero() {
+				proc.AppendOutput("Log file is back, resuming: " + absPath)
+			}
+			missingSince, lastNotice, retryDelay = time.Time{}, time.Time{}, minRetryDelay
+		}
+		// Check file stat
+		info, err := os.Stat(absPath)
+		if err != nil {
+			// File might have been moved/deleted; reopen with backoff from the next tick
+			file.Close()
+			missingSince, nextRetry = time.Now(), time.Time{}
+			continue
+		}
+		progress.Size = info.Size()
+
+		// A relaunched game writes a new log at the same path; switch to it and detect the player again
+		if current, err := file.Stat(); err == nil && !os.SameFile(current, info) {
+			if reopened, err := os.Open(absPath); err == nil {
+				file.Close()
+				file = reopened
+				offset = 0
+				logID = fileLogID(file)
+				if r, ok := proc.(PlayerRedetector); ok {
+					fyne.Do(r.ResetPlayerDetection)
+				}
+			}
+		}
+
+		// Check for truncation
+		if info.Size() < offset {
+			offset = 0
+		}
+
+		// Check if file has new content
+		if info.Size() > offset {
+			// Read new lines
+			file.Seek(offset, io.SeekStart)
+			offset += readLines(ctx, file, maxLineLength, func(line string, _ int64) {
+				progress.Lines++
+				fyne.Do(func() { 
+					proc.DetectPlayerName(line)
+					proc.ProcessLogLine(line) 
+				})
+			}, warnTooLong)
+			if opts.OnOffset != nil {
+				opts.OnOffset(logID, offset)
+			}
+			progress.LastRead = time.Now()
+		}
+	}
+}
+
+// maxLineLength bounds a single log line. Longer lines are skipped rather than buffered.
+const maxLineLength = 10 * 1024 * 1024
+
+// readLines calls onLine for every line in r, with the byte offset just past
+// the line relative to where reading started, and returns the number of bytes
+// consumed. Lines longer than maxLen are dropped and reported to onTooLong
+// instead of stopping the read the way bufio.Scanner does with ErrTooLong.
+// A final line without a newline is delivered too. Reading stops early once
+// ctx is cancelled.
+func readLines(ctx context.Context, r io.Reader, maxLen int, onLine func(line string, end int64), onTooLong func(size int)) int64 {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var consumed int64
+	var buf []byte
+	size := 0
+	for {
+		chunk, err := br.ReadSlice('\n')
+		consumed += int64(len(chunk))
+		size += len(chunk)
+		if size > maxLen {
+			buf = buf[:0]
+		} else {
+			buf = append(buf, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if size > maxLen {
+			onTooLong(size)
+		} else if size > 0 {
+			onLine(strings.TrimRight(string(buf), "\r\n"), consumed)
+		}
+		buf, size = buf[:0], 0
+		if err != nil || ctx.Err() != nil {
+			return consumed
+		}
+	}
+}
+
+// LogID returns the first line of the log, which records when the game
+// started writing it. A recreated game.log gets a new ID even at the same path.
+func LogID(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	return readLogID(file)
+}
+
+// fileLogID returns the LogID of an open log without moving its read offset.
+func fileLogID(file *os.File) string {
+	return readLogID(io.NewSectionReader(file, 0, maxLineLength))
+}
+
+// readLogID returns the first line of r.
+func readLogID(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	if scanner.Scan() {
+		return scanner.Text()
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+)
+
+// recordingHandler is a LogHandler that keeps the lines it's given.
+type recordingHandler struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (h *recordingHandler) DetectPlayerName(string) {}
+func (h *recordingHandler) AppendOutput(string)     {}
+func (h *recordingHandler) ProcessLogLine(line string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lines = append(h.lines, line)
+}
+
+func TestOnOffsetFollowsNewLog(t *testing.T) {
+	test.NewTempApp(t)
+	path := filepath.Join(t.TempDir(), "Game.log")
+	if err := os.WriteFile(path, []byte("first log\nkill\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var lastID string
+	var lastOffset int64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchLogFileWithOptions(path, &recordingHandler{}, Options{
+		Context: ctx,
+		OnOffset: func(logID string, offset int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			lastID, lastOffset = logID, offset
+		},
+	})
+	time.Sleep(200 * time.Millisecond)
+
+	// The game relaunching writes a new file at the same path
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("second log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		id, offset := lastID, lastOffset
+		mu.Unlock()
+		if id == "second log" {
+			if offset != int64(len("second log\n")) {
+				t.Errorf("offset = %d, want %d", offset, len("second log\n"))
+			}
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("offset never reported for the new log; last ID %q", lastID)
+}
+
+func TestReadLinesStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var lines []string
+	consumed := readLines(ctx, strings.NewReader("one\ntwo\nthree\n"), maxLineLength, func(line string, _ int64) {
+		lines = append(lines, line)
+		if line == "two" {
+			cancel()
+		}
+	}, func(int) {})
+	if len(lines) != 2 {
+		t.Errorf("lines = %q, want reading to stop after \"two\"", lines)
+	}
+	if consumed != int64(len("one\ntwo\n")) {
+		t.Errorf("consumed = %d, want %d", consumed, len("one\ntwo\n"))
+	}
+}
+
+func TestReadLinesRecoversAfterOversizedLine(t *testing.T) {
+	const maxLen = 16
+	tests := []struct {
+		name      string
+		input     string
+		wantLines []string
+		wantSkips []int
+	}{
+		{"short lines", "one\ntwo\n", []string{"one", "two"}, nil},
+		{"oversized line skipped", "one\n" + strings.Repeat("x", 40) + "\ntwo\n", []string{"one", "two"}, []int{41}},
+		{"oversized last line without newline", "one\n" + strings.Repeat("x", 40), []string{"one"}, []int{40}},
+		{"line at the limit kept", strings.Repeat("y", maxLen-1) + "\nz\n", []string{strings.Repeat("y", maxLen-1), "z"}, nil},
+		{"crlf endings", "one\r\ntwo\r\n", []string{"one", "two"}, nil},
+		{"two oversized lines", strings.Repeat("x", 20) + "\n" + strings.Repeat("x", 30) + "\nok\n", []string{"ok"}, []int{21, 31}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			var skips []int
+			consumed := readLines(context.Background(), strings.NewReader(tt.input), maxLen, func(line string, _ int64) {
+				lines = append(lines, line)
+			}, func(size int) {
+				skips = append(skips, size)
+			})
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("lines = %q, want %q", lines, tt.wantLines)
+			}
+			if !reflect.DeepEqual(skips, tt.wantSkips) {
+				t.Errorf("skipped sizes = %v, want %v", skips, tt.wantSkips)
+			}
+			if consumed != int64(len(tt.input)) {
+				t.Errorf("consumed = %d, want %d", consumed, len(tt.input))
+			}
+		})
+	}
+}
+
+func TestReadLinesOversizedAcrossBuffer(t *testing.T) {
+	// Longer than bufio's 64 KiB buffer, so the line arrives in several chunks
+	input := strings.Repeat("x", 200*1024) + "\nafter\n"
+	var lines []string
+	skipped := 0
+	readLines(context.Background(), strings.NewReader(input), 100*1024, func(line string, _ int64) {
+		lines = append(lines, line)
+	}, func(int) { skipped++ })
+	if skipped != 1 || !reflect.DeepEqual(lines, []string{"after"}) {
+		t.Errorf("lines = %q with %d skipped, want [after] with 1 skipped", lines, skipped)
+	}
+}